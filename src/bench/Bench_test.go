@@ -0,0 +1,169 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/maps"
+
+	_ "go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/BiAStarFinder"
+	_ "go-PathFinding/finders/DijkstraFinder"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+const arena = `type octile
+height 4
+width 5
+map
+.....
+.@@@.
+.@...
+.....
+`
+
+func TestRunScenarios(t *testing.T) {
+	grid, err := maps.ReadMovingAIMap(strings.NewReader(arena))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scenarios := []maps.TScenario{
+		{MapWidth: 5, MapHeight: 4, StartX: 0, StartY: 0, GoalX: 4, GoalY: 3, Optimal: 7},
+		// no diagonal squeezes past the corners of the wall.
+		{MapWidth: 5, MapHeight: 4, StartX: 2, StartY: 2, GoalX: 0, GoalY: 0, Optimal: 6},
+		{MapWidth: 5, MapHeight: 4, StartX: 2, StartY: 2, GoalX: 4, GoalY: 3, Optimal: 2.41421356},
+		{MapWidth: 5, MapHeight: 4, StartX: 0, StartY: 0, GoalX: 0, GoalY: 0, Optimal: 0},
+	}
+	for _, name := range []string{"astar", "biastar"} {
+		report, err := RunScenarios(grid, scenarios, name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		akLog.FmtPrintln(name, ": ", report.Solved, report.Optimal, report.MaxSuboptimality)
+		if report.Solved != 4 || report.Optimal != 4 || report.Results[0].Closed == 0 {
+			t.Fatalf("%s: %+v", name, report)
+		}
+	}
+
+	wrong := []maps.TScenario{{MapWidth: 5, MapHeight: 4, GoalX: 4, GoalY: 3, Optimal: 9}}
+	if report, err := RunScenarios(grid, wrong, "astar", nil); err != nil || report.Shorter != 1 || report.Optimal != 0 {
+		t.Fatalf("wrong optimum not reported: %+v %v", report, err)
+	}
+	wrong[0].MapWidth = 6
+	if _, err := RunScenarios(grid, wrong, "astar", nil); err == nil {
+		t.Fatalf("scenario of another map accepted")
+	}
+}
+
+// a 32x32 grid with a fifth of its cells blocked.
+func randomGrid(seed int64) *core.TGrid {
+	random := rand.New(rand.NewSource(seed))
+	matrix := make(core.DoubleInt32, 32)
+	for y := range matrix {
+		matrix[y] = make(core.ArrayInt32, 32)
+		for x := range matrix[y] {
+			if random.Intn(5) == 0 {
+				matrix[y][x] = 1
+			}
+		}
+	}
+	return core.Grid(32, 32, matrix)
+}
+
+func TestSuite(t *testing.T) {
+	grid := randomGrid(7)
+	cases := []TCase{{Name: "random", Grid: grid, Queries: RandomQueries(grid, 20, 1)}}
+	report, err := NewSuite("dijkstra", "astar", "biastar").Run(cases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Samples) != 60 {
+		t.Fatalf("%d samples, expected 60", len(report.Samples))
+	}
+	for _, summary := range report.Summaries() {
+		akLog.FmtPrintln(summary)
+	}
+	for _, s := range report.Samples {
+		if s.Missed || s.Found && (s.Suboptimality < 1-1e-9 || (s.Finder == "dijkstra" && s.Suboptimality != 1)) {
+			t.Fatalf("sample %+v", s)
+		}
+		if s.Found && s.Expansions == 0 {
+			t.Fatalf("no expansion recorded: %+v", s)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil || len(rows) != 61 || rows[0][0] != "finder" {
+		t.Fatalf("CSV of %d rows: %v", len(rows), err)
+	}
+
+	out.Reset()
+	if err := report.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	base, err := ReadJSON(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if regressions := Compare(base, report, DefaultThresholds); len(regressions) != 0 {
+		t.Fatalf("run regresses against itself: %v", regressions)
+	}
+
+	// a head doing more work and finding longer paths.
+	head := &TReport{Samples: append([]TSample{}, report.Samples...)}
+	head.Samples[3].Expansions = head.Samples[3].Expansions*2 + 1
+	head.Samples[4].Cost += 1
+	regressions := Compare(base, head, DefaultThresholds)
+	akLog.FmtPrintln("regressions: ", regressions)
+	if len(regressions) != 2 || regressions[0].Metric != "expansions" || regressions[1].Metric != "cost" {
+		t.Fatalf("regressions %v", regressions)
+	}
+
+	// a fast head is never slow, however fast the base was.
+	fast := TSample{Finder: "astar", Found: true, Nanos: 10}
+	slow := fast
+	slow.Nanos = DefaultThresholds.MinNanos - 1
+	if regressions := Compare(&TReport{Samples: []TSample{fast}}, &TReport{Samples: []TSample{slow}}, DefaultThresholds); len(regressions) != 0 {
+		t.Fatalf("regressions under MinNanos: %v", regressions)
+	}
+}
+
+func TestSummariesMissed(t *testing.T) {
+	report := &TReport{Samples: []TSample{
+		{Finder: "astar", Query: 0, Found: true, Cost: 12, Optimal: 10, Suboptimality: 1.2},
+		{Finder: "astar", Query: 1, Found: false, Optimal: 10, Missed: true},
+		{Finder: "astar", Query: 2, Found: false},
+	}}
+	summaries := report.Summaries()
+	if len(summaries) != 1 || summaries[0].Found != 1 || summaries[0].Missed != 1 || summaries[0].MaxSuboptimality != 1.2 {
+		t.Fatalf("summaries %+v", summaries)
+	}
+}
+
+func BenchmarkFinders(b *testing.B) {
+	grid := randomGrid(7)
+	queries := RandomQueries(grid, 50, 1)
+	for _, name := range []string{"dijkstra", "astar", "biastar"} {
+		finder, err := finders.Create(name, MovingAIOpt())
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				q := queries[i%len(queries)]
+				finder.FindPath(grid.NodeID(q.StartX, q.StartY), grid.NodeID(q.GoalX, q.GoalY), grid)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package bench
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+/**
+ * Samples of a benchmark run.
+ */
+type TReport struct {
+	Samples []TSample `json:"samples"`
+}
+
+var csvHeader = []string{
+	"finder", "map", "query", "found", "expansions", "opened", "nanos",
+	"allocs", "bytes", "cost", "optimal", "suboptimality", "missed",
+}
+
+/**
+ * Write one CSV row per sample, after a header row.
+ */
+func (this *TReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, s := range this.Samples {
+		cw.Write([]string{
+			s.Finder, s.Map, strconv.Itoa(s.Query), strconv.FormatBool(s.Found),
+			strconv.Itoa(s.Expansions), strconv.Itoa(s.Opened), strconv.FormatInt(s.Nanos, 10),
+			strconv.FormatUint(s.Allocs, 10), strconv.FormatUint(s.Bytes, 10),
+			strconv.FormatFloat(s.Cost, 'g', -1, 64), strconv.FormatFloat(s.Optimal, 'g', -1, 64),
+			strconv.FormatFloat(s.Suboptimality, 'g', -1, 64), strconv.FormatBool(s.Missed),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (this *TReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(this)
+}
+
+func ReadJSON(r io.Reader) (*TReport, error) {
+	var report TReport
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("bench: %v", err)
+	}
+	return &report, nil
+}
+
+/**
+ * How much worse a measure may get before Compare flags it.
+ * Ratios apply to the base value: 1.2 lets it grow by 20%.
+ * Head times under MinNanos are too noisy to be compared.
+ */
+type TThresholds struct {
+	Expansions float64
+	Time       float64
+	MinNanos   int64
+	Allocs     float64
+	Cost       float64 // absolute
+}
+
+var DefaultThresholds = TThresholds{
+	Expansions: 1,
+	Time:       1.5,
+	MinNanos:   50000,
+	Allocs:     1.2,
+	Cost:       1e-9,
+}
+
+/**
+ * A measure that got worse between two runs.
+ */
+type TRegression struct {
+	Finder string
+	Map    string
+	Query  int
+	Metric string
+	Base   float64
+	Head   float64
+}
+
+func (this TRegression) String() string {
+	return fmt.Sprintf("%s on %s query %d: %s %g -> %g", this.Finder, this.Map, this.Query, this.Metric, this.Base, this.Head)
+}
+
+type sampleKey struct {
+	finder  string
+	mapName string
+	query   int
+}
+
+/**
+ * Compare head with base, sample by sample, and return the regressions.
+ * Samples only present in one of the runs are ignored.
+ */
+func Compare(base, head *TReport, thresholds TThresholds) []TRegression {
+	var before = map[sampleKey]TSample{}
+	for _, s := range base.Samples {
+		before[sampleKey{s.Finder, s.Map, s.Query}] = s
+	}
+
+	var regressions []TRegression
+	for _, h := range head.Samples {
+		b, ok := before[sampleKey{h.Finder, h.Map, h.Query}]
+		if !ok {
+			continue
+		}
+		flag := func(metric string, base, head float64) {
+			regressions = append(regressions, TRegression{h.Finder, h.Map, h.Query, metric, base, head})
+		}
+		if b.Found && !h.Found {
+			flag("found", 1, 0)
+			continue
+		}
+		if h.Cost > b.Cost+thresholds.Cost {
+			flag("cost", b.Cost, h.Cost)
+		}
+		if float64(h.Expansions) > float64(b.Expansions)*thresholds.Expansions {
+			flag("expansions", float64(b.Expansions), float64(h.Expansions))
+		}
+		if float64(h.Allocs) > math.Max(1, float64(b.Allocs))*thresholds.Allocs {
+			flag("allocs", float64(b.Allocs), float64(h.Allocs))
+		}
+		if h.Nanos >= thresholds.MinNanos && float64(h.Nanos) > float64(b.Nanos)*thresholds.Time {
+			flag("nanos", float64(b.Nanos), float64(h.Nanos))
+		}
+	}
+	return regressions
+}
@@ -0,0 +1,116 @@
+package bench
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/maps"
+)
+
+/**
+ * Outcome of one scenario.
+ */
+type TScenarioResult struct {
+	Scenario      maps.TScenario
+	Found         bool
+	Cost          float64
+	Suboptimality float64 // Cost / Optimal, 1 for an optimal path
+	Opened        int
+	Closed        int
+	Elapsed       time.Duration
+}
+
+/**
+ * Outcome of a scenario file.
+ */
+type TScenarioReport struct {
+	Results          []TScenarioResult
+	Solved           int // path found
+	Optimal          int // path found, as short as the scenario says
+	Shorter          int // path found, shorter than the scenario says
+	MaxSuboptimality float64
+	Elapsed          time.Duration
+}
+
+/**
+ * Relative difference below which a cost equals the scenario optimum;
+ * the .scen files round it to 8 decimals.
+ */
+const Tolerance = 1e-6
+
+/**
+ * Options of the MovingAI benchmarks: 8 directions without cutting
+ * corners, with the octile heuristic.
+ */
+func MovingAIOpt() *core.Opt {
+	return &core.Opt{
+		DiagonalMovement: core.OnlyWhenNoObstacles,
+		Heuristic:        core.Octile,
+	}
+}
+
+/**
+ * Run every scenario through the finder registered under name and
+ * compare each path cost with the scenario optimum.
+ * @param {Object} config - Finder options by name, as given to
+ *     finders.Build; the moves of MovingAIOpt() when nil.
+ */
+func RunScenarios(grid *core.TGrid, scenarios []maps.TScenario, name string, config map[string]interface{}) (*TScenarioReport, error) {
+	if config == nil {
+		// octile is the default heuristic of diagonal moves.
+		config = map[string]interface{}{"diagonalMovement": MovingAIOpt().DiagonalMovement.String()}
+	}
+	stats := &core.TSearchStats{}
+	finder, err := finders.BuildWith(name, &core.Opt{Tracer: stats}, config)
+	if err != nil {
+		return nil, err
+	}
+
+	var report = &TScenarioReport{MaxSuboptimality: 1}
+	for i, s := range scenarios {
+		if s.MapWidth != grid.Width() || s.MapHeight != grid.Height() {
+			return nil, fmt.Errorf("bench: scenario %d is for a %dx%d map, not %dx%d",
+				i, s.MapWidth, s.MapHeight, grid.Width(), grid.Height())
+		}
+		if !grid.IsWalkableAt(s.StartX, s.StartY) || !grid.IsWalkableAt(s.GoalX, s.GoalY) {
+			return nil, fmt.Errorf("bench: scenario %d starts or ends on a blocked cell", i)
+		}
+
+		*stats = core.TSearchStats{}
+		begin := time.Now()
+		path := finder.FindPath(grid.NodeID(s.StartX, s.StartY), grid.NodeID(s.GoalX, s.GoalY), grid)
+		result := TScenarioResult{
+			Scenario: s,
+			Found:    len(path) > 0,
+			Opened:   stats.Opened,
+			Closed:   stats.Closed,
+			Elapsed:  time.Since(begin),
+		}
+		report.Elapsed += result.Elapsed
+
+		if result.Found {
+			report.Solved++
+			result.Cost = core.PathCost(grid, path)
+			result.Suboptimality = 1
+			if s.Optimal > 0 {
+				result.Suboptimality = result.Cost / s.Optimal
+			}
+			switch {
+			case math.Abs(result.Cost-s.Optimal) <= Tolerance*math.Max(1, s.Optimal):
+				report.Optimal++
+			case result.Cost < s.Optimal:
+				report.Shorter++
+			}
+			report.MaxSuboptimality = math.Max(report.MaxSuboptimality, result.Suboptimality)
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
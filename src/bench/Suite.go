@@ -0,0 +1,236 @@
+package bench
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"time"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/maps"
+)
+
+/**
+ * A start and goal on a map.
+ */
+type TQuery struct {
+	StartX int `json:"startX"`
+	StartY int `json:"startY"`
+	GoalX  int `json:"goalX"`
+	GoalY  int `json:"goalY"`
+}
+
+/**
+ * A map of the corpus with its queries.
+ */
+type TCase struct {
+	Name    string
+	Grid    *core.TGrid
+	Queries []TQuery
+}
+
+/**
+ * The queries of MovingAI scenarios.
+ */
+func QueriesFromScenarios(scenarios []maps.TScenario) []TQuery {
+	var queries = make([]TQuery, 0, len(scenarios))
+	for _, s := range scenarios {
+		queries = append(queries, TQuery{StartX: s.StartX, StartY: s.StartY, GoalX: s.GoalX, GoalY: s.GoalY})
+	}
+	return queries
+}
+
+/**
+ * Draw count queries between walkable cells, the same ones for the same
+ * seed.
+ */
+func RandomQueries(grid *core.TGrid, count int, seed int64) []TQuery {
+	var free [][2]int
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			if grid.IsWalkableAt(x, y) {
+				free = append(free, [2]int{x, y})
+			}
+		}
+	}
+	var queries []TQuery
+	if len(free) == 0 {
+		return queries
+	}
+	random := rand.New(rand.NewSource(seed))
+	for i := 0; i < count; i++ {
+		start, goal := free[random.Intn(len(free))], free[random.Intn(len(free))]
+		queries = append(queries, TQuery{StartX: start[0], StartY: start[1], GoalX: goal[0], GoalY: goal[1]})
+	}
+	return queries
+}
+
+/**
+ * Measures of one finder on one query.
+ * Expansions counts the closed nodes; Suboptimality is Cost over the
+ * Dijkstra cost, 1 for an optimal path. Missed is set when the finder
+ * found no path though Dijkstra did; Suboptimality is then 0.
+ */
+type TSample struct {
+	Finder        string  `json:"finder"`
+	Map           string  `json:"map"`
+	Query         int     `json:"query"`
+	Found         bool    `json:"found"`
+	Expansions    int     `json:"expansions"`
+	Opened        int     `json:"opened"`
+	Nanos         int64   `json:"nanos"`
+	Allocs        uint64  `json:"allocs"`
+	Bytes         uint64  `json:"bytes"`
+	Cost          float64 `json:"cost"`
+	Optimal       float64 `json:"optimal"`
+	Suboptimality float64 `json:"suboptimality"`
+	Missed        bool    `json:"missed"`
+}
+
+/**
+ * Benchmark of finders over a corpus.
+ * Every finder is built by name from the registry with the options
+ * returned by Opt, Dijkstra giving the reference costs.
+ */
+type TSuite struct {
+	Finders []string
+	Opt     func() *core.Opt
+	Repeat  int // runs per query, the fastest one is kept
+}
+
+/**
+ * @constructor
+ * @param {[]string} names - Registered finder names.
+ */
+func NewSuite(names ...string) *TSuite {
+	return &TSuite{
+		Finders: names,
+		Opt:     MovingAIOpt,
+		Repeat:  1,
+	}
+}
+
+/**
+ * Run every finder on every query of the cases.
+ */
+func (this *TSuite) Run(cases []TCase) (*TReport, error) {
+	var report = &TReport{}
+	dijkstra, err := finders.Create("dijkstra", this.Opt())
+	if err != nil {
+		return nil, fmt.Errorf("bench: the reference finder is not linked in: %v", err)
+	}
+	stats := &core.TSearchStats{}
+	searchers := make([]finders.FinderBase, len(this.Finders))
+	for i, name := range this.Finders {
+		opt := this.Opt()
+		opt.Tracer = stats
+		if searchers[i], err = finders.Create(name, opt); err != nil {
+			return nil, err
+		}
+	}
+	repeat := this.Repeat
+	if repeat < 1 {
+		repeat = 1
+	}
+
+	for _, c := range cases {
+		for q, query := range c.Queries {
+			if !c.Grid.IsWalkableAt(query.StartX, query.StartY) || !c.Grid.IsWalkableAt(query.GoalX, query.GoalY) {
+				return nil, fmt.Errorf("bench: %s query %d starts or ends on a blocked cell", c.Name, q)
+			}
+			start, goal := c.Grid.NodeID(query.StartX, query.StartY), c.Grid.NodeID(query.GoalX, query.GoalY)
+			reference := dijkstra.FindPath(start, goal, c.Grid)
+			optimal := core.PathCost(c.Grid, reference)
+
+			for i, finder := range searchers {
+				sample := TSample{Finder: this.Finders[i], Map: c.Name, Query: q, Optimal: optimal}
+				for r := 0; r < repeat; r++ {
+					*stats = core.TSearchStats{}
+					var before, after runtime.MemStats
+					runtime.ReadMemStats(&before)
+					begin := time.Now()
+					path := finder.FindPath(start, goal, c.Grid)
+					nanos := time.Since(begin).Nanoseconds()
+					runtime.ReadMemStats(&after)
+					if r == 0 || nanos < sample.Nanos {
+						sample.Nanos = nanos
+					}
+					sample.Found = len(path) > 0
+					sample.Cost = core.PathCost(c.Grid, path)
+					sample.Allocs = after.Mallocs - before.Mallocs
+					sample.Bytes = after.TotalAlloc - before.TotalAlloc
+					sample.Expansions = stats.Closed
+					sample.Opened = stats.Opened
+				}
+				sample.Missed = !sample.Found && len(reference) > 0
+				switch {
+				case !sample.Found || len(reference) == 0:
+					sample.Suboptimality = 0
+				case optimal == 0:
+					sample.Suboptimality = 1
+				default:
+					sample.Suboptimality = sample.Cost / optimal
+				}
+				report.Samples = append(report.Samples, sample)
+			}
+		}
+	}
+	return report, nil
+}
+
+/**
+ * Mean measures of a finder over a report.
+ * MaxSuboptimality only covers the paths found; Missed counts the
+ * queries the finder failed though Dijkstra found a path.
+ */
+type TSummary struct {
+	Finder           string
+	Queries          int
+	Found            int
+	MeanExpansions   float64
+	MeanNanos        float64
+	MeanAllocs       float64
+	MaxSuboptimality float64
+	Missed           int
+}
+
+/**
+ * Summaries per finder, in the order the finders first appear.
+ */
+func (this *TReport) Summaries() []TSummary {
+	var summaries []TSummary
+	var index = map[string]int{}
+	for _, s := range this.Samples {
+		i, ok := index[s.Finder]
+		if !ok {
+			i = len(summaries)
+			index[s.Finder] = i
+			summaries = append(summaries, TSummary{Finder: s.Finder})
+		}
+		sum := &summaries[i]
+		sum.Queries++
+		if s.Found {
+			sum.Found++
+		}
+		if s.Missed {
+			sum.Missed++
+		}
+		sum.MeanExpansions += float64(s.Expansions)
+		sum.MeanNanos += float64(s.Nanos)
+		sum.MeanAllocs += float64(s.Allocs)
+		sum.MaxSuboptimality = math.Max(sum.MaxSuboptimality, s.Suboptimality)
+	}
+	for i := range summaries {
+		n := float64(summaries[i].Queries)
+		summaries[i].MeanExpansions /= n
+		summaries[i].MeanNanos /= n
+		summaries[i].MeanAllocs /= n
+	}
+	return summaries
+}
@@ -0,0 +1,133 @@
+package cache
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+/**
+ * Storage of encoded paths. Implementations must be safe for concurrent
+ * use; a miss is reported as ok == false with a nil error.
+ */
+type Backend interface {
+	Get(key string) (value []byte, ok bool, err error)
+	Set(key string, value []byte) error
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+/**
+ * In-memory Backend keeping the Capacity most recently used entries.
+ */
+type TLRUBackend struct {
+	Capacity int
+
+	mu      sync.Mutex
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+}
+
+/**
+ * @constructor
+ * @param {number} capacity - Number of entries kept.
+ */
+func NewLRUBackend(capacity int) *TLRUBackend {
+	return &TLRUBackend{
+		Capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (this *TLRUBackend) Get(key string) ([]byte, bool, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	elem, ok := this.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	this.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true, nil
+}
+
+func (this *TLRUBackend) Set(key string, value []byte) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if elem, ok := this.entries[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		this.order.MoveToFront(elem)
+		return nil
+	}
+	this.entries[key] = this.order.PushFront(&lruEntry{key: key, value: value})
+	for this.order.Len() > this.Capacity {
+		oldest := this.order.Back()
+		this.order.Remove(oldest)
+		delete(this.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+func (this *TLRUBackend) Len() int {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.order.Len()
+}
+
+/**
+ * Backend storing entries in Redis, shared by every process using it.
+ * Entries expire after TTL (0 keeps them until Redis evicts them), which
+ * is what eventually drops the ones of grids that changed.
+ */
+type TRedisBackend struct {
+	Pool   *redis.Pool
+	Prefix string
+	TTL    time.Duration
+}
+
+/**
+ * @constructor
+ * @param {Pool} pool - Connections to the Redis server.
+ * @param {Duration} ttl - Lifetime of the entries, 0 for no expiry.
+ */
+func NewRedisBackend(pool *redis.Pool, ttl time.Duration) *TRedisBackend {
+	return &TRedisBackend{
+		Pool:   pool,
+		Prefix: "pathcache:",
+		TTL:    ttl,
+	}
+}
+
+func (this *TRedisBackend) Get(key string) ([]byte, bool, error) {
+	conn := this.Pool.Get()
+	defer conn.Close()
+	value, err := redis.Bytes(conn.Do("GET", this.Prefix+key))
+	if err == redis.ErrNil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (this *TRedisBackend) Set(key string, value []byte) error {
+	conn := this.Pool.Get()
+	defer conn.Close()
+	var err error
+	if this.TTL > 0 {
+		_, err = conn.Do("SET", this.Prefix+key, value, "PX", int64(this.TTL/time.Millisecond))
+	} else {
+		_, err = conn.Do("SET", this.Prefix+key, value)
+	}
+	return err
+}
@@ -0,0 +1,289 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	_ "go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/SIPPFinder"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+	"github.com/gomodule/redigo/redis"
+)
+
+// fakeRedis answers GET and SET (with PX) over RESP, enough for TRedisBackend.
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	expiry   map[string]time.Time
+}
+
+func startFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeRedis{listener: listener, data: map[string]string{}, expiry: map[string]time.Time{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line)[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line)[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (this *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		this.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			value, ok := this.data[args[1]]
+			if deadline, expires := this.expiry[args[1]]; expires && time.Now().After(deadline) {
+				ok = false
+			}
+			if ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			} else {
+				io.WriteString(conn, "$-1\r\n")
+			}
+		case "SET":
+			this.data[args[1]] = args[2]
+			delete(this.expiry, args[1])
+			if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+				ms, _ := strconv.Atoi(args[4])
+				this.expiry[args[1]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+			}
+			io.WriteString(conn, "+OK\r\n")
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+		this.mu.Unlock()
+	}
+}
+
+// countingFinder counts the searches reaching it, optionally held by gate.
+type countingFinder struct {
+	searches int32
+	gate     chan struct{}
+	finder   finders.FinderBase
+}
+
+func (this *countingFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	atomic.AddInt32(&this.searches, 1)
+	if this.gate != nil {
+		<-this.gate
+	}
+	return this.finder.FindPath(start, end, graph)
+}
+
+func newCounted(t *testing.T, backend Backend) (*TCachedFinder, *countingFinder) {
+	cached, err := Wrap("astar", &core.Opt{}, nil, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter := &countingFinder{finder: cached.Finder}
+	cached.Finder = counter
+	return cached, counter
+}
+
+var cacheMatrix = core.DoubleInt32{
+	{0, 0, 0, 0},
+	{1, 1, 1, 0},
+	{0, 0, 0, 0},
+}
+
+func TestCacheBackends(t *testing.T) {
+	server := startFakeRedis(t)
+	defer server.listener.Close()
+	pool := &redis.Pool{
+		MaxIdle: 2,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", server.listener.Addr().String())
+		},
+	}
+	defer pool.Close()
+
+	backends := map[string]Backend{
+		"lru":   NewLRUBackend(16),
+		"redis": NewRedisBackend(pool, time.Minute),
+	}
+	for name, backend := range backends {
+		grid := core.Grid(4, 3, cacheMatrix)
+		cached, counter := newCounted(t, backend)
+		start, end := grid.NodeID(0, 0), grid.NodeID(0, 2)
+
+		first := cached.FindPath(start, end, grid)
+		second := cached.FindPath(start, end, grid)
+		akLog.FmtPrintln(name, ": ", grid.PathCoords(second), cached.Stats())
+		if len(first) != 9 || fmt.Sprint(first) != fmt.Sprint(second) || counter.searches != 1 {
+			t.Fatalf("%s: paths %v and %v after %d searches", name, first, second, counter.searches)
+		}
+
+		// opening a shortcut changes the content hash: searched again.
+		grid.SetWalkableAt(0, 1, true)
+		if path := cached.FindPath(start, end, grid); len(path) != 3 || counter.searches != 2 {
+			t.Fatalf("%s: path %v after the grid changed, %d searches", name, path, counter.searches)
+		}
+		// closing it again gives back the first content, still cached.
+		grid.SetWalkableAt(0, 1, false)
+		if path := cached.FindPath(start, end, grid); len(path) != 9 || counter.searches != 2 {
+			t.Fatalf("%s: path %v after the grid was restored, %d searches", name, path, counter.searches)
+		}
+
+		// the absence of a path is cached too.
+		grid.SetWalkableAt(3, 1, false)
+		cached.FindPath(start, end, grid)
+		if path := cached.FindPath(start, end, grid); len(path) != 0 || counter.searches != 3 {
+			t.Fatalf("%s: path %v on a closed grid, %d searches", name, path, counter.searches)
+		}
+		if stats := cached.Stats(); stats.Hits != 3 || stats.Misses != 3 || stats.Errors != 0 {
+			t.Fatalf("%s: stats %+v", name, stats)
+		}
+	}
+}
+
+func TestCacheLRUEviction(t *testing.T) {
+	lru := NewLRUBackend(2)
+	lru.Set("a", []byte("1"))
+	lru.Set("b", []byte("2"))
+	lru.Get("a")
+	lru.Set("c", []byte("3"))
+	if _, ok, _ := lru.Get("b"); ok || lru.Len() != 2 {
+		t.Fatalf("least recently used entry kept")
+	}
+	if value, ok, _ := lru.Get("a"); !ok || string(value) != "1" {
+		t.Fatalf("recently used entry evicted")
+	}
+}
+
+func TestCacheCoalescing(t *testing.T) {
+	grid := core.Grid(4, 3, cacheMatrix)
+	cached, counter := newCounted(t, NewLRUBackend(16))
+	counter.gate = make(chan struct{})
+
+	const callers = 8
+	var wg sync.WaitGroup
+	paths := make([]core.ArrayNodeID, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			paths[i] = cached.FindPath(grid.NodeID(0, 0), grid.NodeID(0, 2), grid)
+		}(i)
+	}
+	// let every caller reach the search in flight before it completes.
+	time.Sleep(50 * time.Millisecond)
+	close(counter.gate)
+	wg.Wait()
+
+	if counter.searches != 1 {
+		t.Fatalf("%d searches for identical requests", counter.searches)
+	}
+	for i := range paths {
+		if len(paths[i]) != 9 {
+			t.Fatalf("caller %d got %v", i, paths[i])
+		}
+	}
+	paths[0][0] = -1
+	if paths[1][0] == -1 {
+		t.Fatalf("callers share the path slice")
+	}
+	if stats := cached.Stats(); stats.Coalesced != callers-1 {
+		t.Fatalf("stats %+v", stats)
+	}
+}
+
+func TestCacheKeyFinderConfig(t *testing.T) {
+	grid := core.Grid(4, 3, cacheMatrix)
+	start, end := grid.NodeID(0, 0), grid.NodeID(0, 2)
+	var keys []string
+	for _, horizon := range []float64{10, 10, 1000} {
+		cached, err := Wrap("sipp", &core.Opt{}, map[string]interface{}{"horizon": horizon}, NewLRUBackend(16))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, cached.Key(start, end, grid))
+	}
+	akLog.FmtPrintln(keys[2])
+	if keys[0] != keys[1] || keys[0] == keys[2] {
+		t.Fatalf("keys %q", keys)
+	}
+
+	// finders differing only in their moves share a backend, not paths.
+	backend := NewLRUBackend(16)
+	grid = core.Grid(3, 3, nil)
+	var lengths []int
+	for _, move := range []string{"never", "always"} {
+		cached, err := Wrap("astar", &core.Opt{}, map[string]interface{}{"diagonalMovement": move}, backend)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lengths = append(lengths, len(cached.FindPath(grid.NodeID(0, 0), grid.NodeID(2, 2), grid)))
+	}
+	if lengths[0] != 5 || lengths[1] != 3 {
+		t.Fatalf("path lengths %v", lengths)
+	}
+	if _, err := Wrap("astar", &core.Opt{}, map[string]interface{}{"weight": -1}, backend); err == nil {
+		t.Fatalf("invalid config wrapped")
+	}
+}
+
+func TestCachePathEncoding(t *testing.T) {
+	for _, path := range []core.ArrayNodeID{{}, {5}, {10, 3, 4, 1 << 40, 0}} {
+		decoded, err := decodePath(encodePath(path))
+		if err != nil || fmt.Sprint(decoded) != fmt.Sprint(path) {
+			t.Fatalf("path %v decoded as %v, %v", path, decoded, err)
+		}
+	}
+	if _, err := decodePath([]byte{3, 1}); err == nil {
+		t.Fatalf("truncated entry accepted")
+	}
+}
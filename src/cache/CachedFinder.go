@@ -0,0 +1,186 @@
+package cache
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+)
+
+/**
+ * Graph whose content can be hashed, core.TGrid included. Searches on
+ * other graphs go straight to the finder.
+ */
+type HashedGraph interface {
+	core.Graph
+	ContentHash() uint64
+}
+
+/**
+ * Counters of a cached finder.
+ */
+type TCacheStats struct {
+	Hits      int64 // answered by the backend
+	Misses    int64 // searched
+	Coalesced int64 // waited for an identical search in flight
+	Errors    int64 // backend failures, served by searching
+}
+
+/**
+ * Caching layer around any registered finder.
+ * Entries are keyed by the graph content hash, so a grid that changed
+ * never hits the entries of its previous content; those are left to the
+ * backend to evict.
+ */
+type TCachedFinder struct {
+	Finder    finders.FinderBase
+	Name      string
+	Config    string // the config the finder was built from, canonical
+	FinderOpt *core.Opt
+	Backend   Backend
+
+	group flightGroup
+	stats TCacheStats
+}
+
+/**
+ * Build the finder registered under name, as finders.BuildWith does, and
+ * wrap it.
+ * @param {string} name - Names the finder in the keys.
+ * @param {Object} opt - Options outside the config, such as the Tracer
+ *     and Done of the searches.
+ * @param {Object} config - Options by name, part of the keys in their
+ *     canonical form.
+ * @param {Backend} backend
+ */
+func Wrap(name string, opt *core.Opt, config map[string]interface{}, backend Backend) (*TCachedFinder, error) {
+	canonical, err := finders.Canonical(name, config)
+	if err != nil {
+		return nil, err
+	}
+	finder, err := finders.BuildWith(name, opt, config)
+	if err != nil {
+		return nil, err
+	}
+	return &TCachedFinder{
+		Finder:    finder,
+		Name:      name,
+		Config:    canonical,
+		FinderOpt: opt,
+		Backend:   backend,
+	}, nil
+}
+
+func funcName(fn interface{}) string {
+	value := reflect.ValueOf(fn)
+	if value.IsNil() {
+		return ""
+	}
+	return runtime.FuncForPC(value.Pointer()).Name()
+}
+
+/**
+ * Cache key of a search: the finder is known by its name, its config and
+ * the options it was wrapped with. Functions in the options are told
+ * apart by name, so closures built by the same code share entries.
+ */
+func (this *TCachedFinder) Key(start, end core.NodeID, graph HashedGraph) string {
+	opt := this.FinderOpt
+	return fmt.Sprintf("%016x:%s:%d:%d:%s:%t:%t:%d:%s:%g:%d:%s:%d:%d",
+		graph.ContentHash(), this.Name, start, end, this.Config,
+		opt.AllowDiagonal, opt.DontCrossCorners, opt.DiagonalMovement,
+		funcName(opt.Heuristic), opt.Weight, opt.Neighborhood,
+		funcName(opt.Heuristic3D), opt.AgentSize, opt.TieBreaking)
+}
+
+/**
+ * Find and return the path, from the backend when it is known.
+ * @return {core.ArrayNodeID} A path owned by the caller.
+ */
+func (this *TCachedFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	hashed, ok := graph.(HashedGraph)
+	if !ok {
+		return this.Finder.FindPath(start, end, graph)
+	}
+	key := this.Key(start, end, hashed)
+
+	path, shared := this.group.do(key, func() core.ArrayNodeID {
+		value, ok, err := this.Backend.Get(key)
+		if err != nil {
+			atomic.AddInt64(&this.stats.Errors, 1)
+		}
+		if ok {
+			if path, err := decodePath(value); err == nil {
+				atomic.AddInt64(&this.stats.Hits, 1)
+				return path
+			}
+			atomic.AddInt64(&this.stats.Errors, 1)
+		}
+		atomic.AddInt64(&this.stats.Misses, 1)
+		path := this.Finder.FindPath(start, end, graph)
+		if this.FinderOpt.Stopped() {
+			// an aborted search proves nothing about the path.
+			return path
+		}
+		if err := this.Backend.Set(key, encodePath(path)); err != nil {
+			atomic.AddInt64(&this.stats.Errors, 1)
+		}
+		return path
+	})
+	if shared {
+		atomic.AddInt64(&this.stats.Coalesced, 1)
+	}
+	return append(core.ArrayNodeID{}, path...)
+}
+
+func (this *TCachedFinder) Stats() TCacheStats {
+	return TCacheStats{
+		Hits:      atomic.LoadInt64(&this.stats.Hits),
+		Misses:    atomic.LoadInt64(&this.stats.Misses),
+		Coalesced: atomic.LoadInt64(&this.stats.Coalesced),
+		Errors:    atomic.LoadInt64(&this.stats.Errors),
+	}
+}
+
+/**
+ * Paths are stored as the varint deltas between consecutive node IDs.
+ */
+func encodePath(path core.ArrayNodeID) []byte {
+	var buf = make([]byte, 0, binary.MaxVarintLen64*(len(path)+1))
+	var tmp [binary.MaxVarintLen64]byte
+	buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(path)))]...)
+	var prev core.NodeID
+	for _, id := range path {
+		buf = append(buf, tmp[:binary.PutVarint(tmp[:], int64(id-prev))]...)
+		prev = id
+	}
+	return buf
+}
+
+func decodePath(buf []byte) (core.ArrayNodeID, error) {
+	count, n := binary.Uvarint(buf)
+	if n <= 0 || count > uint64(len(buf)) {
+		return nil, fmt.Errorf("cache: corrupt path entry")
+	}
+	buf = buf[n:]
+	var path = make(core.ArrayNodeID, 0, count)
+	var prev core.NodeID
+	for i := uint64(0); i < count; i++ {
+		delta, n := binary.Varint(buf)
+		if n <= 0 {
+			return nil, fmt.Errorf("cache: corrupt path entry")
+		}
+		buf = buf[n:]
+		prev += core.NodeID(delta)
+		path = append(path, prev)
+	}
+	return path, nil
+}
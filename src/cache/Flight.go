@@ -0,0 +1,53 @@
+package cache
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"sync"
+
+	"go-PathFinding/core"
+)
+
+type flightCall struct {
+	wg   sync.WaitGroup
+	path core.ArrayNodeID
+}
+
+/**
+ * Coalesces concurrent calls with the same key into one.
+ */
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+/**
+ * Run fn once for all the callers arriving while it runs with the same
+ * key. Shared tells whether the result came from another caller's run.
+ */
+func (this *flightGroup) do(key string, fn func() core.ArrayNodeID) (path core.ArrayNodeID, shared bool) {
+	this.mu.Lock()
+	if this.calls == nil {
+		this.calls = map[string]*flightCall{}
+	}
+	if call, ok := this.calls[key]; ok {
+		this.mu.Unlock()
+		call.wg.Wait()
+		return call.path, true
+	}
+	call := &flightCall{}
+	call.wg.Add(1)
+	this.calls[key] = call
+	this.mu.Unlock()
+
+	defer func() {
+		this.mu.Lock()
+		delete(this.calls, key)
+		this.mu.Unlock()
+		call.wg.Done()
+	}()
+	call.path = fn()
+	return call.path, false
+}
@@ -0,0 +1,148 @@
+package main
+
+/*
+	by stefan 2572915286@qq.com
+
+	pathbench runs finders over a corpus of maps and reports their
+	expansions, time, allocations and path costs:
+
+		pathbench -finders astar,biastar -json head.json -compare base.json maps/*.map
+
+	The queries of a map come from its MovingAI scenario file (name.map.scen)
+	when there is one, or are drawn at random.
+*/
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-PathFinding/bench"
+	"go-PathFinding/finders"
+	_ "go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/BiAStarFinder"
+	_ "go-PathFinding/finders/DijkstraFinder"
+	_ "go-PathFinding/finders/JumpPointFinder"
+	_ "go-PathFinding/finders/SIPPFinder"
+	"go-PathFinding/maps"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func loadCase(filename string, queries int, seed int64) (bench.TCase, error) {
+	var c = bench.TCase{Name: filepath.Base(filename)}
+	grid, err := maps.Load(filename)
+	if err != nil {
+		return c, err
+	}
+	c.Grid = grid
+	file, err := os.Open(filename + ".scen")
+	if os.IsNotExist(err) {
+		c.Queries = bench.RandomQueries(grid, queries, seed)
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	defer file.Close()
+	scenarios, err := maps.ReadScenarios(file)
+	if err != nil {
+		return c, fmt.Errorf("%s.scen: %v", filename, err)
+	}
+	c.Queries = bench.QueriesFromScenarios(scenarios)
+	return c, nil
+}
+
+func writeFile(filename string, write func(io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("pathbench", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		names    = flags.String("finders", strings.Join(finders.Names(), ","), "comma-separated finder names")
+		repeat   = flags.Int("repeat", 3, "runs per query, the fastest is kept")
+		queries  = flags.Int("queries", 100, "random queries per map without scenario file")
+		seed     = flags.Int64("seed", 1, "seed of the random queries")
+		csvFile  = flags.String("csv", "", "write the samples as CSV to `file`")
+		jsonFile = flags.String("json", "", "write the samples as JSON to `file`")
+		compare  = flags.String("compare", "", "JSON report of a previous run to check for regressions")
+	)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	fail := func(format string, args ...interface{}) int {
+		fmt.Fprintf(stderr, "pathbench: "+format+"\n", args...)
+		return 1
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: pathbench [flags] map...")
+		flags.PrintDefaults()
+		return 2
+	}
+
+	var cases []bench.TCase
+	for _, filename := range flags.Args() {
+		c, err := loadCase(filename, *queries, *seed)
+		if err != nil {
+			return fail("%v", err)
+		}
+		cases = append(cases, c)
+	}
+	suite := bench.NewSuite(strings.Split(*names, ",")...)
+	suite.Repeat = *repeat
+	report, err := suite.Run(cases)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	fmt.Fprintf(stdout, "%-10s %8s %8s %8s %12s %12s %10s %8s\n", "finder", "queries", "found", "missed", "expansions", "time(us)", "allocs", "subopt")
+	for _, s := range report.Summaries() {
+		fmt.Fprintf(stdout, "%-10s %8d %8d %8d %12.1f %12.1f %10.1f %8.4f\n",
+			s.Finder, s.Queries, s.Found, s.Missed, s.MeanExpansions, s.MeanNanos/1000, s.MeanAllocs, s.MaxSuboptimality)
+	}
+	if *csvFile != "" {
+		if err := writeFile(*csvFile, report.WriteCSV); err != nil {
+			return fail("%v", err)
+		}
+	}
+	if *jsonFile != "" {
+		if err := writeFile(*jsonFile, report.WriteJSON); err != nil {
+			return fail("%v", err)
+		}
+	}
+
+	if *compare != "" {
+		file, err := os.Open(*compare)
+		if err != nil {
+			return fail("%v", err)
+		}
+		base, err := bench.ReadJSON(file)
+		file.Close()
+		if err != nil {
+			return fail("%s: %v", *compare, err)
+		}
+		regressions := bench.Compare(base, report, bench.DefaultThresholds)
+		for _, r := range regressions {
+			fmt.Fprintln(stdout, "REGRESSION", r)
+		}
+		if len(regressions) > 0 {
+			return fail("%d regressions against %s", len(regressions), *compare)
+		}
+	}
+	return 0
+}
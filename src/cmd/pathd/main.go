@@ -0,0 +1,63 @@
+package main
+
+/*
+	by stefan 2572915286@qq.com
+
+	pathd serves the JSON API of package service:
+
+		pathd -addr :8080 -timeout 5s
+*/
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-PathFinding/service"
+)
+
+func main() {
+	var (
+		addr     = flag.String("addr", ":8080", "listen address")
+		timeout  = flag.Duration("timeout", 5*time.Second, "deadline of each request, 0 for none")
+		maxCells = flag.Int("maxcells", service.DefaultMaxCells, "largest grid accepted, in cells")
+		searches = flag.Int("searches", 0, "searches running at once, 0 for the number of CPUs")
+		grace    = flag.Duration("grace", 10*time.Second, "how long shutdown waits for requests in flight")
+	)
+	flag.Parse()
+
+	handler := service.NewServer(*timeout)
+	handler.MaxCells = *maxCells
+	handler.MaxSearches = *searches
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("pathd listening on %s", *addr)
+		errc <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		log.Fatal(err)
+	case sig := <-stop:
+		log.Printf("pathd: %v, shutting down", sig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *grace)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Fatalf("pathd: shutdown: %v", err)
+	}
+}
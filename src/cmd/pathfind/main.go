@@ -0,0 +1,315 @@
+package main
+
+/*
+	by stefan 2572915286@qq.com
+
+	pathfind runs a finder on a map file:
+
+		pathfind -map town.txt -start 0,0 -end 9,4 -finder biastar \
+			-diagonal onlyWhenNoObstacles -heuristic octile -format ascii
+
+	or on every problem of a MovingAI scenario file:
+
+		pathfind -map arena.map -scen arena.map.scen
+
+	-tui opens the interactive visualiser, see tui.go.
+
+	-png and -svg files also draw the map, the search and the path, e.g. for
+	a bug report.
+*/
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-PathFinding/bench"
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	_ "go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/BiAStarFinder"
+	_ "go-PathFinding/finders/DijkstraFinder"
+	_ "go-PathFinding/finders/JumpPointFinder"
+	_ "go-PathFinding/finders/SIPPFinder"
+	"go-PathFinding/maps"
+	"go-PathFinding/service"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func parsePoint(value string) (int, int, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid point %q, expected x,y", value)
+	}
+	x, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid point %q: %v", value, err)
+	}
+	y, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid point %q: %v", value, err)
+	}
+	return x, y, nil
+}
+
+// the flags setting finder options, to the option names of the schemas.
+var optionFlags = map[string]string{
+	"diagonal":           "diagonalMovement",
+	"heuristic":          "heuristic",
+	"weight":             "weight",
+	"agent-size":         "agentSize",
+	"tie":                "tieBreaking",
+	"horizon":            "horizon",
+	"allow-diagonal":     "allowDiagonal",
+	"dont-cross-corners": "dontCrossCorners",
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("pathfind", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		mapFile = flags.String("map", "", "map `file`")
+		finder  = flags.String("finder", "astar", "finder name, see -list")
+		list    = flags.Bool("list", false, "list the finders and exit")
+		start   = flags.String("start", "", "start `x,y`")
+		end     = flags.String("end", "", "end `x,y`")
+		scen    = flags.String("scen", "", "MovingAI scenario `file` to run instead of -start and -end")
+		format  = flags.String("format", "coords", "output: coords, json or ascii")
+		quiet   = flags.Bool("quiet", false, "do not report statistics")
+		pngFile = flags.String("png", "", "also draw the map, the searched cells and the path to a PNG `file`")
+		tui     = flags.Bool("tui", false, "interactive visualiser in the terminal, on -map or an empty -size grid")
+		size    = flags.String("size", "40,20", "`width,height` of the empty -tui grid")
+		delay   = flags.Duration("delay", 50*time.Millisecond, "delay between the -tui animation steps")
+		svgFile = flags.String("svg", "", "also draw the map, the expansion order and the path to an SVG `file`")
+		fixed   = flags.Bool("fixed", false, "deterministic 10/14 integer costs, as in lockstep games, not with -scen")
+	)
+	// finder options, gathered by name once parsed.
+	flags.String("diagonal", "", "diagonal movement: always, never, ifAtMostOneObstacle, onlyWhenNoObstacles")
+	flags.String("heuristic", "", "heuristic: manhattan, euclidean, octile, chebyshev")
+	flags.Float64("weight", 1, "heuristic weight, e.g. 1.5")
+	flags.Int("agent-size", 1, "side of the square agent in cells")
+	flags.String("tie", "", "tie breaking: fifo, lifo, preferHigherG, crossProduct, fewerTurns")
+	flags.Float64("horizon", 1000, "how far sipp unrolls periodic schedules")
+	flags.Bool("allow-diagonal", false, "allow diagonal moves (deprecated, use -diagonal)")
+	flags.Bool("dont-cross-corners", false, "no diagonal move touching a corner (deprecated, use -diagonal)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	// only the options given reach the finder, which checks them.
+	var options = service.Options{}
+	flags.Visit(func(f *flag.Flag) {
+		if name, ok := optionFlags[f.Name]; ok {
+			options[name] = f.Value.(flag.Getter).Get()
+		}
+	})
+
+	if *list {
+		for _, name := range finders.Names() {
+			fmt.Fprintln(stdout, name)
+		}
+		return 0
+	}
+
+	fail := func(format string, args ...interface{}) int {
+		fmt.Fprintf(stderr, "pathfind: "+format+"\n", args...)
+		return 1
+	}
+	if *tui {
+		if *delay <= 0 {
+			return fail("invalid delay %v", *delay)
+		}
+		var grid *core.TGrid
+		if *mapFile != "" {
+			loaded, err := maps.Load(*mapFile)
+			if err != nil {
+				return fail("%v", err)
+			}
+			grid = loaded
+		} else {
+			width, height, err := parsePoint(*size)
+			if err != nil || width <= 0 || height <= 0 {
+				return fail("invalid size %q", *size)
+			}
+			grid = core.Grid(width, height, nil)
+		}
+		if *fixed {
+			grid.SetCostModel(core.FixedCosts)
+		}
+		if err := runTUI(newTUIState(grid, options, *finder), *delay); err != nil {
+			return fail("%v", err)
+		}
+		return 0
+	}
+	if *mapFile == "" || (*scen == "" && (*start == "" || *end == "")) {
+		flags.Usage()
+		return 2
+	}
+	if *scen != "" {
+		if *fixed {
+			// the scenario optima are octile lengths in float costs.
+			return fail("-fixed cannot be used with -scen")
+		}
+		if _, ok := options["diagonalMovement"]; !ok && options["allowDiagonal"] != true {
+			// the moves the scenario optima are computed with.
+			options["diagonalMovement"] = core.OnlyWhenNoObstacles.String()
+		}
+		return runScenarios(*mapFile, *scen, *finder, options, *format, stdout, stderr)
+	}
+	sx, sy, err := parsePoint(*start)
+	if err != nil {
+		return fail("%v", err)
+	}
+	ex, ey, err := parsePoint(*end)
+	if err != nil {
+		return fail("%v", err)
+	}
+	stats := &core.TSearchStats{}
+	sets := &core.TSearchSets{}
+	trace := &core.TSearchTrace{}
+	tracers := core.TTracers{stats}
+	if *pngFile != "" {
+		tracers = append(tracers, sets)
+	}
+	if *svgFile != "" {
+		tracers = append(tracers, trace)
+	}
+	search, err := finders.BuildWith(*finder, &core.Opt{Tracer: tracers}, options)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	grid, err := maps.Load(*mapFile)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if *fixed {
+		grid.SetCostModel(core.FixedCosts)
+	}
+	for _, p := range [][2]int{{sx, sy}, {ex, ey}} {
+		if p[0] < 0 || p[0] >= grid.Width() || p[1] < 0 || p[1] >= grid.Height() {
+			return fail("point (%d, %d) outside the %dx%d map", p[0], p[1], grid.Width(), grid.Height())
+		}
+	}
+
+	begin := time.Now()
+	ids := search.FindPath(grid.NodeID(sx, sy), grid.NodeID(ex, ey), grid)
+	elapsed := time.Since(begin)
+
+	var res service.PathResponse
+	res.Path = grid.PathCoords(ids)
+	res.Length = core.PathLength(res.Path)
+	res.Cost = core.PathCost(grid, ids)
+	res.Stats = service.Stats{Opened: stats.Opened, Closed: stats.Closed, ElapsedMicros: elapsed.Nanoseconds() / 1000}
+
+	switch *format {
+	case "coords":
+		for _, p := range res.Path {
+			fmt.Fprintf(stdout, "%d,%d\n", p[0], p[1])
+		}
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(res); err != nil {
+			return fail("%v", err)
+		}
+	case "ascii":
+		if err := maps.WriteOverlay(stdout, grid, res.Path); err != nil {
+			return fail("%v", err)
+		}
+	default:
+		return fail("unknown format %q", *format)
+	}
+
+	if *pngFile != "" {
+		err := writeFile(*pngFile, func(w io.Writer) error {
+			return maps.NewImageRenderer().WritePNG(w, grid, sets, res.Path)
+		})
+		if err != nil {
+			return fail("%v", err)
+		}
+	}
+	if *svgFile != "" {
+		err := writeFile(*svgFile, func(w io.Writer) error {
+			return maps.NewSVGRenderer().WriteSVG(w, grid, grid.NodeID(sx, sy), grid.NodeID(ex, ey), trace, ids)
+		})
+		if err != nil {
+			return fail("%v", err)
+		}
+	}
+
+	if !*quiet {
+		fmt.Fprintf(stderr, "finder %s: %d nodes, length %.3f, cost %.3f, opened %d, closed %d, %v\n",
+			*finder, len(res.Path), res.Length, res.Cost, stats.Opened, stats.Closed, elapsed)
+	}
+	if len(res.Path) == 0 {
+		return fail("no path")
+	}
+	return 0
+}
+
+func writeFile(filename string, write func(w io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = write(file)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func runScenarios(mapFile, scenFile, finder string, options service.Options, format string, stdout, stderr io.Writer) int {
+	fail := func(format string, args ...interface{}) int {
+		fmt.Fprintf(stderr, "pathfind: "+format+"\n", args...)
+		return 1
+	}
+	grid, err := maps.Load(mapFile)
+	if err != nil {
+		return fail("%v", err)
+	}
+	file, err := os.Open(scenFile)
+	if err != nil {
+		return fail("%v", err)
+	}
+	scenarios, err := maps.ReadScenarios(file)
+	file.Close()
+	if err != nil {
+		return fail("%s: %v", scenFile, err)
+	}
+	report, err := bench.RunScenarios(grid, scenarios, finder, options)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fail("%v", err)
+		}
+	default:
+		for i, r := range report.Results {
+			if !r.Found || math.Abs(r.Suboptimality-1) > bench.Tolerance {
+				fmt.Fprintf(stdout, "%d: (%d,%d) -> (%d,%d) found %t cost %.8f optimal %.8f\n", i,
+					r.Scenario.StartX, r.Scenario.StartY, r.Scenario.GoalX, r.Scenario.GoalY, r.Found, r.Cost, r.Scenario.Optimal)
+			}
+		}
+	}
+	fmt.Fprintf(stderr, "finder %s: %d scenarios, %d solved, %d optimal, %d shorter, max suboptimality %.6f, %v\n",
+		finder, len(report.Results), report.Solved, report.Optimal, report.Shorter, report.MaxSuboptimality, report.Elapsed)
+	if report.Optimal != len(report.Results) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-PathFinding/core"
+	"go-PathFinding/maps"
+	"go-PathFinding/service"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pathfind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mapFile := filepath.Join(dir, "maze.txt")
+	if err := ioutil.WriteFile(mapFile, []byte("....\n###.\n....\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-map", mapFile, "-start", "0,0", "-end", "0,2", "-format", "ascii"}, &stdout, &stderr)
+	if code != 0 || stdout.String() != "S***\n###*\nG***\n" || !strings.Contains(stderr.String(), "length 8") {
+		t.Fatalf("exit %d\n%s%s", code, stdout.String(), stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"-map", mapFile, "-start", "0,0", "-end", "0,2", "-fixed"}, &stdout, &stderr); code != 0 || !strings.Contains(stderr.String(), "cost 80.000") {
+		t.Fatalf("fixed: exit %d, %s", code, stderr.String())
+	}
+	if code := run([]string{"-map", mapFile, "-scen", mapFile, "-fixed"}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "-fixed cannot be used with -scen") {
+		t.Fatalf("fixed scenarios: exit %d, %s", code, stderr.String())
+	}
+	if code := run([]string{"-tui", "-delay", "0s"}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "invalid delay 0s") {
+		t.Fatalf("tui delay: exit %d, %s", code, stderr.String())
+	}
+	if code := run([]string{"-map", mapFile, "-start", "0,0", "-end", "0,2", "-tie", "sideways"}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), `"sideways" is not one of`) {
+		t.Fatalf("tie: exit %d, %s", code, stderr.String())
+	}
+	// the options are checked by the schema of the finder.
+	for _, c := range []struct {
+		args   []string
+		reason string
+	}{
+		{[]string{"-weight", "-1"}, "option weight: -1 below 1"},
+		{[]string{"-agent-size", "0"}, "option agentSize: 0 outside"},
+		{[]string{"-finder", "dijkstra", "-heuristic", "octile"}, "unknown option heuristic"},
+		{[]string{"-horizon", "50"}, "unknown option horizon"},
+	} {
+		stderr.Reset()
+		args := append([]string{"-map", mapFile, "-start", "0,0", "-end", "0,2"}, c.args...)
+		if code := run(args, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), c.reason) {
+			t.Fatalf("%v: exit %d, %s", c.args, code, stderr.String())
+		}
+	}
+	if code := run([]string{"-map", mapFile, "-start", "0,0", "-end", "0,2", "-finder", "sipp", "-horizon", "50"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("sipp horizon: exit %d, %s", code, stderr.String())
+	}
+
+	pngFile, svgFile := filepath.Join(dir, "maze.png"), filepath.Join(dir, "maze.svg")
+	if code := run([]string{"-map", mapFile, "-start", "0,0", "-end", "0,2", "-png", pngFile, "-svg", svgFile, "-quiet"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("png: exit %d, %s", code, stderr.String())
+	}
+	if grid, err := maps.Load(pngFile); err != nil || grid.Width() != 4*8 || grid.Height() != 3*8 {
+		t.Fatalf("png not written: %v", err)
+	}
+	if svg, err := ioutil.ReadFile(svgFile); err != nil || !strings.Contains(string(svg), "<polyline") {
+		t.Fatalf("svg not written: %v", err)
+	}
+
+	stdout.Reset()
+	code = run([]string{"-map", mapFile, "-start", "0,0", "-end", "0,2", "-format", "json", "-finder", "biastar",
+		"-diagonal", "always", "-heuristic", "octile", "-quiet"}, &stdout, &stderr)
+	var res service.PathResponse
+	if err := json.Unmarshal(stdout.Bytes(), &res); code != 0 || err != nil || len(res.Path) != 7 || res.Stats.Closed == 0 {
+		t.Fatalf("exit %d, %v: %s", code, err, stdout.String())
+	}
+
+	for _, args := range [][]string{
+		{"-map", mapFile, "-start", "0,0", "-end", "9,9"},
+		{"-map", mapFile, "-start", "0,0", "-end", "0,2", "-finder", "nope"},
+		{"-map", mapFile, "-start", "0,0", "-end", "0,1"},
+	} {
+		stderr.Reset()
+		if code := run(args, &stdout, &stderr); code != 1 {
+			t.Fatalf("%v: exit %d, %s", args, code, stderr.String())
+		}
+	}
+
+	scenFile := filepath.Join(dir, "maze.scen")
+	scen := "version 1\n0\tmaze.txt\t4\t3\t0\t0\t0\t2\t8.00000000\n"
+	if err := ioutil.WriteFile(scenFile, []byte(scen), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if code := run([]string{"-map", mapFile, "-scen", scenFile}, &stdout, &stderr); code != 0 || !strings.Contains(stderr.String(), "1 optimal") {
+		t.Fatalf("scenarios: exit %d\n%s%s", code, stdout.String(), stderr.String())
+	}
+}
+
+func TestTUI(t *testing.T) {
+	state := newTUIState(core.Grid(5, 3, nil), service.Options{}, "astar")
+	// a wall across the two top rows of the middle column.
+	for _, key := range []string{"right", "l", " ", "down", "w", "q"} {
+		if !state.handle(key) {
+			break
+		}
+	}
+	if state.grid.IsWalkableAt(2, 0) || state.grid.IsWalkableAt(2, 1) || state.cursorX != 2 || state.cursorY != 1 {
+		t.Fatalf("walls not placed")
+	}
+	if state.handle("q") {
+		t.Fatalf("q did not quit")
+	}
+	// start at (0, 1), goal at the corner: the default one.
+	for _, key := range []string{"left", "left", "s", "enter"} {
+		state.handle(key)
+	}
+	if len(state.path) != 6 || state.step != 0 {
+		t.Fatalf("path %v at step %d, %s", state.path, state.step, state.message)
+	}
+	var out bytes.Buffer
+	state.render(&out)
+	if strings.Contains(out.String(), ansiClosed) || !strings.Contains(out.String(), "step 0/") {
+		t.Fatalf("search shown before the first step")
+	}
+
+	state.handle("p")
+	for state.playing {
+		state.tick()
+		// the current path always leads back to the start.
+		if cells := state.currentPath(); len(cells) > 0 && !cells[state.start] {
+			t.Fatalf("current path %v at step %d misses the start", cells, state.step)
+		}
+	}
+	out.Reset()
+	state.render(&out)
+	frame := out.String()
+	if state.step != len(state.trace.Events) || strings.Count(frame, ansiPath) != 4 || !strings.Contains(frame, ansiClosed) {
+		t.Fatalf("replay ended at step %d\n%s", state.step, frame)
+	}
+
+	state.handle("b")
+	state.handle("d")
+	if state.trace != nil || tuiMoves[state.move] != core.Always {
+		t.Fatalf("changing the moves kept the search")
+	}
+	// a view smaller than the grid follows the cursor.
+	state.view = [2]int{2, 2}
+	state.handle("right")
+	out.Reset()
+	state.render(&out)
+	if rows := strings.Count(out.String(), "\r\n"); rows != 2+3 || !strings.Contains(out.String(), "cursor 1,1") {
+		t.Fatalf("view of %d rows\n%s", rows, out.String())
+	}
+
+	keys := make(chan string)
+	go readKeys(strings.NewReader("j\x1b[C\rq"), keys)
+	var got []string
+	for key := range keys {
+		got = append(got, key)
+	}
+	if strings.Join(got, ",") != "j,right,enter,q" {
+		t.Fatalf("keys %v", got)
+	}
+}
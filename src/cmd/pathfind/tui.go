@@ -0,0 +1,392 @@
+package main
+
+/*
+	by stefan 2572915286@qq.com
+
+	pathfind -tui draws the map in the terminal with ANSI colours, like the
+	PathFinding.js demo: move the cursor with the arrows or hjkl, place the
+	start (s), the goal (g) and walls (space), then search (enter) and step
+	through the recorded search (n and b) or play it (p). The terminal is
+	put in raw mode with stty, so that it works over SSH.
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/service"
+)
+
+var tuiMoves = []core.DiagonalMovement{core.Never, core.Always, core.IfAtMostOneObstacle, core.OnlyWhenNoObstacles}
+
+const tuiHelp = "arrows/hjkl move  space wall  s start  g goal  enter search  n/b step  p play  " +
+	"e end  f finder  d diagonal  c clear  q quit"
+
+// ANSI background colours of the cells.
+const (
+	ansiReset    = "\x1b[0m"
+	ansiWalkable = "\x1b[47m"
+	ansiBlocked  = "\x1b[100m"
+	ansiOpened   = "\x1b[42m"
+	ansiClosed   = "\x1b[46m"
+	ansiPath     = "\x1b[43m"
+	ansiStart    = "\x1b[102m"
+	ansiGoal     = "\x1b[101m"
+)
+
+/**
+ * State of the visualiser, driven by keys and drawn as ANSI frames.
+ * The search is recorded at once and replayed event by event: step is
+ * the number of trace events shown.
+ */
+type tuiState struct {
+	grid    *core.TGrid
+	options service.Options
+	finders []string
+	finder  int
+	move    int
+	cursorX int
+	cursorY int
+	start   core.NodeID
+	goal    core.NodeID
+	trace   *core.TSearchTrace
+	path    core.ArrayNodeID
+	step    int
+	playing bool
+	message string
+	view    [2]int // visible cells across and down
+}
+
+func newTUIState(grid *core.TGrid, options service.Options, finder string) *tuiState {
+	state := &tuiState{
+		grid:    grid,
+		options: options,
+		finders: finders.Names(),
+		start:   grid.NodeID(0, 0),
+		goal:    grid.NodeID(grid.Width()-1, grid.Height()-1),
+		view:    [2]int{grid.Width(), grid.Height()},
+	}
+	for i, name := range state.finders {
+		if name == finder {
+			state.finder = i
+		}
+	}
+	for i, move := range tuiMoves {
+		if move.String() == options["diagonalMovement"] {
+			state.move = i
+		}
+	}
+	return state
+}
+
+func (this *tuiState) clear() {
+	this.trace, this.path, this.step, this.playing = nil, nil, 0, false
+}
+
+/**
+ * Search from start to goal with the selected finder and options,
+ * recording the trace. The replay starts from the first event.
+ */
+func (this *tuiState) search() {
+	this.clear()
+	// the selected moves replace the deprecated diagonal options.
+	options := service.Options{"diagonalMovement": tuiMoves[this.move].String()}
+	for name, value := range this.options {
+		if name != "allowDiagonal" && name != "dontCrossCorners" && name != "diagonalMovement" {
+			options[name] = value
+		}
+	}
+	this.trace = &core.TSearchTrace{}
+	finder, err := finders.BuildWith(this.finders[this.finder], &core.Opt{Tracer: this.trace}, options)
+	if err != nil {
+		this.message = err.Error()
+		return
+	}
+	this.path = finder.FindPath(this.start, this.goal, this.grid)
+	if len(this.path) == 0 {
+		this.message = "no path"
+	} else {
+		this.message = fmt.Sprintf("%d nodes, cost %.3f", len(this.path), core.PathCost(this.grid, this.path))
+	}
+}
+
+/**
+ * Apply a key: a printable character, or "up", "down", "left", "right"
+ * and "enter". Returns false to quit.
+ */
+func (this *tuiState) handle(key string) bool {
+	this.message = ""
+	id := this.grid.NodeID(this.cursorX, this.cursorY)
+	switch key {
+	case "q", "\x03":
+		return false
+	case "up", "k":
+		if this.cursorY > 0 {
+			this.cursorY--
+		}
+	case "down", "j":
+		if this.cursorY < this.grid.Height()-1 {
+			this.cursorY++
+		}
+	case "left", "h":
+		if this.cursorX > 0 {
+			this.cursorX--
+		}
+	case "right", "l":
+		if this.cursorX < this.grid.Width()-1 {
+			this.cursorX++
+		}
+	case " ", "w":
+		if id == this.start || id == this.goal {
+			this.message = "no wall on the start or the goal"
+			break
+		}
+		this.grid.SetWalkableAt(this.cursorX, this.cursorY, !this.grid.IsWalkableAt(this.cursorX, this.cursorY))
+		this.clear()
+	case "s", "g":
+		if !this.grid.IsWalkableAt(this.cursorX, this.cursorY) {
+			this.message = "blocked cell"
+			break
+		}
+		if key == "s" {
+			this.start = id
+		} else {
+			this.goal = id
+		}
+		this.clear()
+	case "enter":
+		this.search()
+	case "n":
+		if this.trace != nil && this.step < len(this.trace.Events) {
+			this.step++
+		}
+	case "b":
+		if this.step > 0 {
+			this.step--
+		}
+	case "e":
+		if this.trace != nil {
+			this.step, this.playing = len(this.trace.Events), false
+		}
+	case "p":
+		if this.trace == nil {
+			this.search()
+		}
+		this.playing = this.trace != nil && !this.playing
+	case "f":
+		this.finder = (this.finder + 1) % len(this.finders)
+		this.clear()
+	case "d":
+		this.move = (this.move + 1) % len(tuiMoves)
+		this.clear()
+	case "c":
+		this.clear()
+	}
+	return true
+}
+
+/**
+ * Advance a playing replay by one event, stopping at the end.
+ */
+func (this *tuiState) tick() {
+	if !this.playing {
+		return
+	}
+	this.handle("n")
+	if this.step >= len(this.trace.Events) {
+		this.playing = false
+	}
+}
+
+// currentPath follows the parents from the node closed last, up to the
+// start of its search.
+func (this *tuiState) currentPath() map[core.NodeID]bool {
+	var cells = map[core.NodeID]bool{}
+	if this.trace == nil {
+		return cells
+	}
+	if this.step == len(this.trace.Events) {
+		for _, id := range this.path {
+			cells[id] = true
+		}
+		return cells
+	}
+	var current core.NodeID = -1
+	for _, e := range this.trace.Events[:this.step] {
+		if e.Close {
+			current = e.ID
+		}
+	}
+	parents := this.trace.Parents(this.step)
+	for current >= 0 && !cells[current] {
+		cells[current] = true
+		parent, ok := parents[current]
+		if !ok {
+			break
+		}
+		current = parent
+	}
+	return cells
+}
+
+/**
+ * Draw a frame: the visible part of the grid around the cursor, two
+ * characters per cell, and the status lines.
+ */
+func (this *tuiState) render(w io.Writer) {
+	var opened = map[core.NodeID]bool{}
+	var closed = map[core.NodeID]bool{}
+	if this.trace != nil {
+		for _, e := range this.trace.Events[:this.step] {
+			if e.Close {
+				closed[e.ID] = true
+				delete(opened, e.ID)
+			} else if !closed[e.ID] {
+				opened[e.ID] = true
+			}
+		}
+	}
+	path := this.currentPath()
+
+	// scroll so that the cursor stays in view.
+	left := clampView(this.cursorX-this.view[0]/2, this.grid.Width()-this.view[0])
+	top := clampView(this.cursorY-this.view[1]/2, this.grid.Height()-this.view[1])
+
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	for y := top; y < top+this.view[1] && y < this.grid.Height(); y++ {
+		for x := left; x < left+this.view[0] && x < this.grid.Width(); x++ {
+			id := this.grid.NodeID(x, y)
+			switch {
+			case id == this.start:
+				b.WriteString(ansiStart)
+			case id == this.goal:
+				b.WriteString(ansiGoal)
+			case !this.grid.IsWalkableAt(x, y):
+				b.WriteString(ansiBlocked)
+			case path[id]:
+				b.WriteString(ansiPath)
+			case closed[id]:
+				b.WriteString(ansiClosed)
+			case opened[id]:
+				b.WriteString(ansiOpened)
+			default:
+				b.WriteString(ansiWalkable)
+			}
+			if x == this.cursorX && y == this.cursorY {
+				b.WriteString("\x1b[30m[]")
+			} else {
+				b.WriteString("  ")
+			}
+			b.WriteString(ansiReset)
+		}
+		b.WriteString("\r\n")
+	}
+
+	events := 0
+	if this.trace != nil {
+		events = len(this.trace.Events)
+	}
+	fmt.Fprintf(&b, "finder %s  diagonal %s  step %d/%d  open %d  closed %d  cursor %d,%d\r\n",
+		this.finders[this.finder], tuiMoves[this.move], this.step, events, len(opened), len(closed), this.cursorX, this.cursorY)
+	fmt.Fprintf(&b, "%s\r\n%s\r\n", tuiHelp, this.message)
+	io.WriteString(w, b.String())
+}
+
+func clampView(first, last int) int {
+	if first > last {
+		first = last
+	}
+	if first < 0 {
+		first = 0
+	}
+	return first
+}
+
+// readKeys turns the raw terminal input into keys, arrows being sent as
+// ESC [ A to D.
+func readKeys(r io.Reader, keys chan<- string) {
+	br := bufio.NewReader(r)
+	defer close(keys)
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return
+		}
+		switch c {
+		case '\r', '\n':
+			keys <- "enter"
+		case 0x1b:
+			if next, err := br.ReadByte(); err != nil || next != '[' {
+				continue
+			}
+			arrow, err := br.ReadByte()
+			if err != nil {
+				return
+			}
+			if key, ok := map[byte]string{'A': "up", 'B': "down", 'C': "right", 'D': "left"}[arrow]; ok {
+				keys <- key
+			}
+		default:
+			keys <- string(c)
+		}
+	}
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+/**
+ * Run the visualiser on the terminal until q is pressed.
+ */
+func runTUI(state *tuiState, delay time.Duration) error {
+	if size, err := stty("size"); err == nil {
+		var rows, cols int
+		if _, err := fmt.Sscan(size, &rows, &cols); err == nil && rows > 4 && cols > 1 {
+			// two characters per cell, four status lines.
+			state.view = [2]int{cols / 2, rows - 4}
+		}
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return fmt.Errorf("stty: %v, not a terminal?", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return fmt.Errorf("stty: %v", err)
+	}
+	defer stty(strings.TrimSpace(saved))
+	defer os.Stdout.WriteString("\x1b[?25h" + ansiReset + "\r\n")
+	os.Stdout.WriteString("\x1b[?25l")
+
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	// redrawn on changes only: idle ticks would repaint the whole screen.
+	state.render(os.Stdout)
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok || !state.handle(key) {
+				return nil
+			}
+		case <-ticker.C:
+			if !state.playing {
+				continue
+			}
+			state.tick()
+		}
+		state.render(os.Stdout)
+	}
+}
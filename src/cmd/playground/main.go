@@ -0,0 +1,63 @@
+package main
+
+/*
+	by stefan 2572915286@qq.com
+
+	playground serves the finder playground of package playground on the
+	local machine, then open the address in a browser:
+
+		playground -addr localhost:8090
+*/
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-PathFinding/playground"
+)
+
+func main() {
+	var (
+		addr       = flag.String("addr", "localhost:8090", "listen address")
+		maxCells   = flag.Int("maxcells", 200*200, "largest grid accepted, in cells")
+		maxFinders = flag.Int("maxfinders", 6, "finders compared at once")
+		grace      = flag.Duration("grace", 5*time.Second, "how long shutdown waits for searches in flight")
+	)
+	flag.Parse()
+
+	handler := playground.NewPlayground()
+	handler.MaxCells = *maxCells
+	handler.MaxFinders = *maxFinders
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("playground on http://%s/", *addr)
+		errc <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		log.Fatal(err)
+	case sig := <-stop:
+		log.Printf("playground: %v, shutting down", sig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *grace)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Fatalf("playground: shutdown: %v", err)
+	}
+}
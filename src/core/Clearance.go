@@ -0,0 +1,107 @@
+package core
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+/**
+ * Clearance of every cell of a grid: the side of the largest square of
+ * walkable cells having its top-left corner on the cell (0 when the cell
+ * is blocked). An agent covering size x size cells fits at (x, y) when
+ * the clearance there is at least size.
+ */
+type TClearanceMap struct {
+	width  int
+	height int
+	values []int32
+}
+
+/**
+ * The clearance map of the grid, built on first use and then kept up to
+ * date by SetWalkableAt.
+ */
+func (this *TGrid) Clearance() *TClearanceMap {
+	if this.clearance == nil {
+		this.clearance = buildClearance(this)
+	}
+	return this.clearance
+}
+
+func buildClearance(grid *TGrid) *TClearanceMap {
+	clearance := &TClearanceMap{
+		width:  grid.width,
+		height: grid.height,
+		values: make([]int32, grid.width*grid.height),
+	}
+	for y := grid.height - 1; y >= 0; y-- {
+		for x := grid.width - 1; x >= 0; x-- {
+			clearance.values[y*grid.width+x] = clearance.compute(grid, x, y)
+		}
+	}
+	return clearance
+}
+
+func (this *TClearanceMap) valueAt(x, y int) int32 {
+	if x < 0 || x >= this.width || y < 0 || y >= this.height {
+		return 0
+	}
+	return this.values[y*this.width+x]
+}
+
+func (this *TClearanceMap) compute(grid *TGrid, x, y int) int32 {
+	if !grid.IsWalkableAt(x, y) {
+		return 0
+	}
+	right := this.valueAt(x+1, y)
+	down := this.valueAt(x, y+1)
+	diagonal := this.valueAt(x+1, y+1)
+	min := right
+	if down < min {
+		min = down
+	}
+	if diagonal < min {
+		min = diagonal
+	}
+	return min + 1
+}
+
+/**
+ * Propagate a walkability change of (x, y). Only cells above and to the
+ * left of it depend on it, and the propagation stops as soon as a cell
+ * keeps its value.
+ */
+func (this *TClearanceMap) update(grid *TGrid, x, y int) {
+	var stack = []Coordinate{{X: int32(x), Y: int32(y)}}
+	for len(stack) > 0 {
+		cell := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		cx, cy := int(cell.X), int(cell.Y)
+		if cx < 0 || cy < 0 {
+			continue
+		}
+		value := this.compute(grid, cx, cy)
+		if value == this.values[cy*this.width+cx] && !(cx == x && cy == y) {
+			continue
+		}
+		this.values[cy*this.width+cx] = value
+		stack = append(stack,
+			Coordinate{X: int32(cx - 1), Y: int32(cy)},
+			Coordinate{X: int32(cx), Y: int32(cy - 1)},
+			Coordinate{X: int32(cx - 1), Y: int32(cy - 1)})
+	}
+}
+
+/**
+ * Get the clearance of the cell, 0 outside the grid.
+ */
+func (this *TClearanceMap) At(x, y int) int32 {
+	return this.valueAt(x, y)
+}
+
+/**
+ * Whether an agent of size x size cells fits with its top-left corner
+ * on (x, y).
+ */
+func (this *TClearanceMap) Fits(x, y int, size int32) bool {
+	return this.valueAt(x, y) >= size
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestClearance(t *testing.T) {
+	grid := Grid(4, 3, DoubleInt32{
+		{0, 0, 0, 0},
+		{0, 0, 0, 1},
+		{0, 0, 0, 0},
+	})
+	expected := DoubleInt32{
+		{3, 2, 1, 1},
+		{2, 2, 1, 0},
+		{1, 1, 1, 1},
+	}
+	clearance := grid.Clearance()
+	for y := range expected {
+		for x := range expected[y] {
+			if clearance.At(x, y) != expected[y][x] {
+				t.Fatalf("clearance at (%v, %v) is %v, expected %v", x, y, clearance.At(x, y), expected[y][x])
+			}
+		}
+	}
+}
+
+func TestClearanceIncremental(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	grid := Grid(16, 12, nil)
+	clearance := grid.Clearance()
+	for i := 0; i < 500; i++ {
+		grid.SetWalkableAt(random.Intn(16), random.Intn(12), random.Intn(3) != 0)
+		rebuilt := buildClearance(grid)
+		for j := range rebuilt.values {
+			if rebuilt.values[j] != clearance.values[j] {
+				t.Fatalf("step %v: cell %v is %v, rebuilt %v", i, j, clearance.values[j], rebuilt.values[j])
+			}
+		}
+	}
+}
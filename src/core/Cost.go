@@ -0,0 +1,57 @@
+package core
+
+import "math"
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+/**
+ * Whether cost is a valid terrain cost: greater than 0 and finite.
+ */
+func ValidCost(cost float64) bool {
+	return cost > 0 && !math.IsInf(cost, 0)
+}
+
+/**
+ * Set the terrain cost of the cell: the cost of a step entering it is
+ * multiplied by it. Cells cost 1 by default; costs below 1 make the grid
+ * heuristics overestimate, so that paths may no longer be optimal.
+ * NOTE: throws exception if the coordinate is not inside the grid.
+ * @param {number} cost - Greater than 0 and finite.
+ */
+func (this *TGrid) SetCostAt(x, y int, cost float64) {
+	if !ValidCost(cost) {
+		panic("Incorrect value of cell cost")
+	}
+	id := this.NodeID(x, y)
+	if this.costs == nil {
+		if cost == 1 {
+			return
+		}
+		this.costs = make([]float64, this.width*this.height)
+		for i := range this.costs {
+			this.costs[i] = 1
+		}
+	}
+	this.hash ^= costKey(id, this.costs[id])
+	this.costs[id] = cost
+	this.hash ^= costKey(id, cost)
+}
+
+/**
+ * The terrain cost of the cell, 1 unless set otherwise.
+ */
+func (this *TGrid) GetCostAt(x, y int) float64 {
+	if this.costs == nil {
+		return 1
+	}
+	return this.costs[this.NodeID(x, y)]
+}
+
+/**
+ * Whether any cell has a cost other than 1.
+ */
+func (this *TGrid) HasCosts() bool {
+	return this.costs != nil
+}
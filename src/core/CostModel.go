@@ -0,0 +1,87 @@
+package core
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import "math"
+
+/**
+ * How a TGrid prices steps and estimates distances.
+ */
+type CostModel int
+
+const (
+	// 1 a straight step and SQRT2 a diagonal one, with opt.Heuristic.
+	FloatCosts CostModel = 0
+	// FixedStraight a straight step and FixedDiagonal a diagonal one, for
+	// deterministic lockstep simulations: see SetCostModel.
+	FixedCosts CostModel = 1
+)
+
+const (
+	FixedStraight = 10
+	FixedDiagonal = 14
+)
+
+/**
+ * Select the cost model of the grid, FloatCosts by default.
+ * Under FixedCosts every g, h and f value a finder computes is a whole
+ * number well below 2^53, so that float64 sums are exact and searches
+ * give bit-identical paths on every platform, whatever the compiler does
+ * with floating-point expressions:
+ *   - steps cost 10 straight and 14 diagonally, times the cell cost,
+ *     rounded to a whole number of at least 1;
+ *   - the heuristic is the integer Manhattan distance without diagonal
+ *     movement, the 10/14 octile distance otherwise; opt.Heuristic is not
+ *     used, float heuristics not being reproducible across platforms;
+ *   - the timed finders keep time in straight steps, schedules included,
+ *     and price a wait as a straight step: SIPP searches on the costs
+ *     and converts its times, the space-time searches of package mapf
+ *     move a step per time step.
+ * Weights must then be exact in binary, e.g. 1.5 or 1.25 but not 1.1, to
+ * keep the weighted estimates exact.
+ */
+func (this *TGrid) SetCostModel(model CostModel) {
+	if model != FloatCosts && model != FixedCosts {
+		panic("Incorrect value of cost model")
+	}
+	if model != this.model {
+		this.hash ^= modelKey(this.model) ^ modelKey(model)
+		this.model = model
+	}
+}
+
+/**
+ * The cost model of the grid.
+ */
+func (this *TGrid) CostModel() CostModel {
+	return this.model
+}
+
+/**
+ * Cost of a straight and of a diagonal step on a cell of cost 1.
+ */
+func (this *TGrid) StepCosts() (straight, diagonal float64) {
+	if this.model == FixedCosts {
+		return FixedStraight, FixedDiagonal
+	}
+	return 1, SQRT2
+}
+
+// fixedHeuristic is the exact cost between two cells of an open grid
+// under FixedCosts.
+func fixedHeuristic(dx, dy int, move DiagonalMovement) float64 {
+	if move == Never {
+		return float64(FixedStraight * (dx + dy))
+	}
+	if dx < dy {
+		dx, dy = dy, dx
+	}
+	return float64(FixedStraight*(dx-dy) + FixedDiagonal*dy)
+}
+
+// fixedCost rounds the price of a step entering a cell of the given cost.
+func fixedCost(step, cost float64) float64 {
+	return math.Max(1, math.Round(float64(step*cost)))
+}
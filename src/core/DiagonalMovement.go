@@ -1,5 +1,7 @@
 package core
 
+import "fmt"
+
 /*
 	by stefan 2572915286@qq.com
 	Based upon  https://github.com/qiao/PathFinding.js
@@ -13,3 +15,46 @@ const (
 	IfAtMostOneObstacle DiagonalMovement = 3
 	OnlyWhenNoObstacles DiagonalMovement = 4
 )
+
+var diagonalMovementNames = map[DiagonalMovement]string{
+	Always:              "always",
+	Never:               "never",
+	IfAtMostOneObstacle: "ifAtMostOneObstacle",
+	OnlyWhenNoObstacles: "onlyWhenNoObstacles",
+}
+
+func (this DiagonalMovement) String() string {
+	if name, ok := diagonalMovementNames[this]; ok {
+		return name
+	}
+	return fmt.Sprintf("DiagonalMovement(%d)", int(this))
+}
+
+/**
+ * Parse the name of a DiagonalMovement, as returned by String.
+ */
+func ParseDiagonalMovement(name string) (DiagonalMovement, error) {
+	for move, moveName := range diagonalMovementNames {
+		if moveName == name {
+			return move, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown diagonal movement %q", name)
+}
+
+/**
+ * The DiagonalMovement of the options, derived from the deprecated
+ * AllowDiagonal and DontCrossCorners when not set, as the finders do.
+ */
+func (this *Opt) Movement() DiagonalMovement {
+	switch {
+	case this.DiagonalMovement != 0:
+		return this.DiagonalMovement
+	case !this.AllowDiagonal:
+		return Never
+	case this.DontCrossCorners:
+		return OnlyWhenNoObstacles
+	default:
+		return IfAtMostOneObstacle
+	}
+}
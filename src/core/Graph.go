@@ -0,0 +1,42 @@
+package core
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+/**
+ * Identifier of a graph node.
+ * Every graph hashes its own node representation (grid coordinates,
+ * waypoint, puzzle state...) into a NodeID: equal nodes must hash to the
+ * same id and different nodes to different ids. Finders only ever see ids.
+ */
+type NodeID int64
+
+type ArrayNodeID []NodeID
+
+/**
+ * The graph abstraction every finder searches on.
+ * Finders may run on any graph, not only TGrid, as long as Neighbors,
+ * Cost and Heuristic agree: Cost is only asked for pairs returned by
+ * Neighbors, and Heuristic should not overestimate the cost of a path
+ * if optimal results are expected.
+ */
+type Graph interface {
+	// the nodes reachable from id in one step, under the finder options.
+	Neighbors(id NodeID, opt *Opt) ArrayNodeID
+	// the cost of the step from -> to.
+	Cost(from, to NodeID) float64
+	// the estimated cost from -> to, before the finder weight is applied.
+	Heuristic(from, to NodeID, opt *Opt) float64
+	// whether id is a node a path may start on, end on or go through,
+	// under the finder options.
+	IsWalkable(id NodeID, opt *Opt) bool
+}
+
+/**
+ * A graph whose nodes lie on a plane, as the cells of a TGrid: the
+ * tie breaking policies looking at directions need their coordinates.
+ */
+type PlanarGraph interface {
+	NodeXY(id NodeID) (int, int)
+}
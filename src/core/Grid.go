@@ -1,6 +1,8 @@
 package core
 
 import (
+	"math"
+
 	"github.com/Peakchen/xgameCommon/akLog"
 )
 
@@ -10,9 +12,16 @@ import (
 */
 
 type TGrid struct {
-	width  int
-	height int
-	nodes  DoubleNode
+	width     int
+	height    int
+	nodes     DoubleNode
+	clearance *TClearanceMap
+	schedules map[NodeID]*TSchedule
+	hash      uint64
+	costs     []float64
+	model     CostModel
+	// contribution of each schedule to hash, as it was when set.
+	scheduleKeys map[NodeID]uint64
 }
 
 const (
@@ -29,11 +38,13 @@ const (
  *     If the matrix is not supplied, all the nodes will be Walkable.  */
 
 func Grid(width, height int, matrix DoubleInt32) *TGrid {
-	return &TGrid{
+	var grid = &TGrid{
 		width:  width,
 		height: height,
 		nodes:  buildNodes(width, height, matrix),
 	}
+	grid.rehash()
+	return grid
 }
 
 /**
@@ -81,6 +92,14 @@ func buildNodes(width, height int, matrix DoubleInt32) DoubleNode {
 	return nodes
 }
 
+func (this *TGrid) Width() int {
+	return this.width
+}
+
+func (this *TGrid) Height() int {
+	return this.height
+}
+
 func (this *TGrid) GetNodeAt(x, y int) *TNode {
 	return this.nodes[y][x]
 }
@@ -116,8 +135,15 @@ func (this *TGrid) isInside(x, y int) bool {
  * @param {number} y - The y coordinate of the node.
  * @param {boolean} Walkable - Whether the position is Walkable.
  */
-func (this *TGrid) setWalkableAt(x, y int32, Walkable bool) {
+func (this *TGrid) SetWalkableAt(x, y int, Walkable bool) {
+	if this.nodes[y][x].Walkable == Walkable {
+		return
+	}
 	this.nodes[y][x].Walkable = Walkable
+	this.hash ^= blockedKey(this.NodeID(x, y))
+	if this.clearance != nil {
+		this.clearance.update(this, x, y)
+	}
 }
 
 /**
@@ -139,6 +165,28 @@ func (this *TGrid) setWalkableAt(x, y int32, Walkable bool) {
  * @param {DiagonalMovement} diagonalMovement
  */
 func (this *TGrid) GetNeighbors(node *TNode, move DiagonalMovement) ArrayNode {
+	return this.getNeighbors(node, move, this.IsWalkableAt)
+}
+
+/**
+ * Get the neighbors of the given node for an agent covering size x size
+ * cells, node being its top-left cell. Sizes up to 1 are plain
+ * GetNeighbors; larger ones use the clearance map, built on first use.
+ * @param {Node} node
+ * @param {DiagonalMovement} diagonalMovement
+ * @param {number} size
+ */
+func (this *TGrid) GetNeighborsForSize(node *TNode, move DiagonalMovement, size int32) ArrayNode {
+	if size <= 1 {
+		return this.GetNeighbors(node, move)
+	}
+	clearance := this.Clearance()
+	return this.getNeighbors(node, move, func(x, y int) bool {
+		return clearance.Fits(x, y, size)
+	})
+}
+
+func (this *TGrid) getNeighbors(node *TNode, move DiagonalMovement, walkable func(x, y int) bool) ArrayNode {
 	var x = int(node.X)
 	var y = int(node.Y)
 	var neighbors = ArrayNode{}
@@ -155,22 +203,22 @@ func (this *TGrid) GetNeighbors(node *TNode, move DiagonalMovement) ArrayNode {
 	)
 
 	// ↑
-	if this.IsWalkableAt(x, y-1) {
+	if walkable(x, y-1) {
 		neighbors = append(neighbors, nodes[y-1][x])
 		s0 = true
 	}
 	// →
-	if this.IsWalkableAt(x+1, y) {
+	if walkable(x+1, y) {
 		neighbors = append(neighbors, nodes[y][x+1])
 		s1 = true
 	}
 	// ↓
-	if this.IsWalkableAt(x, y+1) {
+	if walkable(x, y+1) {
 		neighbors = append(neighbors, nodes[y+1][x])
 		s2 = true
 	}
 	// ←
-	if this.IsWalkableAt(x-1, y) {
+	if walkable(x-1, y) {
 		neighbors = append(neighbors, nodes[y][x-1])
 		s3 = true
 	}
@@ -199,25 +247,111 @@ func (this *TGrid) GetNeighbors(node *TNode, move DiagonalMovement) ArrayNode {
 	}
 
 	// ↖
-	if d0 && this.IsWalkableAt(x-1, y-1) {
+	if d0 && walkable(x-1, y-1) {
 		neighbors = append(neighbors, nodes[y-1][x-1])
 	}
 	// ↗
-	if d1 && this.IsWalkableAt(x+1, y-1) {
+	if d1 && walkable(x+1, y-1) {
 		neighbors = append(neighbors, nodes[y-1][x+1])
 	}
 	// ↘
-	if d2 && this.IsWalkableAt(x+1, y+1) {
+	if d2 && walkable(x+1, y+1) {
 		neighbors = append(neighbors, nodes[y+1][x+1])
 	}
 	// ↙
-	if d3 && this.IsWalkableAt(x-1, y+1) {
+	if d3 && walkable(x-1, y+1) {
 		neighbors = append(neighbors, nodes[y+1][x-1])
 	}
 
 	return neighbors
 }
 
+/**
+ * Hash the position into the NodeID used when searching the grid.
+ */
+func (this *TGrid) NodeID(x, y int) NodeID {
+	return NodeID(y*this.width + x)
+}
+
+/**
+ * The position of a NodeID built by NodeID.
+ */
+func (this *TGrid) NodeXY(id NodeID) (int, int) {
+	return int(id) % this.width, int(id) / this.width
+}
+
+/**
+ * Convert a path of NodeIDs into {x, y} coordinates.
+ */
+func (this *TGrid) PathCoords(path ArrayNodeID) DoubleInt32 {
+	var coords = DoubleInt32{}
+	for _, id := range path {
+		x, y := this.NodeXY(id)
+		coords = append(coords, ArrayInt32{int32(x), int32(y)})
+	}
+	return coords
+}
+
+/**
+ * Whether the node is walkable, for an agent of opt.AgentSize cells.
+ */
+func (this *TGrid) IsWalkable(id NodeID, opt *Opt) bool {
+	x, y := this.NodeXY(id)
+	if id < 0 {
+		return false
+	}
+	if opt != nil && opt.AgentSize > 1 {
+		return this.Clearance().Fits(x, y, opt.AgentSize)
+	}
+	return this.IsWalkableAt(x, y)
+}
+
+/**
+ * Graph neighbors of the node, following opt.DiagonalMovement and
+ * opt.AgentSize.
+ */
+func (this *TGrid) Neighbors(id NodeID, opt *Opt) ArrayNodeID {
+	x, y := this.NodeXY(id)
+	var ids = ArrayNodeID{}
+	for _, node := range this.GetNeighborsForSize(this.nodes[y][x], opt.DiagonalMovement, opt.AgentSize) {
+		ids = append(ids, this.NodeID(int(node.X), int(node.Y)))
+	}
+	return ids
+}
+
+/**
+ * Cost of a step between two neighbors: 1 straight, SQRT2 diagonally,
+ * times the cost of the cell entered. See SetCostModel for FixedCosts.
+ */
+func (this *TGrid) Cost(from, to NodeID) float64 {
+	x0, y0 := this.NodeXY(from)
+	x1, y1 := this.NodeXY(to)
+	straight, cost := this.StepCosts()
+	if x0 == x1 || y0 == y1 {
+		cost = straight
+	}
+	if this.costs != nil {
+		if this.model == FixedCosts {
+			return fixedCost(cost, this.costs[to])
+		}
+		cost *= this.costs[to]
+	}
+	return cost
+}
+
+/**
+ * opt.Heuristic applied to the absolute coordinate differences.
+ * See SetCostModel for FixedCosts.
+ */
+func (this *TGrid) Heuristic(from, to NodeID, opt *Opt) float64 {
+	x0, y0 := this.NodeXY(from)
+	x1, y1 := this.NodeXY(to)
+	if this.model == FixedCosts {
+		return fixedHeuristic(absInt(x1-x0), absInt(y1-y0), opt.Movement())
+	}
+	return opt.Heuristic(math.Abs(float64(x1-x0)), math.Abs(float64(y1-y0)))
+}
+
 /**
  * Get a clone of this grid.
  * @return {Grid} Cloned grid.
@@ -239,6 +373,11 @@ func (this *TGrid) clone() *TGrid {
 	}
 
 	newGrid.nodes = newNodes
+	if this.costs != nil {
+		newGrid.costs = append([]float64{}, this.costs...)
+	}
+	newGrid.model = this.model
+	newGrid.rehash()
 
 	return newGrid
 }
@@ -0,0 +1,407 @@
+package core
+
+import (
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+/*
+	by stefan 2572915286@qq.com
+	Layered companion of TGrid: a stack of floors (or a voxel volume)
+	with explicit vertical connectors.
+*/
+
+const (
+	SQRT3 = 1.7320508075688772
+)
+
+type TripleNode3D [][][]*TNode3D
+type ArrayNode3D []*TNode3D
+
+type TNode3D struct {
+	X        int32
+	Y        int32
+	Z        int32
+	Walkable bool
+}
+
+func Node3D(x, y, z int32, Walkable bool) *TNode3D {
+	return &TNode3D{
+		X:        x,
+		Y:        y,
+		Z:        z,
+		Walkable: Walkable,
+	}
+}
+
+func (this *TNode3D) IsEqual(node *TNode3D) bool {
+	return this.X == node.X && this.Y == node.Y && this.Z == node.Z
+}
+
+/**
+ * A vertical link between two cells, traversable in both directions.
+ */
+type TConnector struct {
+	Kind ConnectorKind
+	From *TNode3D
+	To   *TNode3D
+	Cost float64
+}
+
+/**
+ * A reachable neighbour together with the cost of stepping onto it.
+ */
+type TEdge3D struct {
+	Node *TNode3D
+	Cost float64
+}
+
+type TGrid3D struct {
+	width      int
+	height     int
+	depth      int
+	nodes      TripleNode3D
+	connectors map[*TNode3D][]*TConnector
+}
+
+/**
+ * The layered Grid class.
+ * @constructor
+ * @param {number} width Number of columns of every floor.
+ * @param {number} height Number of rows of every floor.
+ * @param {number} depth Number of floors.
+ * @param {[]DoubleInt32} [floors] - One 0-1 matrix per floor, indexed by z,
+ *     using the same convention as Grid (0 for Walkable).
+ *     If floors is not supplied, all the nodes will be Walkable.
+ */
+func Grid3D(width, height, depth int, floors []DoubleInt32) *TGrid3D {
+	return &TGrid3D{
+		width:      width,
+		height:     height,
+		depth:      depth,
+		nodes:      buildNodes3D(width, height, depth, floors),
+		connectors: map[*TNode3D][]*TConnector{},
+	}
+}
+
+func buildNodes3D(width, height, depth int, floors []DoubleInt32) TripleNode3D {
+	var nodes = make(TripleNode3D, depth)
+
+	for z := 0; z < depth; z++ {
+		nodes[z] = make([][]*TNode3D, height)
+		for y := 0; y < height; y++ {
+			nodes[z][y] = make([]*TNode3D, width)
+			for x := 0; x < width; x++ {
+				nodes[z][y][x] = Node3D(int32(x), int32(y), int32(z), true)
+			}
+		}
+	}
+
+	if floors == nil {
+		return nodes
+	}
+
+	if len(floors) != depth {
+		akLog.Error("Floor count does not fit")
+		return nodes
+	}
+
+	for z := 0; z < depth; z++ {
+		matrix := floors[z]
+		if matrix == nil {
+			continue
+		}
+		if len(matrix) != height || len(matrix[0]) != width {
+			akLog.Error("Matrix size does not fit, floor: ", z)
+			continue
+		}
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				nodes[z][y][x].Walkable = matrix[y][x] == 0
+			}
+		}
+	}
+
+	return nodes
+}
+
+func (this *TGrid3D) Width() int {
+	return this.width
+}
+
+func (this *TGrid3D) Height() int {
+	return this.height
+}
+
+func (this *TGrid3D) Depth() int {
+	return this.depth
+}
+
+func (this *TGrid3D) GetNodeAt(x, y, z int) *TNode3D {
+	return this.nodes[z][y][x]
+}
+
+/**
+ * Determine whether the node at the given position is Walkable.
+ * (Also returns false if the position is outside the grid.)
+ */
+func (this *TGrid3D) IsWalkableAt(x, y, z int) bool {
+	return this.isInside(x, y, z) && this.nodes[z][y][x].Walkable
+}
+
+func (this *TGrid3D) isInside(x, y, z int) bool {
+	return (x >= 0 && x < this.width) && (y >= 0 && y < this.height) && (z >= 0 && z < this.depth)
+}
+
+/**
+ * Set whether the node on the given position is Walkable.
+ * NOTE: panics if the coordinate is not inside the grid.
+ */
+func (this *TGrid3D) SetWalkableAt(x, y, z int, Walkable bool) {
+	this.nodes[z][y][x].Walkable = Walkable
+}
+
+/**
+ * Link two cells with a stairs, ladder or elevator connector.
+ * The connector can be used in both directions.
+ * @param {ConnectorKind} kind
+ * @param {number} cost - Traversal cost, DefaultConnectorCost(kind) if <= 0.
+ *     Keep it at least the straight-line distance between both ends,
+ *     otherwise the 3D heuristics are no longer admissible.
+ */
+func (this *TGrid3D) AddConnector(kind ConnectorKind, x1, y1, z1, x2, y2, z2 int, cost float64) *TConnector {
+	if !this.isInside(x1, y1, z1) || !this.isInside(x2, y2, z2) {
+		akLog.Error("Connector is outside the grid")
+		return nil
+	}
+	if cost <= 0 {
+		cost = DefaultConnectorCost(kind)
+	}
+	connector := &TConnector{
+		Kind: kind,
+		From: this.nodes[z1][y1][x1],
+		To:   this.nodes[z2][y2][x2],
+		Cost: cost,
+	}
+	this.connectors[connector.From] = append(this.connectors[connector.From], connector)
+	this.connectors[connector.To] = append(this.connectors[connector.To], connector)
+	return connector
+}
+
+/**
+ * Remove every connector attached to the given cell.
+ */
+func (this *TGrid3D) RemoveConnectors(x, y, z int) {
+	node := this.nodes[z][y][x]
+	for _, connector := range this.connectors[node] {
+		other := connector.To
+		if other == node {
+			other = connector.From
+		}
+		kept := this.connectors[other][:0]
+		for _, c := range this.connectors[other] {
+			if c != connector {
+				kept = append(kept, c)
+			}
+		}
+		this.connectors[other] = kept
+	}
+	delete(this.connectors, node)
+}
+
+func (this *TGrid3D) GetConnectors(x, y, z int) []*TConnector {
+	return this.connectors[this.nodes[z][y][x]]
+}
+
+/**
+ * Get the neighbors of the given node, with the cost of each step.
+ *
+ * With the Planar neighborhood, movement stays on the node's floor and
+ * follows move exactly like TGrid.GetNeighbors does. The voxel
+ * neighborhoods (Six, Eighteen, TwentySix) allow every step changing at
+ * most 1, 2 or 3 axes; move then only controls corner cutting:
+ * OnlyWhenNoObstacles needs every intermediate cell to be walkable,
+ * IfAtMostOneObstacle needs at least one single-axis cell to be walkable.
+ * Connectors attached to the node are always followed.
+ * @param {TNode3D} node
+ * @param {Neighborhood3D} neighborhood
+ * @param {DiagonalMovement} move
+ */
+func (this *TGrid3D) GetNeighbors(node *TNode3D, neighborhood Neighborhood3D, move DiagonalMovement) []TEdge3D {
+	var x = int(node.X)
+	var y = int(node.Y)
+	var z = int(node.Z)
+	var neighbors = []TEdge3D{}
+
+	var maxAxes = 0
+	var dzRange = 0
+	switch neighborhood {
+	case Planar:
+		maxAxes = 2
+		if move == Never {
+			maxAxes = 1
+		}
+	case Six:
+		maxAxes = 1
+		dzRange = 1
+	case Eighteen:
+		maxAxes = 2
+		dzRange = 1
+	case TwentySix:
+		maxAxes = 3
+		dzRange = 1
+	default:
+		panic("Incorrect value of neighborhood")
+	}
+
+	for dz := -dzRange; dz <= dzRange; dz++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				axes := absInt(dx) + absInt(dy) + absInt(dz)
+				if axes == 0 || axes > maxAxes {
+					continue
+				}
+				if !this.IsWalkableAt(x+dx, y+dy, z+dz) {
+					continue
+				}
+				if axes > 1 && !this.canCutCorner(x, y, z, dx, dy, dz, move) {
+					continue
+				}
+				var cost = float64(1)
+				if axes == 2 {
+					cost = SQRT2
+				} else if axes == 3 {
+					cost = SQRT3
+				}
+				neighbors = append(neighbors, TEdge3D{Node: this.nodes[z+dz][y+dy][x+dx], Cost: cost})
+			}
+		}
+	}
+
+	for _, connector := range this.connectors[node] {
+		other := connector.To
+		if other == node {
+			other = connector.From
+		}
+		if other.Walkable {
+			neighbors = append(neighbors, TEdge3D{Node: other, Cost: connector.Cost})
+		}
+	}
+
+	return neighbors
+}
+
+/**
+ * Check the cells passed on the way from (x, y, z) to
+ * (x+dx, y+dy, z+dz) against the diagonal movement rule.
+ */
+func (this *TGrid3D) canCutCorner(x, y, z, dx, dy, dz int, move DiagonalMovement) bool {
+	switch move {
+	case OnlyWhenNoObstacles:
+		// every cell of the box spanned by the step, except both ends.
+		for _, cz := range []int{0, dz} {
+			for _, cy := range []int{0, dy} {
+				for _, cx := range []int{0, dx} {
+					if !this.IsWalkableAt(x+cx, y+cy, z+cz) {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	case IfAtMostOneObstacle:
+		return (dx != 0 && this.IsWalkableAt(x+dx, y, z)) ||
+			(dy != 0 && this.IsWalkableAt(x, y+dy, z)) ||
+			(dz != 0 && this.IsWalkableAt(x, y, z+dz))
+	case Always, Never:
+		// Never on a voxel neighborhood is expressed by Six itself.
+		return true
+	}
+	panic("Incorrect value of diagonalMovement")
+}
+
+/**
+ * Hash the position into the NodeID used when searching the grid.
+ */
+func (this *TGrid3D) NodeID(x, y, z int) NodeID {
+	return NodeID((z*this.height+y)*this.width + x)
+}
+
+/**
+ * The position of a NodeID built by NodeID.
+ */
+func (this *TGrid3D) NodeXYZ(id NodeID) (int, int, int) {
+	floor := this.width * this.height
+	return int(id) % this.width, int(id) % floor / this.width, int(id) / floor
+}
+
+/**
+ * Convert a path of NodeIDs into {x, y, z} coordinates.
+ */
+func (this *TGrid3D) PathCoords(path ArrayNodeID) DoubleInt32 {
+	var coords = DoubleInt32{}
+	for _, id := range path {
+		x, y, z := this.NodeXYZ(id)
+		coords = append(coords, ArrayInt32{int32(x), int32(y), int32(z)})
+	}
+	return coords
+}
+
+func (this *TGrid3D) IsWalkable(id NodeID, opt *Opt) bool {
+	x, y, z := this.NodeXYZ(id)
+	return id >= 0 && this.IsWalkableAt(x, y, z)
+}
+
+/**
+ * Graph neighbors of the node, following opt.Neighborhood and
+ * opt.DiagonalMovement.
+ */
+func (this *TGrid3D) Neighbors(id NodeID, opt *Opt) ArrayNodeID {
+	x, y, z := this.NodeXYZ(id)
+	var ids = ArrayNodeID{}
+	for _, edge := range this.GetNeighbors(this.nodes[z][y][x], opt.Neighborhood, opt.DiagonalMovement) {
+		ids = append(ids, this.NodeID(int(edge.Node.X), int(edge.Node.Y), int(edge.Node.Z)))
+	}
+	return ids
+}
+
+/**
+ * Cost of a step between two neighbors: the connector cost when a
+ * connector joins them, the length of the step otherwise.
+ */
+func (this *TGrid3D) Cost(from, to NodeID) float64 {
+	x0, y0, z0 := this.NodeXYZ(from)
+	x1, y1, z1 := this.NodeXYZ(to)
+	var best = -1.0
+	target := this.nodes[z1][y1][x1]
+	for _, connector := range this.connectors[this.nodes[z0][y0][x0]] {
+		if (connector.To == target || connector.From == target) && (best < 0 || connector.Cost < best) {
+			best = connector.Cost
+		}
+	}
+	if best >= 0 {
+		return best
+	}
+	switch absInt(x1-x0) + absInt(y1-y0) + absInt(z1-z0) {
+	case 1:
+		return float64(1)
+	case 2:
+		return SQRT2
+	}
+	return SQRT3
+}
+
+/**
+ * opt.Heuristic3D applied to the absolute coordinate differences.
+ */
+func (this *TGrid3D) Heuristic(from, to NodeID, opt *Opt) float64 {
+	x0, y0, z0 := this.NodeXYZ(from)
+	x1, y1, z1 := this.NodeXYZ(to)
+	return opt.Heuristic3D(float64(absInt(x1-x0)), float64(absInt(y1-y0)), float64(absInt(z1-z0)))
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -1,7 +1,7 @@
 package core
 
 import (
-	"sort"
+	"container/heap"
 )
 
 /*
@@ -9,54 +9,118 @@ import (
 	Based upon https://github.com/qiao/PathFinding.js
 */
 
+/**
+ * Open list of the finders, ordered by `f`, then by the order the nodes
+ * were first pushed: equal `f` values pop first in, first out, whatever
+ * the heap did before, so that identical searches pop identical nodes.
+ * The open lists of a TTieBreaker break the ties after its policy.
+ */
 type GridHeap struct {
-	grids []*AStarGrid
+	grids  gridList
+	pushed uint64
 }
 
+/**
+ * Search state of a graph node, owned by a single FindPath call.
+ */
 type AStarGrid struct {
-	*TNode
+	Id         NodeID
 	F          float64
 	G          float64
-	H          int32
+	H          float64
+	Parent     *AStarGrid
 	Opened     bool
 	Closed     bool
-	Openedflag int // another opened used
+	Openedflag int     // another opened used
+	Tie        float64 // tie key of the TTieBreaker, lower first
+	index      int
+	order      uint64 // pushes before this one, in its heap
 }
 
-func NewGridHeap() *GridHeap {
-	return &GridHeap{
-		grids: []*AStarGrid{},
+type gridList struct {
+	items  []*AStarGrid
+	policy TieBreaking
+}
+
+func (this *gridList) Len() int { return len(this.items) }
+func (this *gridList) Less(i, j int) bool {
+	a, b := this.items[i], this.items[j]
+	if this.policy == TieFIFO {
+		if a.F != b.F {
+			return a.F < b.F
+		}
+		return a.order < b.order
+	}
+	if fa, fb := tieKey(a.F), tieKey(b.F); fa != fb {
+		return fa < fb
 	}
+	switch this.policy {
+	case TieLIFO:
+		return a.order > b.order
+	case TiePreferHigherG:
+		if a.G != b.G {
+			return a.G > b.G
+		}
+	case TieCrossProduct, TieFewerTurns:
+		if a.Tie != b.Tie {
+			return a.Tie < b.Tie
+		}
+	}
+	return a.order < b.order
+}
+func (this *gridList) Swap(i, j int) {
+	this.items[i], this.items[j] = this.items[j], this.items[i]
+	this.items[i].index = i
+	this.items[j].index = j
 }
 
-func (this *GridHeap) autoSort() {
-	sort.Slice(this.grids, func(i, j int) bool {
-		return this.grids[i].F < this.grids[j].F
-	})
+func (this *gridList) Push(x interface{}) {
+	grid := x.(*AStarGrid)
+	grid.index = len(this.items)
+	this.items = append(this.items, grid)
+}
+
+func (this *gridList) Pop() interface{} {
+	old := this.items
+	grid := old[len(old)-1]
+	this.items = old[:len(old)-1]
+	grid.index = -1
+	return grid
+}
+
+func NewGridHeap() *GridHeap {
+	return &GridHeap{}
 }
 
 func (this *GridHeap) Push(new *AStarGrid) {
-	this.grids = append(this.grids, new)
-	//this.autoSort()
+	new.order = this.pushed
+	this.pushed++
+	heap.Push(&this.grids, new)
 }
 
+/**
+ * Pop the grid with the minimum `f` value.
+ */
 func (this *GridHeap) Pop() (grid *AStarGrid) {
-	total := len(this.grids)
-	if total == 0 {
+	if this.grids.Len() == 0 {
 		return nil
 	}
-	grid = this.grids[total-1]
-	this.grids = this.grids[:total-1]
-	return
+	return heap.Pop(&this.grids).(*AStarGrid)
 }
 
 func (this *GridHeap) Empty() bool {
-	return len(this.grids) == 0
+	return this.grids.Len() == 0
+}
+
+func (this *GridHeap) Len() int {
+	return this.grids.Len()
 }
 
+/**
+ * Restore the heap order after the `f` value or the tie key of grid changed.
+ */
 func (this *GridHeap) UpdateItem(grid *AStarGrid) {
-	i := sort.Search(len(this.grids), func(i int) bool { return this.grids[i].X == grid.X && this.grids[i].Y == grid.X })
-	if i < len(this.grids) && (this.grids[i].X == grid.X && this.grids[i].Y == grid.Y) {
-		this.grids[i] = grid
+	if grid.index >= 0 && grid.index < this.grids.Len() && this.grids.items[grid.index] == grid {
+		heap.Fix(&this.grids, grid.index)
 	}
 }
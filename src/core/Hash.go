@@ -0,0 +1,92 @@
+package core
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+/**
+ * splitmix64 finaliser, spreads consecutive keys over the 64 bits.
+ */
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func blockedKey(id NodeID) uint64 {
+	return mix64(uint64(id)<<1 | 1)
+}
+
+func scheduleKey(id NodeID, schedule *TSchedule) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	write := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	write(uint64(id))
+	write(math.Float64bits(schedule.Period))
+	for _, interval := range schedule.Blocked {
+		write(math.Float64bits(interval.Start))
+		write(math.Float64bits(interval.End))
+	}
+	return mix64(h.Sum64() << 1)
+}
+
+// the cost 1 contributes nothing, so that an unset cost and a cost set
+// back to 1 hash the same.
+func costKey(id NodeID, cost float64) uint64 {
+	if cost == 1 {
+		return 0
+	}
+	return mix64(mix64(uint64(id)<<1) ^ math.Float64bits(cost))
+}
+
+// FloatCosts contributes nothing, as for the costs.
+func modelKey(model CostModel) uint64 {
+	if model == FloatCosts {
+		return 0
+	}
+	return mix64(uint64(model) << 56)
+}
+
+/**
+ * Recompute the content hash from scratch.
+ */
+func (this *TGrid) rehash() {
+	this.hash = mix64(uint64(this.width)<<32|uint64(this.height)) ^ modelKey(this.model)
+	for y := 0; y < this.height; y++ {
+		for x := 0; x < this.width; x++ {
+			if !this.nodes[y][x].Walkable {
+				this.hash ^= blockedKey(this.NodeID(x, y))
+			}
+		}
+	}
+	for i, cost := range this.costs {
+		this.hash ^= costKey(NodeID(i), cost)
+	}
+	for id, schedule := range this.schedules {
+		this.scheduleKeys[id] = scheduleKey(id, schedule)
+		this.hash ^= this.scheduleKeys[id]
+	}
+}
+
+/**
+ * Hash of the grid content: its size, the blocked cells, the cell costs,
+ * the cost model and the schedules.
+ * Each cell contributes independently (Zobrist hashing), which lets
+ * SetWalkableAt, SetCostAt and SetSchedule keep it up to date in O(1);
+ * edits made directly to the nodes or to a schedule already set are not
+ * seen.
+ * @return {number} Equal for grids of equal content.
+ */
+func (this *TGrid) ContentHash() uint64 {
+	return this.hash
+}
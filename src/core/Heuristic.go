@@ -1,6 +1,10 @@
 package core
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"sort"
+)
 
 /*
 	by stefan 2572915286@qq.com
@@ -13,7 +17,7 @@ import "math"
  * @param {number} dy - Difference in y.
  * @return {number} dx + dy
  */
-func Manhattan(dx, dy int32) int32 {
+func Manhattan(dx, dy float64) float64 {
 	return dx + dy
 }
 
@@ -23,8 +27,8 @@ func Manhattan(dx, dy int32) int32 {
  * @param {number} dy - Difference in y.
  * @return {number} sqrt(dx * dx + dy * dy)
  */
-func Euclidean(dx, dy int32) int32 {
-	return int32(math.Ceil(math.Sqrt(float64(dx*dx + dy*dy))))
+func Euclidean(dx, dy float64) float64 {
+	return math.Sqrt(dx*dx + dy*dy)
 }
 
 /**
@@ -33,12 +37,12 @@ func Euclidean(dx, dy int32) int32 {
  * @param {number} dy - Difference in y.
  * @return {number} sqrt(dx * dx + dy * dy) for grids
  */
-func Octile(dx, dy int32) int32 {
+func Octile(dx, dy float64) float64 {
 	var F = SQRT2 - float64(1)
 	if dx < dy {
-		return int32(math.Ceil((F*float64(dx) + float64(dy))))
+		return F*dx + dy
 	}
-	return int32(math.Ceil((F*float64(dy) + float64(dx))))
+	return F*dy + dx
 }
 
 /**
@@ -47,6 +51,35 @@ func Octile(dx, dy int32) int32 {
  * @param {number} dy - Difference in y.
  * @return {number} max(dx, dy)
  */
-func Chebyshev(dx, dy int32) int32 {
-	return int32(math.Max(float64(dx), float64(dy)))
+func Chebyshev(dx, dy float64) float64 {
+	return math.Max(dx, dy)
+}
+
+var heuristics = map[string]func(dx, dy float64) float64{
+	"manhattan": Manhattan,
+	"euclidean": Euclidean,
+	"octile":    Octile,
+	"chebyshev": Chebyshev,
+}
+
+/**
+ * Look a heuristic up by its lower-case name, e.g. "octile".
+ */
+func HeuristicByName(name string) (func(dx, dy float64) float64, error) {
+	if heuristic, ok := heuristics[name]; ok {
+		return heuristic, nil
+	}
+	return nil, fmt.Errorf("unknown heuristic %q", name)
+}
+
+/**
+ * Sorted names of the heuristics known to HeuristicByName.
+ */
+func HeuristicNames() []string {
+	names := make([]string, 0, len(heuristics))
+	for name := range heuristics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
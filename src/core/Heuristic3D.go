@@ -0,0 +1,54 @@
+package core
+
+import "math"
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+/**
+ * Manhattan distance in 3D.
+ * @return {number} dx + dy + dz
+ */
+func Manhattan3D(dx, dy, dz float64) float64 {
+	return dx + dy + dz
+}
+
+/**
+ * Euclidean distance in 3D.
+ * @return {number} sqrt(dx * dx + dy * dy + dz * dz)
+ */
+func Euclidean3D(dx, dy, dz float64) float64 {
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+/**
+ * Octile distance in 3D, exact for the TwentySix neighborhood.
+ * @return {number} (sqrt3 - sqrt2) * min + (sqrt2 - 1) * mid + max
+ */
+func Octile3D(dx, dy, dz float64) float64 {
+	lo, mid, hi := sort3(dx, dy, dz)
+	return (SQRT3-SQRT2)*lo + (SQRT2-1)*mid + hi
+}
+
+/**
+ * Chebyshev distance in 3D.
+ * @return {number} max(dx, dy, dz)
+ */
+func Chebyshev3D(dx, dy, dz float64) float64 {
+	_, _, hi := sort3(dx, dy, dz)
+	return hi
+}
+
+func sort3(a, b, c float64) (float64, float64, float64) {
+	if a > b {
+		a, b = b, a
+	}
+	if b > c {
+		b, c = c, b
+	}
+	if a > b {
+		a, b = b, a
+	}
+	return a, b, c
+}
@@ -0,0 +1,41 @@
+package core
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+type Neighborhood3D int
+
+const (
+	// movement stays on the floor (following DiagonalMovement), floors are
+	// only joined through connectors.
+	Planar Neighborhood3D = 0
+	// voxel neighbourhoods: faces, faces+edges, faces+edges+corners.
+	Six       Neighborhood3D = 6
+	Eighteen  Neighborhood3D = 18
+	TwentySix Neighborhood3D = 26
+)
+
+type ConnectorKind int
+
+const (
+	Stairs   ConnectorKind = 1
+	Ladder   ConnectorKind = 2
+	Elevator ConnectorKind = 3
+)
+
+/**
+ * Default traversal cost of a connector kind, used when a connector
+ * is added with a cost <= 0.
+ */
+func DefaultConnectorCost(kind ConnectorKind) float64 {
+	switch kind {
+	case Stairs:
+		return 2
+	case Ladder:
+		return 3
+	case Elevator:
+		return 5
+	}
+	panic("Incorrect value of connector kind")
+}
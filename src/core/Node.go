@@ -12,7 +12,6 @@ type TNode struct {
 	X        int32
 	Y        int32
 	Walkable bool
-	Parent   *TNode
 }
 
 func Node(x int32, y int32, Walkable bool) *TNode {
@@ -20,7 +19,6 @@ func Node(x int32, y int32, Walkable bool) *TNode {
 		X:        x,
 		Y:        y,
 		Walkable: Walkable,
-		Parent:   nil,
 	}
 }
 
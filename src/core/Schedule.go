@@ -0,0 +1,137 @@
+package core
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"math"
+	"sort"
+)
+
+/**
+ * A time interval [Start, End). End may be +Inf.
+ */
+type TInterval struct {
+	Start float64
+	End   float64
+}
+
+/**
+ * Availability schedule of a cell: the cell is blocked during every
+ * interval of Blocked. With Period > 0 the intervals repeat every Period,
+ * which suits doors on a timer and patrolling hazards.
+ */
+type TSchedule struct {
+	Blocked []TInterval
+	Period  float64
+}
+
+/**
+ * Attach a schedule to the cell, nil removes it. Cells without schedule
+ * follow their Walkable flag at any time.
+ */
+func (this *TGrid) SetSchedule(x, y int, schedule *TSchedule) {
+	if this.schedules == nil {
+		this.schedules = map[NodeID]*TSchedule{}
+		this.scheduleKeys = map[NodeID]uint64{}
+	}
+	id := this.NodeID(x, y)
+	this.hash ^= this.scheduleKeys[id]
+	if schedule == nil {
+		delete(this.schedules, id)
+		delete(this.scheduleKeys, id)
+		return
+	}
+	this.schedules[id] = schedule
+	this.scheduleKeys[id] = scheduleKey(id, schedule)
+	this.hash ^= this.scheduleKeys[id]
+}
+
+func (this *TGrid) GetSchedule(x, y int) *TSchedule {
+	return this.schedules[this.NodeID(x, y)]
+}
+
+/**
+ * Whether the grid has any scheduled cell.
+ */
+func (this *TGrid) HasSchedules() bool {
+	return len(this.schedules) > 0
+}
+
+/**
+ * Determine whether the node at the given position is Walkable at time t.
+ */
+func (this *TGrid) IsWalkableAtTime(x, y int, t float64) bool {
+	if !this.IsWalkableAt(x, y) {
+		return false
+	}
+	schedule := this.schedules[this.NodeID(x, y)]
+	if schedule == nil {
+		return true
+	}
+	if schedule.Period > 0 {
+		t = math.Mod(t, schedule.Period)
+		if t < 0 {
+			t += schedule.Period
+		}
+	}
+	for _, blocked := range schedule.Blocked {
+		if t >= blocked.Start && t < blocked.End {
+			return false
+		}
+		// an interval running over the end of the period.
+		if schedule.Period > 0 && t+schedule.Period < blocked.End {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * The safe intervals of the cell: the maximal intervals during which it
+ * is walkable, in time order. Periodic schedules are only unrolled from
+ * the period holding from up to until, the cell being considered blocked
+ * after until, and the intervals then start with that period, not at 0.
+ */
+func (this *TGrid) SafeIntervals(x, y int, from, until float64) []TInterval {
+	if !this.IsWalkableAt(x, y) {
+		return []TInterval{}
+	}
+	schedule := this.schedules[this.NodeID(x, y)]
+	if schedule == nil || len(schedule.Blocked) == 0 {
+		return []TInterval{{Start: 0, End: math.Inf(1)}}
+	}
+
+	var blocked = []TInterval{}
+	var clock = 0.0
+	if schedule.Period > 0 {
+		clock = math.Max(0, math.Floor(from/schedule.Period)*schedule.Period)
+		// a period early, for the intervals running over into the first.
+		for base := clock - schedule.Period; base < until; base += schedule.Period {
+			for _, b := range schedule.Blocked {
+				blocked = append(blocked, TInterval{Start: base + b.Start, End: base + b.End})
+			}
+		}
+		blocked = append(blocked, TInterval{Start: math.Max(until, clock), End: math.Inf(1)})
+	} else {
+		blocked = append(blocked, schedule.Blocked...)
+	}
+	sort.Slice(blocked, func(i, j int) bool {
+		return blocked[i].Start < blocked[j].Start
+	})
+
+	var safe = []TInterval{}
+	for _, b := range blocked {
+		if b.Start > clock {
+			safe = append(safe, TInterval{Start: clock, End: b.Start})
+		}
+		if b.End > clock {
+			clock = b.End
+		}
+	}
+	if !math.IsInf(clock, 1) {
+		safe = append(safe, TInterval{Start: clock, End: math.Inf(1)})
+	}
+	return safe
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+/**
+ * Which of the open nodes of equal `f` the finders expand first, and which
+ * of the parents of equal cost they keep. Many paths of a grid cost the
+ * same; the policy picks the one that looks the most natural, never a
+ * longer one.
+ */
+type TieBreaking int
+
+const (
+	// first opened, first expanded: the default.
+	TieFIFO TieBreaking = 0
+	// last opened, first expanded.
+	TieLIFO TieBreaking = 1
+	// higher `g` first, the nodes nearest to the goal, then FIFO.
+	TiePreferHigherG TieBreaking = 2
+	// nearest to the line from the start to the goal first, then FIFO.
+	TieCrossProduct TieBreaking = 3
+	// reached with fewer direction changes first, then FIFO.
+	TieFewerTurns TieBreaking = 4
+)
+
+var tieBreakingNames = map[TieBreaking]string{
+	TieFIFO:          "fifo",
+	TieLIFO:          "lifo",
+	TiePreferHigherG: "preferHigherG",
+	TieCrossProduct:  "crossProduct",
+	TieFewerTurns:    "fewerTurns",
+}
+
+func (this TieBreaking) String() string {
+	if name, ok := tieBreakingNames[this]; ok {
+		return name
+	}
+	return fmt.Sprintf("TieBreaking(%d)", int(this))
+}
+
+/**
+ * Parse the name of a TieBreaking, as returned by String.
+ */
+func ParseTieBreaking(name string) (TieBreaking, error) {
+	for policy, policyName := range tieBreakingNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown tie breaking %q", name)
+}
+
+/**
+ * Names of the tie breaking policies, in the order of their values.
+ */
+func TieBreakingNames() []string {
+	var names []string
+	for policy := TieFIFO; policy <= TieFewerTurns; policy++ {
+		names = append(names, policy.String())
+	}
+	return names
+}
+
+// tieResolution is the difference under which two costs are a tie for
+// the policies other than FIFO, so that sums of diagonal steps taken in
+// another order still tie.
+const tieResolution = 1e-9
+
+func tieKey(cost float64) float64 {
+	return math.Round(cost / tieResolution)
+}
+
+/**
+ * The tie breaking of a single search: the open list ordered after the
+ * policy, and the tie keys of the nodes. The cross product and fewer
+ * turns policies need the coordinates of a PlanarGraph; on other graphs
+ * they fall back to FIFO.
+ */
+type TTieBreaker struct {
+	policy TieBreaking
+	planar PlanarGraph
+	startX int
+	startY int
+	endX   int
+	endY   int
+}
+
+func NewTieBreaker(policy TieBreaking, graph Graph, start, end NodeID) *TTieBreaker {
+	this := &TTieBreaker{policy: policy}
+	if planar, ok := graph.(PlanarGraph); ok && (policy == TieCrossProduct || policy == TieFewerTurns) {
+		this.planar = planar
+		this.startX, this.startY = planar.NodeXY(start)
+		this.endX, this.endY = planar.NodeXY(end)
+	}
+	return this
+}
+
+/**
+ * An empty open list ordered after the policy.
+ */
+func (this *TTieBreaker) NewHeap() *GridHeap {
+	policy := this.policy
+	if this.planar == nil && (policy == TieCrossProduct || policy == TieFewerTurns) {
+		policy = TieFIFO
+	}
+	heap := NewGridHeap()
+	heap.grids.policy = policy
+	return heap
+}
+
+/**
+ * Whether node, already opened, should be reached from parent at cost g
+ * instead of from its current parent.
+ */
+func (this *TTieBreaker) Better(node, parent *AStarGrid, g float64) bool {
+	if this.planar != nil && this.policy == TieFewerTurns && tieKey(g) == tieKey(node.G) {
+		return this.turns(node, parent) < node.Tie
+	}
+	return g < node.G
+}
+
+/**
+ * Set the parent of node and its tie key after the policy.
+ */
+func (this *TTieBreaker) Link(node, parent *AStarGrid) {
+	if this.planar != nil {
+		switch this.policy {
+		case TieCrossProduct:
+			x, y := this.planar.NodeXY(node.Id)
+			dx1, dy1 := x-this.endX, y-this.endY
+			dx2, dy2 := this.startX-this.endX, this.startY-this.endY
+			node.Tie = math.Abs(float64(dx1*dy2 - dx2*dy1))
+		case TieFewerTurns:
+			node.Tie = this.turns(node, parent)
+		}
+	}
+	node.Parent = parent
+}
+
+// turns counts the direction changes of the path to node through parent,
+// the steps being straight or diagonal segments of any length.
+func (this *TTieBreaker) turns(node, parent *AStarGrid) float64 {
+	if parent.Parent == nil {
+		return 0
+	}
+	if this.direction(parent.Parent, parent) == this.direction(parent, node) {
+		return parent.Tie
+	}
+	return parent.Tie + 1
+}
+
+func (this *TTieBreaker) direction(from, to *AStarGrid) [2]int {
+	x0, y0 := this.planar.NodeXY(from.Id)
+	x1, y1 := this.planar.NodeXY(to.Id)
+	return [2]int{sign(x1 - x0), sign(y1 - y0)}
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+package core
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+/**
+ * Observer of a search, set through Opt.Tracer.
+ * Finders report every node pushed to (OnOpen) and popped from (OnClose)
+ * their open list, with its g and h values.
+ */
+type Tracer interface {
+	OnOpen(id NodeID, g, h float64)
+	OnClose(id NodeID, g, h float64)
+}
+
+/**
+ * Tracer counting the opened and closed nodes of a search.
+ */
+type TSearchStats struct {
+	Opened int
+	Closed int
+}
+
+func (this *TSearchStats) OnOpen(id NodeID, g, h float64) {
+	this.Opened++
+}
+
+func (this *TSearchStats) OnClose(id NodeID, g, h float64) {
+	this.Closed++
+}
+
+/**
+ * Tracer recording the ids of the opened and closed nodes, in the order
+ * of the search. A node reopened with a better g appears again.
+ */
+type TSearchSets struct {
+	Opened ArrayNodeID
+	Closed ArrayNodeID
+}
+
+func (this *TSearchSets) OnOpen(id NodeID, g, h float64) {
+	this.Opened = append(this.Opened, id)
+}
+
+func (this *TSearchSets) OnClose(id NodeID, g, h float64) {
+	this.Closed = append(this.Closed, id)
+}
+
+/**
+ * Tracer passing every event to each of its tracers.
+ */
+type TTracers []Tracer
+
+func (this TTracers) OnOpen(id NodeID, g, h float64) {
+	for _, tracer := range this {
+		tracer.OnOpen(id, g, h)
+	}
+}
+
+func (this TTracers) OnClose(id NodeID, g, h float64) {
+	for _, tracer := range this {
+		tracer.OnClose(id, g, h)
+	}
+}
+
+/**
+ * One event of a recorded search.
+ */
+type TTraceEvent struct {
+	Close bool // closed, otherwise opened
+	ID    NodeID
+	G     float64
+	H     float64
+}
+
+/**
+ * Tracer recording every event of a search, in order, for the renderers
+ * and visualisers replaying it.
+ */
+type TSearchTrace struct {
+	Events []TTraceEvent
+}
+
+func (this *TSearchTrace) OnOpen(id NodeID, g, h float64) {
+	this.Events = append(this.Events, TTraceEvent{ID: id, G: g, H: h})
+}
+
+func (this *TSearchTrace) OnClose(id NodeID, g, h float64) {
+	this.Events = append(this.Events, TTraceEvent{Close: true, ID: id, G: g, H: h})
+}
+
+/**
+ * The rank of every closed node in the order of expansion, from 0.
+ * A node closed again keeps its first rank.
+ */
+func (this *TSearchTrace) ExpansionOrder() map[NodeID]int {
+	var order = map[NodeID]int{}
+	for _, e := range this.Events {
+		if _, ok := order[e.ID]; e.Close && !ok {
+			order[e.ID] = len(order)
+		}
+	}
+	return order
+}
+
+/**
+ * The g value of every closed node when it was first closed.
+ */
+func (this *TSearchTrace) ClosedG() map[NodeID]float64 {
+	var g = map[NodeID]float64{}
+	for _, e := range this.Events {
+		if _, ok := g[e.ID]; e.Close && !ok {
+			g[e.ID] = e.G
+		}
+	}
+	return g
+}
+
+/**
+ * The parents of the nodes opened by the first count events: finders open
+ * the neighbours of a node right after closing it, so the parent of an
+ * opened node is the node closed last before it. A node reopened with a
+ * better g takes its new parent.
+ */
+func (this *TSearchTrace) Parents(count int) map[NodeID]NodeID {
+	var parents = map[NodeID]NodeID{}
+	var current NodeID = -1
+	for _, e := range this.Events[:count] {
+		if e.Close {
+			current = e.ID
+		} else if current >= 0 {
+			parents[e.ID] = current
+		}
+	}
+	return parents
+}
@@ -10,14 +10,14 @@ import "math"
 /**
  * Backtrace according to the Parent records and return the path.
  * (including both start and end nodes)
- * @param {Node} node End node
- * @return {DoubleInt32} the path
+ * @param {AStarGrid} node End node
+ * @return {ArrayNodeID} the path
  */
-func Backtrace(node *TNode) DoubleInt32 {
-	var path = DoubleInt32{ArrayInt32{node.X, node.Y}}
+func Backtrace(node *AStarGrid) ArrayNodeID {
+	var path = ArrayNodeID{node.Id}
 	for node.Parent != nil {
 		node = node.Parent
-		path = append(path, ArrayInt32{node.X, node.Y})
+		path = append(path, node.Id)
 	}
 	Reverse(path)
 	return path
@@ -29,7 +29,7 @@ func Backtrace(node *TNode) DoubleInt32 {
  * @param {Node}
  * @param {Node}
  */
-func BiBacktrace(nodeA, nodeB *TNode) DoubleInt32 {
+func BiBacktrace(nodeA, nodeB *AStarGrid) ArrayNodeID {
 	pathA := Backtrace(nodeA)
 	pathB := Backtrace(nodeB)
 	Reverse(pathB)
@@ -39,19 +39,36 @@ func BiBacktrace(nodeA, nodeB *TNode) DoubleInt32 {
 
 /**
  * Compute the length of the path.
+ * On a grid without cell costs it is the cost of the path, 1 a straight
+ * step and SQRT2 a diagonal one, as accumulated by the finders in `g`.
  * @param {Array<Array<number>>} path The path
  * @return {number} The length of the path
  */
-func PathLength(path DoubleInt32) int {
-	var i, sum int
+func PathLength(path DoubleInt32) float64 {
+	var i int
+	var sum float64
 	var a, b ArrayInt32
-	var dx, dy int32
+	var dx, dy float64
 	for i = 1; i < len(path); i++ {
 		a = path[i-1]
 		b = path[i]
-		dx = a[0] - b[0]
-		dy = a[1] - b[1]
-		sum += int(math.Ceil(math.Sqrt(float64(dx*dx + dy*dy))))
+		dx = float64(a[0] - b[0])
+		dy = float64(a[1] - b[1])
+		sum += math.Sqrt(dx*dx + dy*dy)
+	}
+	return sum
+}
+
+/**
+ * Sum of the graph edge costs along the path.
+ * @param {Graph} graph
+ * @param {ArrayNodeID} path
+ * @return {number} The cost, as accumulated by the finders in `g`
+ */
+func PathCost(graph Graph, path ArrayNodeID) float64 {
+	var sum float64
+	for i := 1; i < len(path); i++ {
+		sum += graph.Cost(path[i-1], path[i])
 	}
 	return sum
 }
@@ -67,6 +67,11 @@ func Reverse(nodes interface{}) {
 		for i, j := 0, vlen-1; i < j; i, j = i+1, j-1 {
 			is[i], is[j] = is[j], is[i]
 		}
+	case ArrayNodeID:
+		is := (data.Interface().(ArrayNodeID))
+		for i, j := 0, vlen-1; i < j; i, j = i+1, j-1 {
+			is[i], is[j] = is[j], is[i]
+		}
 	case DoubleInt64:
 		is := (data.Interface().(DoubleInt64))
 		for i, j := 0, vlen-1; i < j; i, j = i+1, j-1 {
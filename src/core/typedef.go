@@ -26,8 +26,31 @@ type Opt struct {
 	AllowDiagonal    bool
 	DontCrossCorners bool
 	DiagonalMovement DiagonalMovement
-	Heuristic        func(x, y int32) int32
-	Weight           int32
+	Heuristic        func(dx, dy float64) float64
+	Weight           float64
+	Neighborhood     Neighborhood3D
+	Heuristic3D      func(dx, dy, dz float64) float64
+	AgentSize        int32
+	Tracer           Tracer
+	TieBreaking      TieBreaking
+	Done             <-chan struct{}
+}
+
+/**
+ * Whether the search should give up, Done being closed, e.g. by the
+ * deadline of a context. The finders of the registry poll it before each
+ * expansion and then return no path.
+ */
+func (this *Opt) Stopped() bool {
+	if this.Done == nil {
+		return false
+	}
+	select {
+	case <-this.Done:
+		return true
+	default:
+		return false
+	}
 }
 
 type Coordinate struct {
@@ -38,3 +61,15 @@ type Coordinate struct {
 func (this *Coordinate) IsEqual(dst *Coordinate) bool {
 	return this.X == dst.X && this.Y == dst.Y
 }
+
+/**
+ * A position reached at time T, in steps from the start of the plan.
+ * Two consecutive entries at the same position are a wait.
+ */
+type TimedCoordinate struct {
+	X int32
+	Y int32
+	T float64
+}
+
+type TimedPath []TimedCoordinate
@@ -0,0 +1,58 @@
+package AStar3DFinder
+
+/*
+	by stefan 2572915286@qq.com
+	A* over core.TGrid3D, returning (x, y, z) paths.
+*/
+
+import (
+	"go-PathFinding/core"
+	"go-PathFinding/finders/AStarFinder"
+)
+
+type TAStar3DFinder struct {
+	*AStarFinder.TAStarFinder
+}
+
+/**
+ * A* path-finder for layered grids.
+ * @constructor
+ * @param {Object} opt
+ * @param {Neighborhood3D} opt.neighborhood Planar (floors joined only by
+ *     connectors) or a 6, 18 or 26-connected voxel neighborhood.
+ * @param {DiagonalMovement} opt.diagonalMovement Allowed diagonal movement
+ *     on Planar grids, corner cutting on voxel grids.
+ * @param {function} opt.heuristic3D Heuristic function to estimate the
+ *     distance (defaults to Manhattan3D for Planar and Six, Octile3D otherwise).
+ * @param {number} opt.weight Weight to apply to the heuristic.
+ */
+func CreateAStar3DFinder(opt *core.Opt) (this *TAStar3DFinder) {
+	this = &TAStar3DFinder{
+		TAStarFinder: AStarFinder.CreateAStarFinder(opt),
+	}
+
+	if opt.Heuristic3D == nil {
+		if this.FinderOpt.Neighborhood == core.Six ||
+			(this.FinderOpt.Neighborhood == core.Planar && this.FinderOpt.DiagonalMovement == core.Never) {
+			this.FinderOpt.Heuristic3D = core.Manhattan3D
+		} else {
+			this.FinderOpt.Heuristic3D = core.Octile3D
+		}
+	}
+	return
+}
+
+/**
+ * Find and return the the path between two cells of a layered grid.
+ * TAStar3DFinder is also a regular finder: FindPath on NodeIDs built by
+ * TGrid3D.NodeID works on the grid as a core.Graph.
+ * @return {core.DoubleInt32} The path as {x, y, z} triples, including both
+ *     start and end positions. Empty if there is none.
+ */
+func (this *TAStar3DFinder) FindPath3D(startX, startY, startZ, endX, endY, endZ int, grid *core.TGrid3D) core.DoubleInt32 {
+	if !grid.IsWalkableAt(startX, startY, startZ) || !grid.IsWalkableAt(endX, endY, endZ) {
+		return core.DoubleInt32{}
+	}
+	path := this.FindPath(grid.NodeID(startX, startY, startZ), grid.NodeID(endX, endY, endZ), grid)
+	return grid.PathCoords(path)
+}
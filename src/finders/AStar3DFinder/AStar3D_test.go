@@ -0,0 +1,65 @@
+package AStar3DFinder
+
+import (
+	"go-PathFinding/core"
+	"testing"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+func TestAStar3DFinderFloors(t *testing.T) {
+	// two floors split by a wall, the only way across is the ladder
+	// up to the second floor and the stairs back down.
+	floor := core.DoubleInt32{
+		{0, 0, 1, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 1, 0, 0},
+	}
+	grid := core.Grid3D(5, 3, 2, []core.DoubleInt32{floor, nil})
+	grid.AddConnector(core.Ladder, 1, 1, 0, 1, 1, 1, 0)
+	grid.AddConnector(core.Stairs, 3, 1, 1, 3, 1, 0, 0)
+
+	finder := CreateAStar3DFinder(&core.Opt{DiagonalMovement: core.Never})
+	result := finder.FindPath3D(0, 1, 0, 4, 1, 0, grid)
+	akLog.FmtPrintln("result: ", result)
+	if len(result) != 7 {
+		t.Fatalf("unexpected path: %v", result)
+	}
+	if result[2][2] != 1 || result[4][2] != 1 || result[5][2] != 0 {
+		t.Fatalf("path does not use both connectors: %v", result)
+	}
+
+	grid.RemoveConnectors(3, 1, 1)
+	if result = finder.FindPath3D(0, 1, 0, 4, 1, 0, grid); len(result) != 0 {
+		t.Fatalf("path found without stairs: %v", result)
+	}
+}
+
+func TestAStar3DFinderVoxel(t *testing.T) {
+	grid := core.Grid3D(3, 3, 3, nil)
+	for _, neighborhood := range []core.Neighborhood3D{core.Six, core.Eighteen, core.TwentySix} {
+		finder := CreateAStar3DFinder(&core.Opt{
+			DiagonalMovement: core.Always,
+			Neighborhood:     neighborhood,
+			Heuristic3D:      core.Chebyshev3D,
+		})
+		result := finder.FindPath3D(0, 0, 0, 2, 2, 2, grid)
+		akLog.FmtPrintln("neighborhood: ", neighborhood, " result: ", result)
+		var expected = map[core.Neighborhood3D]int{core.Six: 7, core.Eighteen: 4, core.TwentySix: 3}[neighborhood]
+		if len(result) != expected {
+			t.Errorf("neighborhood %v: expected %v nodes, got %v", neighborhood, expected, result)
+		}
+	}
+
+	// a blocked centre forbids cutting through it with OnlyWhenNoObstacles.
+	grid.SetWalkableAt(1, 1, 1, false)
+	finder := CreateAStar3DFinder(&core.Opt{
+		DiagonalMovement: core.OnlyWhenNoObstacles,
+		Neighborhood:     core.TwentySix,
+	})
+	for _, node := range finder.FindPath3D(0, 0, 0, 2, 2, 2, grid) {
+		if node[0] == 1 && node[1] == 1 && node[2] == 1 {
+			t.Fatal("path goes through a blocked voxel")
+		}
+	}
+}
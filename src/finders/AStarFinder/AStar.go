@@ -7,7 +7,7 @@ package AStarFinder
 
 import (
 	"go-PathFinding/core"
-	"math"
+	"go-PathFinding/finders"
 )
 
 type TAStarFinder struct {
@@ -25,18 +25,22 @@ type TAStarFinder struct {
 *     block corners. Deprecated, use diagonalMovement instead.
 * @param {DiagonalMovement} opt.diagonalMovement Allowed diagonal movement.
 * @param {function} opt.heuristic Heuristic function to estimate the distance
-*     (defaults to manhattan).
+*     (defaults to manhattan, octile when moving diagonally).
 * @param {number} opt.weight Weight to apply to the heuristic to allow for
 *     suboptimal paths, in order to speed up the search.
+* @param {number} opt.agentSize Side in cells of the square agent, only
+*     routed through gaps it fits in (defaults to a single cell).
+* @param {Tracer} opt.tracer Optional observer of the opened and closed nodes.
+* @param {<-chan struct{}} opt.done Closed to abort the search, which then
+*     finds no path.
+* @param {TieBreaking} opt.tieBreaking Which of the paths of equal cost is
+*     found (defaults to fifo, the first opened node first).
 */
 
 func CreateAStarFinder(opt *core.Opt) (this *TAStarFinder) {
 	this = &TAStarFinder{
 		FinderOpt: opt,
 	}
-	if opt.Heuristic == nil {
-		this.FinderOpt.Heuristic = core.Manhattan
-	}
 	if opt.Weight == 0 {
 		this.FinderOpt.Weight = 1
 	}
@@ -67,34 +71,39 @@ func CreateAStarFinder(opt *core.Opt) (this *TAStarFinder) {
 	return
 }
 
+func init() {
+	finders.RegisterWithSchema("astar", func(opt *core.Opt) finders.FinderBase {
+		return CreateAStarFinder(opt)
+	}, finders.HeuristicSchema.With(finders.TieBreakingOption))
+}
+
 /**
  * Find and return the the path.
- * @return {core.DoubleInt32} The path, including both start and
- *     end positions.
+ * @param {NodeID} start
+ * @param {NodeID} end
+ * @param {Graph} graph - Any graph, core.TGrid included.
+ * @return {core.ArrayNodeID} The path, including both start and
+ *     end nodes. Empty if there is none.
  */
-func (this *TAStarFinder) FindPath(startX, startY, endX, endY int, grid *core.TGrid) core.DoubleInt32 {
-	var path = core.DoubleInt32{}
+func (this *TAStarFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	var path = core.ArrayNodeID{}
+
+	if !graph.IsWalkable(start, this.FinderOpt) || !graph.IsWalkable(end, this.FinderOpt) {
+		return path
+	}
 
-	var openList = core.NewGridHeap()
+	var ties = core.NewTieBreaker(this.FinderOpt.TieBreaking, graph, start, end)
+	var openList = ties.NewHeap()
 	var startNode = &core.AStarGrid{
-		TNode:  grid.GetNodeAt(startX, startY),
+		Id:     start,
 		F:      0.0,
 		G:      0.0,
 		H:      0,
 		Opened: false,
 		Closed: false,
 	}
-	endNode := grid.GetNodeAt(endX, endY)
-	heuristic := this.FinderOpt.Heuristic
-	diagonalMovement := this.FinderOpt.DiagonalMovement
 	weight := this.FinderOpt.Weight
 
-	// var node, neighbor *core.AStarGrid
-	// var neighbors core.ArrayNode
-	//var i, l int
-	var x, y int32
-	var ng float64
-
 	// set the `g` and `f` value of the start node to be 0
 	startNode.G = 0.0
 	startNode.F = 0.0
@@ -102,62 +111,68 @@ func (this *TAStarFinder) FindPath(startX, startY, endX, endY int, grid *core.TG
 	// push the start node into the open list
 	openList.Push(startNode)
 	startNode.Opened = true
+	tracer := this.FinderOpt.Tracer
+	if tracer != nil {
+		tracer.OnOpen(start, startNode.G, startNode.H)
+	}
 
-	// record walked positions
-	var walkedMap = map[string]bool{}
+	// search state of every node seen so far
+	var nodes = map[core.NodeID]*core.AStarGrid{start: startNode}
 
 	// while the open list is not empty
 	for !openList.Empty() {
 		// pop the position of node which has the minimum `f` value.
+		if this.FinderOpt.Stopped() {
+			return path
+		}
 		node := openList.Pop()
 		node.Closed = true
-		walkedMap[core.NodeGroupStr(node.X, node.Y)] = true
+		if tracer != nil {
+			tracer.OnClose(node.Id, node.G, node.H)
+		}
 
 		// if reached the end position, construct the path and return it
-		if node.IsEqual(endNode) {
-			return core.Backtrace(endNode)
+		if node.Id == end {
+			return core.Backtrace(node)
 		}
 
 		// get neigbours of the current node
-		neighbors := grid.GetNeighbors(node.TNode, diagonalMovement)
+		neighbors := graph.Neighbors(node.Id, this.FinderOpt)
 		for i := 0; i < len(neighbors); i++ {
-			if walkedMap[core.NodeGroupStr(neighbors[i].X, neighbors[i].Y)] {
-				continue
-			}
-
-			neighbor := &core.AStarGrid{
-				TNode:  neighbors[i],
-				F:      0.0,
-				G:      0.0,
-				H:      0,
-				Opened: false,
-				Closed: false,
+			neighbor := nodes[neighbors[i]]
+			if neighbor == nil {
+				neighbor = &core.AStarGrid{
+					Id:     neighbors[i],
+					F:      0.0,
+					G:      0.0,
+					H:      0,
+					Opened: false,
+					Closed: false,
+				}
+				nodes[neighbors[i]] = neighbor
 			}
 
 			if neighbor.Closed {
 				continue
 			}
 
-			x = neighbor.X
-			y = neighbor.Y
-
 			// get the distance between current node and the neighbor
 			// and calculate the next g score
-			if x-node.X == 0 || y-node.Y == 0 {
-				ng = node.G + float64(1)
-			} else {
-				ng = node.G + core.SQRT2
-			}
+			ng := node.G + graph.Cost(node.Id, neighbor.Id)
 
 			// check if the neighbor has not been inspected yet, or
-			// can be reached with smaller cost from the current node
-			if !neighbor.Opened || ng < neighbor.G {
+			// can be reached with smaller cost from the current node,
+			// or with the same cost but a path the tie breaking prefers
+			if !neighbor.Opened || ties.Better(neighbor, node, ng) {
 				neighbor.G = ng
-				if neighbor.H == 0 {
-					neighbor.H = weight * heuristic(int32(math.Abs(float64(x-int32(endX)))), int32(math.Abs(float64((y-int32(endY))))))
+				if !neighbor.Opened {
+					neighbor.H = weight * graph.Heuristic(neighbor.Id, end, this.FinderOpt)
+				}
+				neighbor.F = neighbor.G + neighbor.H
+				ties.Link(neighbor, node)
+				if tracer != nil {
+					tracer.OnOpen(neighbor.Id, neighbor.G, neighbor.H)
 				}
-				neighbor.F = neighbor.G + float64(neighbor.H)
-				neighbor.Parent = node.TNode
 
 				if !neighbor.Opened {
 					openList.Push(neighbor)
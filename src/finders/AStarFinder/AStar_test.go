@@ -2,7 +2,11 @@ package AStarFinder
 
 import (
 	"go-PathFinding/core"
+	"go-PathFinding/finders"
 	"go-PathFinding/finders/config"
+	"go-PathFinding/finders/conformance"
+	"math"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -23,8 +27,208 @@ func TestAStarFinder(t *testing.T) {
 			Weight:           0,
 		}
 		finder := CreateAStarFinder(opt)
-		result := finder.FindPath(item.StartX, item.StartY, item.EndX, item.EndY, grid)
+		result := grid.PathCoords(finder.FindPath(grid.NodeID(item.StartX, item.StartY), grid.NodeID(item.EndX, item.EndY), grid))
 		akLog.FmtPrintln("result: ", result, "\n", float64(time.Since(itemnow).Nanoseconds())/float64(1e9))
 	}
 	akLog.FmtPrintln("spend: ", float64(time.Since(now).Nanoseconds())/float64(1e9))
 }
+
+// a small road network: the direct road 0-3 is longer than 0-1-2-3.
+type roadGraph map[core.NodeID]map[core.NodeID]float64
+
+func (this roadGraph) Neighbors(id core.NodeID, opt *core.Opt) core.ArrayNodeID {
+	var ids = core.ArrayNodeID{}
+	for other := range this[id] {
+		ids = append(ids, other)
+	}
+	return ids
+}
+
+func (this roadGraph) Cost(from, to core.NodeID) float64 {
+	return this[from][to]
+}
+
+func (this roadGraph) Heuristic(from, to core.NodeID, opt *core.Opt) float64 {
+	return 0
+}
+
+func (this roadGraph) IsWalkable(id core.NodeID, opt *core.Opt) bool {
+	return this[id] != nil
+}
+
+func TestAStarFinderGraph(t *testing.T) {
+	graph := roadGraph{
+		0: {1: 2, 3: 10},
+		1: {0: 2, 2: 3},
+		2: {1: 3, 3: 1},
+		3: {0: 10, 2: 1},
+		4: {},
+	}
+	finder := CreateAStarFinder(&core.Opt{})
+	result := finder.FindPath(0, 3, graph)
+	if len(result) != 4 || result[1] != 1 || result[2] != 2 {
+		t.Fatalf("unexpected path: %v", result)
+	}
+	if result = finder.FindPath(0, 4, graph); len(result) != 0 {
+		t.Fatalf("unexpected path: %v", result)
+	}
+}
+
+func TestAStarFinderAgentSize(t *testing.T) {
+	// the only gap in the wall is 2 cells wide.
+	grid := core.Grid(7, 9, core.DoubleInt32{
+		{0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0},
+		{1, 1, 1, 0, 0, 1, 1},
+		{1, 1, 1, 0, 0, 1, 1},
+		{0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0},
+	})
+	finder := CreateAStarFinder(&core.Opt{DiagonalMovement: core.Never, AgentSize: 2})
+	if result := finder.FindPath(grid.NodeID(0, 0), grid.NodeID(0, 7), grid); len(result) == 0 {
+		t.Fatal("2x2 agent found no path")
+	}
+
+	finder = CreateAStarFinder(&core.Opt{DiagonalMovement: core.Never, AgentSize: 3})
+	if result := finder.FindPath(grid.NodeID(0, 0), grid.NodeID(0, 6), grid); len(result) != 0 {
+		t.Fatalf("3x3 agent went through a 2 cells gap: %v", grid.PathCoords(result))
+	}
+
+	// widen the gap, the clearance map follows.
+	for y := 3; y <= 4; y++ {
+		grid.SetWalkableAt(5, y, true)
+	}
+	result := finder.FindPath(grid.NodeID(0, 0), grid.NodeID(0, 6), grid)
+	if len(result) == 0 {
+		t.Fatal("3x3 agent found no path through the widened gap")
+	}
+	for _, id := range result {
+		x, y := grid.NodeXY(id)
+		if grid.Clearance().At(x, y) < 3 {
+			t.Fatalf("3x3 agent does not fit at (%v, %v)", x, y)
+		}
+	}
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(opt *core.Opt) finders.FinderBase {
+		return CreateAStarFinder(opt)
+	}, conformance.TClaims{Optimal: true})
+}
+
+func TestAStarFinderWeight(t *testing.T) {
+	// diagonal searches default to the octile heuristic.
+	if opt := CreateAStarFinder(&core.Opt{DiagonalMovement: core.Always}).FinderOpt; opt.Heuristic(3, 4) != core.Octile(3, 4) {
+		t.Fatalf("default heuristic %v under diagonal movement", opt.Heuristic(3, 4))
+	}
+
+	// weighted paths cost at most weight times the shortest one, and their
+	// length is the cost accumulated in g on a grid without costs.
+	rnd := rand.New(rand.NewSource(3))
+	for round := 0; round < 30; round++ {
+		matrix := make(core.DoubleInt32, 20)
+		for y := range matrix {
+			matrix[y] = make(core.ArrayInt32, 20)
+			for x := range matrix[y] {
+				if rnd.Float64() < 0.25 {
+					matrix[y][x] = 1
+				}
+			}
+		}
+		grid := core.Grid(20, 20, matrix)
+		start, end := grid.NodeID(0, 0), grid.NodeID(19, 19)
+		grid.SetWalkableAt(0, 0, true)
+		grid.SetWalkableAt(19, 19, true)
+		shortest := CreateAStarFinder(&core.Opt{DiagonalMovement: core.OnlyWhenNoObstacles}).FindPath(start, end, grid)
+		weighted := CreateAStarFinder(&core.Opt{DiagonalMovement: core.OnlyWhenNoObstacles, Weight: 1.5}).FindPath(start, end, grid)
+		if len(shortest) == 0 {
+			continue
+		}
+		cost, bound := core.PathCost(grid, weighted), 1.5*core.PathCost(grid, shortest)
+		if len(weighted) == 0 || cost > bound+1e-9 {
+			t.Fatalf("round %d: weighted path %v costs %v, bound %v", round, grid.PathCoords(weighted), cost, bound)
+		}
+		if length := core.PathLength(grid.PathCoords(weighted)); math.Abs(length-cost) > 1e-9 {
+			t.Fatalf("round %d: length %v, cost %v", round, length, cost)
+		}
+	}
+}
+
+// turns counts the direction changes of a path.
+func turns(path core.DoubleInt32) int {
+	count := 0
+	for i := 2; i < len(path); i++ {
+		if path[i][0]-path[i-1][0] != path[i-1][0]-path[i-2][0] || path[i][1]-path[i-1][1] != path[i-1][1]-path[i-2][1] {
+			count++
+		}
+	}
+	return count
+}
+
+// deviation is the furthest a path strays from the line between its ends,
+// in cells.
+func deviation(path core.DoubleInt32) float64 {
+	first, last := path[0], path[len(path)-1]
+	dx, dy := float64(last[0]-first[0]), float64(last[1]-first[1])
+	furthest := 0.0
+	for _, point := range path {
+		cross := math.Abs(float64(point[0]-first[0])*dy - float64(point[1]-first[1])*dx)
+		furthest = math.Max(furthest, cross/math.Hypot(dx, dy))
+	}
+	return furthest
+}
+
+func TestAStarFinderTieBreaking(t *testing.T) {
+	policies := []core.TieBreaking{core.TieFIFO, core.TieLIFO, core.TiePreferHigherG, core.TieCrossProduct, core.TieFewerTurns}
+
+	// on an open grid, the cross product follows the line to the goal and
+	// fewer turns goes round a single corner.
+	for _, move := range []core.DiagonalMovement{core.Never, core.Always} {
+		grid := core.Grid(30, 30, nil)
+		start, end := grid.NodeID(2, 3), grid.NodeID(25, 14)
+		shortest := core.PathCost(grid, CreateAStarFinder(&core.Opt{DiagonalMovement: move}).FindPath(start, end, grid))
+		for _, policy := range policies {
+			path := grid.PathCoords(CreateAStarFinder(&core.Opt{DiagonalMovement: move, TieBreaking: policy}).FindPath(start, end, grid))
+			akLog.FmtPrintln(move, policy, "turns", turns(path), "deviation", deviation(path))
+			if cost := core.PathLength(path); math.Abs(cost-shortest) > 1e-9 {
+				t.Fatalf("%v %v: cost %v, expected %v", move, policy, cost, shortest)
+			}
+			if policy == core.TieCrossProduct && deviation(path) >= 1 {
+				t.Fatalf("%v: path %v strays %v from the line", move, path, deviation(path))
+			}
+			if policy == core.TieFewerTurns && turns(path) != 1 {
+				t.Fatalf("%v: path %v turns %d times", move, path, turns(path))
+			}
+		}
+	}
+
+	// ties never cost a longer path.
+	rnd := rand.New(rand.NewSource(5))
+	for round := 0; round < 30; round++ {
+		matrix := make(core.DoubleInt32, 20)
+		for y := range matrix {
+			matrix[y] = make(core.ArrayInt32, 20)
+			for x := range matrix[y] {
+				if rnd.Float64() < 0.3 {
+					matrix[y][x] = 1
+				}
+			}
+		}
+		grid := core.Grid(20, 20, matrix)
+		start, end := grid.NodeID(0, 0), grid.NodeID(19, 19)
+		grid.SetWalkableAt(0, 0, true)
+		grid.SetWalkableAt(19, 19, true)
+		for _, move := range []core.DiagonalMovement{core.Never, core.OnlyWhenNoObstacles} {
+			shortest := core.PathCost(grid, CreateAStarFinder(&core.Opt{DiagonalMovement: move}).FindPath(start, end, grid))
+			for _, policy := range policies {
+				path := CreateAStarFinder(&core.Opt{DiagonalMovement: move, TieBreaking: policy}).FindPath(start, end, grid)
+				if cost := core.PathCost(grid, path); math.Abs(cost-shortest) > 1e-9 {
+					t.Fatalf("round %d %v %v: cost %v, expected %v", round, move, policy, cost, shortest)
+				}
+			}
+		}
+	}
+}
@@ -7,8 +7,8 @@ package BiAStarFinder
 
 import (
 	"go-PathFinding/core"
+	"go-PathFinding/finders"
 	"go-PathFinding/finders/AStarFinder"
-	"math"
 )
 
 type BiAStarFinder struct {
@@ -26,9 +26,11 @@ type BiAStarFinder struct {
  *     block corners. Deprecated, use diagonalMovement instead.
  * @param {DiagonalMovement} opt.diagonalMovement Allowed diagonal movement.
  * @param {function} opt.heuristic Heuristic function to estimate the distance
- *     (defaults to manhattan).
+ *     (defaults to manhattan, octile when moving diagonally).
  * @param {number} opt.weight Weight to apply to the heuristic to allow for
  *     suboptimal paths, in order to speed up the search.
+ * @param {TieBreaking} opt.tieBreaking Order of the nodes of equal cost,
+ *     each side breaking ties towards its own target.
  */
 
 func CreateBiAStarFinder(opt *core.Opt) (this *BiAStarFinder) {
@@ -37,17 +39,37 @@ func CreateBiAStarFinder(opt *core.Opt) (this *BiAStarFinder) {
 	}
 }
 
+func init() {
+	finders.RegisterWithSchema("biastar", func(opt *core.Opt) finders.FinderBase {
+		return CreateBiAStarFinder(opt)
+	}, finders.HeuristicSchema.With(finders.TieBreakingOption))
+}
+
 /**
  * Find and return the the path.
- * @return {core.DoubleInt32} The path, including both start and
- *     end positions.
+ * Both searches walk the graph edges, the one from the end node
+ * backwards, so the graph is expected to be undirected.
+ * @param {NodeID} start
+ * @param {NodeID} end
+ * @param {Graph} graph - Any graph, core.TGrid included.
+ * @return {core.ArrayNodeID} The path, including both start and
+ *     end nodes. Empty if there is none.
  */
-func (this *BiAStarFinder) FindPath(startX, startY, endX, endY int, grid *core.TGrid) core.DoubleInt32 {
-	var startOpenList = core.NewGridHeap()
-	var endOpenList = core.NewGridHeap()
+func (this *BiAStarFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	if !graph.IsWalkable(start, this.FinderOpt) || !graph.IsWalkable(end, this.FinderOpt) {
+		return core.ArrayNodeID{}
+	}
+	if start == end {
+		return core.ArrayNodeID{start}
+	}
+
+	var startTies = core.NewTieBreaker(this.FinderOpt.TieBreaking, graph, start, end)
+	var endTies = core.NewTieBreaker(this.FinderOpt.TieBreaking, graph, end, start)
+	var startOpenList = startTies.NewHeap()
+	var endOpenList = endTies.NewHeap()
 
 	var startNode = &core.AStarGrid{
-		TNode:  grid.GetNodeAt(startX, startY),
+		Id:     start,
 		F:      0.0,
 		G:      0.0,
 		H:      0,
@@ -56,7 +78,7 @@ func (this *BiAStarFinder) FindPath(startX, startY, endX, endY int, grid *core.T
 	}
 
 	var endNode = &core.AStarGrid{
-		TNode:  grid.GetNodeAt(endX, endY),
+		Id:     end,
 		F:      0.0,
 		G:      0.0,
 		H:      0,
@@ -64,136 +86,87 @@ func (this *BiAStarFinder) FindPath(startX, startY, endX, endY int, grid *core.T
 		Closed: false,
 	}
 
-	heuristic := this.FinderOpt.Heuristic
-	diagonalMovement := this.FinderOpt.DiagonalMovement
 	weight := this.FinderOpt.Weight
 
-	var ng float64
 	var BY_START = 1
 	var BY_END = 2
 
 	// set the `g` and `f` value of the start node to be 0
 	// and push it into the start open list
-	startNode.G = 0.0
-	startNode.F = 0.0
 	startOpenList.Push(startNode)
 	startNode.Openedflag = BY_START
 
 	// set the `g` and `f` value of the end node to be 0
 	// and push it into the open open list
-	endNode.G = 0.0
-	endNode.F = 0.0
 	endOpenList.Push(endNode)
 	endNode.Openedflag = BY_END
 
-	// record walked positions
-	var walkedMap = map[string]*core.AStarGrid{}
-
-	//check produce repeat parent.
-	checkNodeParentSame := func(src, dst *core.TNode) bool {
-		srcParent := src.Parent
-		for srcParent != nil {
-			if core.NodeGroupStr(src.Parent.X, src.Parent.Y) == core.NodeGroupStr(dst.X, dst.Y) {
-				return true
-			}
-			srcParent = srcParent.Parent
-		}
-		return false
-	}
-
-	//check and sort path right.
-	sortPathOrder := func(src, dst *core.TNode) core.DoubleInt32 {
-		result := core.BiBacktrace(src, dst)
-		fistCoord := core.Array2Coordinate(result[0])
-		lastCoord := core.Array2Coordinate(result[len(result)-1])
-		if (fistCoord.X != startNode.TNode.X && fistCoord.Y != startNode.TNode.Y) &&
-			(lastCoord.X != endNode.TNode.X && lastCoord.Y != endNode.TNode.Y) {
-			core.Reverse(result)
-		} else if !(fistCoord.X == startNode.TNode.X && fistCoord.Y == startNode.TNode.Y &&
-			lastCoord.X == endNode.TNode.X && lastCoord.Y == endNode.TNode.Y) {
-			if fistCoord.X == lastCoord.X && fistCoord.Y == lastCoord.Y &&
-				(startOpenList.Empty() || endOpenList.Empty()) {
-				result = result[:len(result)-1]
-			} else {
-				result = nil
-			}
-		}
-		return result
+	tracer := this.FinderOpt.Tracer
+	if tracer != nil {
+		tracer.OnOpen(start, 0, 0)
+		tracer.OnOpen(end, 0, 0)
 	}
 
-	//check parent find end node.
-	isLinkEndNode := func(node *core.TNode) bool {
-		if node.Parent == nil {
-			return false
-		}
-		return node.Parent.IsEqual(startNode.TNode) && node.IsEqual(endNode.TNode)
-	}
+	// search state of every node seen by either side
+	var nodes = map[core.NodeID]*core.AStarGrid{start: startNode, end: endNode}
 
-	getPathNodes := func(list *core.GridHeap, endflag int, openflag int) core.DoubleInt32 {
-		// pop the position of start node which has the minimum `f` value.
+	// expand the best node of list, returns the path once both sides meet.
+	expand := func(list *core.GridHeap, ties *core.TTieBreaker, openflag int, target core.NodeID) core.ArrayNodeID {
+		// pop the position of node which has the minimum `f` value.
 		node := list.Pop()
-		node.Closed = false
-
-		// for short distance path find.
-		if isLinkEndNode(node.TNode) {
-			return core.DoubleInt32{
-				core.ArrayInt32{startNode.X, startNode.Y},
-				core.ArrayInt32{endNode.X, endNode.Y}}
+		node.Closed = true
+		if tracer != nil {
+			tracer.OnClose(node.Id, node.G, node.H)
 		}
 
-		walkedMap[core.NodeGroupStr(node.X, node.Y)] = node
-
 		// get neigbours of the current node
-		neighbors := grid.GetNeighbors(node.TNode, diagonalMovement)
+		neighbors := graph.Neighbors(node.Id, this.FinderOpt)
 		for i := 0; i < len(neighbors); i++ {
-			if checkNodeParentSame(node.TNode, neighbors[i]) {
-				continue
-			}
-
-			neighbor := &core.AStarGrid{
-				TNode:  neighbors[i],
-				F:      0.0,
-				G:      0.0,
-				H:      0,
-				Opened: false,
-				Closed: false,
-			}
-
-			walkedNode := walkedMap[core.NodeGroupStr(neighbors[i].X, neighbors[i].Y)]
-			if walkedNode != nil {
-				neighbor = walkedNode
-			}
-
-			if neighbor.Openedflag == endflag {
-				return sortPathOrder(node.TNode, neighbor.TNode)
+			neighbor := nodes[neighbors[i]]
+			if neighbor == nil {
+				neighbor = &core.AStarGrid{
+					Id:     neighbors[i],
+					F:      0.0,
+					G:      0.0,
+					H:      0,
+					Opened: false,
+					Closed: false,
+				}
+				nodes[neighbors[i]] = neighbor
 			}
 
 			if neighbor.Closed {
 				continue
 			}
 
-			x := neighbor.X
-			y := neighbor.Y
+			if neighbor.Openedflag != 0 && neighbor.Openedflag != openflag {
+				if openflag == BY_START {
+					return core.BiBacktrace(node, neighbor)
+				}
+				return core.BiBacktrace(neighbor, node)
+			}
 
 			// get the distance between current node and the neighbor
 			// and calculate the next g score
-			if x-node.X == 0 || y-node.Y == 0 {
-				ng = node.G + float64(1)
+			var ng float64
+			if openflag == BY_START {
+				ng = node.G + graph.Cost(node.Id, neighbor.Id)
 			} else {
-				ng = node.G + core.SQRT2
+				ng = node.G + graph.Cost(neighbor.Id, node.Id)
 			}
 
 			// check if the neighbor has not been inspected yet, or
-			// can be reached with smaller cost from the current node
-			if neighbor.Openedflag == 0 || ng < neighbor.G {
+			// can be reached with smaller cost from the current node,
+			// or with the same cost but a path the tie breaking prefers
+			if neighbor.Openedflag == 0 || ties.Better(neighbor, node, ng) {
 				neighbor.G = ng
-				if neighbor.H == 0 {
-					neighbor.H = weight * heuristic(int32(math.Abs(float64(x-int32(endX)))), int32(math.Abs(float64((y-int32(endY))))))
+				if neighbor.Openedflag == 0 {
+					neighbor.H = weight * graph.Heuristic(neighbor.Id, target, this.FinderOpt)
 				}
-				neighbor.F = neighbor.G + float64(neighbor.H)
-
-				if neighbor.Parent == nil {
-					neighbor.Parent = node.TNode
+				neighbor.F = neighbor.G + neighbor.H
+				ties.Link(neighbor, node)
+				if tracer != nil {
+					tracer.OnOpen(neighbor.Id, neighbor.G, neighbor.H)
 				}
 
 				if neighbor.Openedflag == 0 {
@@ -207,24 +180,23 @@ func (this *BiAStarFinder) FindPath(startX, startY, endX, endY int, grid *core.T
 				}
 			}
 		} // end for each neighbor
-		// fail to find the path
-		return core.DoubleInt32{}
+		return nil
 	}
 
 	// while both the open lists are not empty
 	for !startOpenList.Empty() && !endOpenList.Empty() {
-
-		startRet := getPathNodes(startOpenList, BY_END, BY_START)
-		if len(startRet) != 0 {
-			return startRet
+		if this.FinderOpt.Stopped() {
+			break
+		}
+		if path := expand(startOpenList, startTies, BY_START, end); path != nil {
+			return path
 		}
 
-		endRet := getPathNodes(endOpenList, BY_START, BY_END)
-		if len(endRet) != 0 {
-			return endRet
+		if path := expand(endOpenList, endTies, BY_END, start); path != nil {
+			return path
 		}
 	} // end while not open list empty
 
 	// fail to find the path
-	return core.DoubleInt32{}
+	return core.ArrayNodeID{}
 }
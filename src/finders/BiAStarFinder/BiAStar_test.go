@@ -2,7 +2,10 @@ package BiAStarFinder
 
 import (
 	"go-PathFinding/core"
+	"go-PathFinding/finders"
 	"go-PathFinding/finders/config"
+	"go-PathFinding/finders/conformance"
+	"math"
 	"testing"
 	"time"
 
@@ -23,8 +26,42 @@ func TestBiAStarFinder(t *testing.T) {
 			Weight:           0,
 		}
 		finder := CreateBiAStarFinder(opt)
-		result := finder.FindPath(item.StartX, item.StartY, item.EndX, item.EndY, grid)
+		result := grid.PathCoords(finder.FindPath(grid.NodeID(item.StartX, item.StartY), grid.NodeID(item.EndX, item.EndY), grid))
 		akLog.FmtPrintln("result: ", result, "\n", float64(time.Since(itemnow).Nanoseconds())/float64(1e9))
 	}
 	akLog.FmtPrintln("spend: ", float64(time.Since(now).Nanoseconds())/float64(1e9))
 }
+
+func TestConformance(t *testing.T) {
+	// bidirectional search meets halfway, not always on a shortest path.
+	conformance.Run(t, func(opt *core.Opt) finders.FinderBase {
+		return CreateBiAStarFinder(opt)
+	}, conformance.TClaims{})
+}
+
+func TestBiAStarFinderTieBreaking(t *testing.T) {
+	// each side breaks its ties towards its own target: on an open grid the
+	// cross product keeps the path along the line, fewer turns turns once.
+	grid := core.Grid(30, 30, nil)
+	start, end := grid.NodeID(2, 3), grid.NodeID(25, 14)
+	dx, dy := 23.0, 11.0
+	for _, move := range []core.DiagonalMovement{core.Never, core.Always, core.OnlyWhenNoObstacles} {
+		path := grid.PathCoords(CreateBiAStarFinder(&core.Opt{DiagonalMovement: move, TieBreaking: core.TieCrossProduct}).FindPath(start, end, grid))
+		for _, point := range path {
+			if cross := math.Abs(float64(point[0]-2)*dy - float64(point[1]-3)*dx); cross/math.Hypot(dx, dy) >= 1 {
+				t.Fatalf("%v: crossProduct path %v strays from the line at %v", move, path, point)
+			}
+		}
+
+		path = grid.PathCoords(CreateBiAStarFinder(&core.Opt{DiagonalMovement: move, TieBreaking: core.TieFewerTurns}).FindPath(start, end, grid))
+		turns := 0
+		for i := 2; i < len(path); i++ {
+			if path[i][0]-path[i-1][0] != path[i-1][0]-path[i-2][0] || path[i][1]-path[i-1][1] != path[i-1][1]-path[i-2][1] {
+				turns++
+			}
+		}
+		if turns != 1 {
+			t.Fatalf("%v: fewerTurns path %v turns %d times", move, path, turns)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package finders_test
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"testing"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	_ "go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/BiAStarFinder"
+	_ "go-PathFinding/finders/DijkstraFinder"
+	_ "go-PathFinding/finders/JumpPointFinder"
+	_ "go-PathFinding/finders/SIPPFinder"
+	"go-PathFinding/mapf"
+)
+
+// the finders of the determinism test, whatever else the tests link in.
+var fixedFinders = []string{"astar", "biastar", "dijkstra", "jps", "sipp"}
+
+func hashUint64(h hash.Hash64, value uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], value)
+	h.Write(buf[:])
+}
+
+// fixedPathsHash searches random grids under FixedCosts with every finder,
+// and plans a few agents on them with CBS and cooperative A*, and hashes
+// the paths found.
+func fixedPathsHash(t *testing.T) uint64 {
+	h := fnv.New64a()
+	rnd := rand.New(rand.NewSource(49))
+	for round := 0; round < 12; round++ {
+		width, height := 16+rnd.Intn(16), 16+rnd.Intn(16)
+		grid := core.Grid(width, height, nil)
+		grid.SetCostModel(core.FixedCosts)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				switch r := rnd.Float64(); {
+				case r < 0.25:
+					grid.SetWalkableAt(x, y, false)
+				case r < 0.4 && round%2 == 1:
+					grid.SetCostAt(x, y, 1+float64(rnd.Intn(8))/4)
+				}
+			}
+		}
+		start := grid.NodeID(rnd.Intn(width), rnd.Intn(height))
+		end := grid.NodeID(rnd.Intn(width), rnd.Intn(height))
+		for _, name := range fixedFinders {
+			schema, _ := finders.Schema(name)
+			for _, move := range []string{"never", "always", "ifAtMostOneObstacle", "onlyWhenNoObstacles"} {
+				for _, weight := range []float64{1, 1.5} {
+					config := map[string]interface{}{"diagonalMovement": move}
+					if schema.Lookup("weight") != nil {
+						config["weight"] = weight
+					}
+					finder, err := finders.Build(name, config)
+					if err != nil {
+						t.Fatal(err)
+					}
+					path := finder.FindPath(start, end, grid)
+					hashUint64(h, uint64(len(path)))
+					for _, id := range path {
+						hashUint64(h, uint64(id))
+					}
+				}
+			}
+		}
+
+		var agents []mapf.TAgent
+		for id := 1; id <= 3; id++ {
+			agent := mapf.TAgent{Id: id, StartX: rnd.Intn(width), StartY: rnd.Intn(height), GoalX: rnd.Intn(width), GoalY: rnd.Intn(height)}
+			grid.SetWalkableAt(agent.StartX, agent.StartY, true)
+			grid.SetWalkableAt(agent.GoalX, agent.GoalY, true)
+			agents = append(agents, agent)
+		}
+		for _, move := range []core.DiagonalMovement{core.Never, core.OnlyWhenNoObstacles} {
+			var plans [][]core.TimedPath
+			for _, w := range []float64{1, 1.5} {
+				solver := mapf.CreateCBSSolver(&core.Opt{DiagonalMovement: move}, w)
+				solver.MaxTime = 256
+				paths, _ := solver.Solve(agents, grid)
+				plans = append(plans, paths)
+			}
+			for _, window := range []int32{0, 8} {
+				plans = append(plans, mapf.CreateCooperativeFinder(&core.Opt{DiagonalMovement: move}, window).FindPaths(agents, grid))
+			}
+			for _, paths := range plans {
+				hashUint64(h, uint64(len(paths)))
+				for _, path := range paths {
+					hashUint64(h, uint64(len(path)))
+					for _, step := range path {
+						hashUint64(h, uint64(step.X)<<32|uint64(uint32(step.Y)))
+						hashUint64(h, math.Float64bits(step.T))
+					}
+				}
+			}
+		}
+	}
+	return h.Sum64()
+}
+
+func TestFixedCostsDeterminism(t *testing.T) {
+	// the same on every run and every platform: a change of this hash is
+	// a change of the paths lockstep games agree on.
+	const expected = 0xbb02901e6ecc2f88
+	first, second := fixedPathsHash(t), fixedPathsHash(t)
+	if first != second {
+		t.Fatalf("paths hashed as %016x, then %016x", first, second)
+	}
+	if first != expected {
+		t.Fatalf("paths hashed as %#016x, expected %#016x", first, uint64(expected))
+	}
+}
+
+func TestFixedCosts(t *testing.T) {
+	grid := core.Grid(5, 5, nil)
+	hash := grid.ContentHash()
+	grid.SetCostModel(core.FixedCosts)
+	if grid.ContentHash() == hash {
+		t.Fatal("the cost model does not change the content hash")
+	}
+	grid.SetCostAt(1, 1, 1.55)
+	opt := &core.Opt{DiagonalMovement: core.Always, Heuristic: core.Euclidean}
+	for _, c := range []struct {
+		value, expected float64
+	}{
+		{grid.Cost(grid.NodeID(0, 0), grid.NodeID(1, 0)), 10},
+		{grid.Cost(grid.NodeID(0, 0), grid.NodeID(1, 1)), 22}, // 14 * 1.55 rounded
+		{grid.Heuristic(grid.NodeID(0, 0), grid.NodeID(4, 1), opt), 44},
+		{grid.Heuristic(grid.NodeID(0, 0), grid.NodeID(4, 1), &core.Opt{DiagonalMovement: core.Never}), 50},
+	} {
+		if c.value != c.expected {
+			t.Fatalf("%v, expected %v", c.value, c.expected)
+		}
+	}
+	grid.SetCostModel(core.FloatCosts)
+	grid.SetCostAt(1, 1, 1)
+	if grid.ContentHash() != hash {
+		t.Fatal("the content hash does not come back with the float costs")
+	}
+}
@@ -0,0 +1,68 @@
+package DijkstraFinder
+
+/*
+	by stefan 2572915286@qq.com
+	Based upon https://github.com/qiao/PathFinding.js
+*/
+
+import (
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/finders/AStarFinder"
+)
+
+type TDijkstraFinder struct {
+	*AStarFinder.TAStarFinder
+}
+
+/**
+ * Dijkstra path-finder: A* without heuristic, on any graph.
+ * @constructor
+ * @param {Object} opt
+ * @param {boolean} opt.allowDiagonal Whether diagonal movement is allowed.
+ *     Deprecated, use diagonalMovement instead.
+ * @param {boolean} opt.dontCrossCorners Disallow diagonal movement touching
+ *     block corners. Deprecated, use diagonalMovement instead.
+ * @param {DiagonalMovement} opt.diagonalMovement Allowed diagonal movement.
+ * @param {TieBreaking} opt.tieBreaking Which of the paths of equal cost is
+ *     found (defaults to fifo).
+ */
+func CreateDijkstraFinder(opt *core.Opt) (this *TDijkstraFinder) {
+	return &TDijkstraFinder{
+		TAStarFinder: AStarFinder.CreateAStarFinder(opt),
+	}
+}
+
+func init() {
+	// no heuristic, no weight.
+	finders.RegisterWithSchema("dijkstra", func(opt *core.Opt) finders.FinderBase {
+		return CreateDijkstraFinder(opt)
+	}, finders.GridSchema.With(finders.TieBreakingOption))
+}
+
+// the graph with every heuristic estimate at 0.
+type uninformed struct {
+	core.Graph
+}
+
+func (this uninformed) Heuristic(from, to core.NodeID, opt *core.Opt) float64 {
+	return 0
+}
+
+// an uninformed grid, keeping the coordinates the tie breaking looks at.
+type uninformedPlanar struct {
+	uninformed
+	core.PlanarGraph
+}
+
+/**
+ * Find and return the the shortest path.
+ * @return {core.ArrayNodeID} The path, including both start and
+ *     end nodes. Empty if there is none.
+ */
+func (this *TDijkstraFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	if planar, ok := graph.(core.PlanarGraph); ok {
+		return this.TAStarFinder.FindPath(start, end, uninformedPlanar{uninformed{graph}, planar})
+	}
+	return this.TAStarFinder.FindPath(start, end, uninformed{graph})
+}
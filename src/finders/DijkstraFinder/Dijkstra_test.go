@@ -0,0 +1,44 @@
+package DijkstraFinder
+
+import (
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/finders/AStarFinder"
+	"go-PathFinding/finders/config"
+	"go-PathFinding/finders/conformance"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+func TestDijkstraFinder(t *testing.T) {
+	akLog.FmtPrintln("begin DijkstraFinder test...")
+	now := time.Now()
+	for _, move := range []core.DiagonalMovement{core.Never, core.OnlyWhenNoObstacles, core.Always} {
+		for _, item := range config.PathData {
+			grid := core.Grid(len(item.Matrix[0]), len(item.Matrix), item.Matrix)
+			start, end := grid.NodeID(item.StartX, item.StartY), grid.NodeID(item.EndX, item.EndY)
+			path := CreateDijkstraFinder(&core.Opt{DiagonalMovement: move}).FindPath(start, end, grid)
+			akLog.FmtPrintln("result: ", grid.PathCoords(path))
+
+			// both are optimal when A* keeps an admissible heuristic.
+			heuristic := core.Manhattan
+			if move != core.Never {
+				heuristic = core.Chebyshev
+			}
+			reference := AStarFinder.CreateAStarFinder(&core.Opt{DiagonalMovement: move, Heuristic: heuristic}).FindPath(start, end, grid)
+			if len(path) == 0 || math.Abs(core.PathCost(grid, path)-core.PathCost(grid, reference)) > 1e-9 {
+				t.Fatalf("%v: path %v, A* found %v", move, grid.PathCoords(path), grid.PathCoords(reference))
+			}
+		}
+	}
+	akLog.FmtPrintln("spend: ", float64(time.Since(now).Nanoseconds())/float64(1e9))
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(opt *core.Opt) finders.FinderBase {
+		return CreateDijkstraFinder(opt)
+	}, conformance.TClaims{Optimal: true})
+}
@@ -3,5 +3,5 @@ package finders
 import "go-PathFinding/core"
 
 type FinderBase interface {
-	FindPath(startX, startY, endX, endY int, grid *core.TGrid) core.DoubleInt32
+	FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID
 }
@@ -0,0 +1,450 @@
+package JumpPointFinder
+
+/*
+	by stefan 2572915286@qq.com
+	Based upon https://github.com/qiao/PathFinding.js
+*/
+
+import (
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/finders/AStarFinder"
+)
+
+type TJumpPointFinder struct {
+	*AStarFinder.TAStarFinder
+}
+
+/**
+ * Jump Point Search path-finder, on uniform-cost grids.
+ * Based upon D. Harabor and A. Grastien, "Online Graph Pruning for
+ * Pathfinding on Grid Maps", AAAI 2011.
+ * Other graphs, and grids with cell costs, are searched with plain A*.
+ * The tie breaking only chooses among jump points, between which paths
+ * never turn.
+ * @constructor
+ * @param {Object} opt - see AStarFinder.CreateAStarFinder.
+ */
+func CreateJumpPointFinder(opt *core.Opt) (this *TJumpPointFinder) {
+	return &TJumpPointFinder{
+		TAStarFinder: AStarFinder.CreateAStarFinder(opt),
+	}
+}
+
+func init() {
+	finders.RegisterWithSchema("jps", func(opt *core.Opt) finders.FinderBase {
+		return CreateJumpPointFinder(opt)
+	}, finders.HeuristicSchema.With(finders.TieBreakingOption))
+}
+
+// search state shared by the jumps of one FindPath.
+type jumpSearch struct {
+	grid *core.TGrid
+	opt  *core.Opt
+	endX int
+	endY int
+}
+
+func (this *jumpSearch) walkable(x, y int) bool {
+	if x < 0 || x >= this.grid.Width() || y < 0 || y >= this.grid.Height() {
+		return false
+	}
+	return this.grid.IsWalkable(this.grid.NodeID(x, y), this.opt)
+}
+
+/**
+ * Find and return the the path.
+ * @param {NodeID} start
+ * @param {NodeID} end
+ * @param {Graph} graph - A core.TGrid, other graphs are searched with A*.
+ * @return {core.ArrayNodeID} The path, including both start and
+ *     end nodes, every step between its jump points expanded.
+ *     Empty if there is none.
+ */
+func (this *TJumpPointFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	grid, ok := graph.(*core.TGrid)
+	if !ok || grid.HasCosts() {
+		return this.TAStarFinder.FindPath(start, end, graph)
+	}
+	opt := this.FinderOpt
+	if !grid.IsWalkable(start, opt) || !grid.IsWalkable(end, opt) {
+		return core.ArrayNodeID{}
+	}
+
+	endX, endY := grid.NodeXY(end)
+	var search = &jumpSearch{grid: grid, opt: opt, endX: endX, endY: endY}
+	var ties = core.NewTieBreaker(opt.TieBreaking, grid, start, end)
+	var openList = ties.NewHeap()
+	var startNode = &core.AStarGrid{Id: start}
+	var nodes = map[core.NodeID]*core.AStarGrid{start: startNode}
+	weight := opt.Weight
+	tracer := opt.Tracer
+
+	openList.Push(startNode)
+	startNode.Opened = true
+	if tracer != nil {
+		tracer.OnOpen(start, startNode.G, startNode.H)
+	}
+
+	for !openList.Empty() {
+		if opt.Stopped() {
+			return core.ArrayNodeID{}
+		}
+		node := openList.Pop()
+		node.Closed = true
+		if tracer != nil {
+			tracer.OnClose(node.Id, node.G, node.H)
+		}
+		if node.Id == end {
+			return expandPath(grid, core.Backtrace(node))
+		}
+
+		// identify the successors: the jump points in the directions
+		// left by the pruning rules.
+		x, y := grid.NodeXY(node.Id)
+		for _, neighbor := range search.neighbors(node) {
+			jx, jy, found := search.jump(neighbor[0], neighbor[1], x, y)
+			if !found {
+				continue
+			}
+			id := grid.NodeID(jx, jy)
+			jumpNode := nodes[id]
+			if jumpNode == nil {
+				jumpNode = &core.AStarGrid{Id: id}
+				nodes[id] = jumpNode
+			}
+			if jumpNode.Closed {
+				continue
+			}
+
+			// the segment to a jump point is straight or diagonal.
+			ng := node.G + segmentCost(grid, abs(jx-x), abs(jy-y))
+			if !jumpNode.Opened || ties.Better(jumpNode, node, ng) {
+				jumpNode.G = ng
+				if !jumpNode.Opened {
+					jumpNode.H = weight * grid.Heuristic(id, end, opt)
+				}
+				jumpNode.F = jumpNode.G + jumpNode.H
+				ties.Link(jumpNode, node)
+				if tracer != nil {
+					tracer.OnOpen(id, jumpNode.G, jumpNode.H)
+				}
+				if !jumpNode.Opened {
+					openList.Push(jumpNode)
+					jumpNode.Opened = true
+				} else {
+					openList.UpdateItem(jumpNode)
+				}
+			}
+		}
+	}
+	return core.ArrayNodeID{}
+}
+
+/**
+ * The neighbors of the node worth jumping to: all of them at the start,
+ * then the natural and forced neighbors in the direction of travel.
+ * Diagonal neighbors are only returned when the diagonal movement allows
+ * the step; straight ones may be blocked, jump checks them.
+ */
+func (this *jumpSearch) neighbors(node *core.AStarGrid) [][2]int {
+	grid := this.grid
+	if node.Parent == nil {
+		var list [][2]int
+		for _, id := range grid.Neighbors(node.Id, this.opt) {
+			x, y := grid.NodeXY(id)
+			list = append(list, [2]int{x, y})
+		}
+		return list
+	}
+
+	x, y := grid.NodeXY(node.Id)
+	px, py := grid.NodeXY(node.Parent.Id)
+	dx, dy := sign(x-px), sign(y-py)
+	walkable := this.walkable
+	var list [][2]int
+	add := func(x, y int) {
+		list = append(list, [2]int{x, y})
+	}
+
+	switch this.opt.DiagonalMovement {
+	case core.Never:
+		if dx != 0 {
+			if walkable(x, y-1) {
+				add(x, y-1)
+			}
+			if walkable(x, y+1) {
+				add(x, y+1)
+			}
+			if walkable(x+dx, y) {
+				add(x+dx, y)
+			}
+		} else {
+			if walkable(x-1, y) {
+				add(x-1, y)
+			}
+			if walkable(x+1, y) {
+				add(x+1, y)
+			}
+			if walkable(x, y+dy) {
+				add(x, y+dy)
+			}
+		}
+
+	case core.Always:
+		if dx != 0 && dy != 0 {
+			if walkable(x, y+dy) {
+				add(x, y+dy)
+			}
+			if walkable(x+dx, y) {
+				add(x+dx, y)
+			}
+			if walkable(x+dx, y+dy) {
+				add(x+dx, y+dy)
+			}
+			if !walkable(x-dx, y) {
+				add(x-dx, y+dy)
+			}
+			if !walkable(x, y-dy) {
+				add(x+dx, y-dy)
+			}
+		} else if dx == 0 {
+			if walkable(x, y+dy) {
+				add(x, y+dy)
+			}
+			if !walkable(x+1, y) {
+				add(x+1, y+dy)
+			}
+			if !walkable(x-1, y) {
+				add(x-1, y+dy)
+			}
+		} else {
+			if walkable(x+dx, y) {
+				add(x+dx, y)
+			}
+			if !walkable(x, y+1) {
+				add(x+dx, y+1)
+			}
+			if !walkable(x, y-1) {
+				add(x+dx, y-1)
+			}
+		}
+
+	case core.IfAtMostOneObstacle:
+		if dx != 0 && dy != 0 {
+			if walkable(x, y+dy) {
+				add(x, y+dy)
+			}
+			if walkable(x+dx, y) {
+				add(x+dx, y)
+			}
+			if walkable(x, y+dy) || walkable(x+dx, y) {
+				add(x+dx, y+dy)
+			}
+			if !walkable(x-dx, y) && walkable(x, y+dy) {
+				add(x-dx, y+dy)
+			}
+			if !walkable(x, y-dy) && walkable(x+dx, y) {
+				add(x+dx, y-dy)
+			}
+		} else if dx == 0 {
+			if walkable(x, y+dy) {
+				add(x, y+dy)
+				if !walkable(x+1, y) {
+					add(x+1, y+dy)
+				}
+				if !walkable(x-1, y) {
+					add(x-1, y+dy)
+				}
+			}
+		} else {
+			if walkable(x+dx, y) {
+				add(x+dx, y)
+				if !walkable(x, y+1) {
+					add(x+dx, y+1)
+				}
+				if !walkable(x, y-1) {
+					add(x+dx, y-1)
+				}
+			}
+		}
+
+	case core.OnlyWhenNoObstacles:
+		if dx != 0 && dy != 0 {
+			if walkable(x, y+dy) {
+				add(x, y+dy)
+			}
+			if walkable(x+dx, y) {
+				add(x+dx, y)
+			}
+			if walkable(x, y+dy) && walkable(x+dx, y) {
+				add(x+dx, y+dy)
+			}
+		} else if dx != 0 {
+			next, down, up := walkable(x+dx, y), walkable(x, y+1), walkable(x, y-1)
+			if next {
+				add(x+dx, y)
+				if down {
+					add(x+dx, y+1)
+				}
+				if up {
+					add(x+dx, y-1)
+				}
+			}
+			if down {
+				add(x, y+1)
+			}
+			if up {
+				add(x, y-1)
+			}
+		} else {
+			next, right, left := walkable(x, y+dy), walkable(x+1, y), walkable(x-1, y)
+			if next {
+				add(x, y+dy)
+				if right {
+					add(x+1, y+dy)
+				}
+				if left {
+					add(x-1, y+dy)
+				}
+			}
+			if right {
+				add(x+1, y)
+			}
+			if left {
+				add(x-1, y)
+			}
+		}
+
+	default:
+		panic("Incorrect value of diagonalMovement")
+	}
+	return list
+}
+
+/**
+ * Search recursively in the direction from (px, py) to (x, y), stopping
+ * at the first jump point: the end, or a node with forced neighbors.
+ * @return {number, number, boolean} The jump point, if any.
+ */
+func (this *jumpSearch) jump(x, y, px, py int) (int, int, bool) {
+	dx, dy := x-px, y-py
+	walkable := this.walkable
+	if !walkable(x, y) {
+		return 0, 0, false
+	}
+	if x == this.endX && y == this.endY {
+		return x, y, true
+	}
+
+	switch this.opt.DiagonalMovement {
+	case core.Never:
+		if dx != 0 {
+			if (walkable(x, y-1) && !walkable(x-dx, y-1)) || (walkable(x, y+1) && !walkable(x-dx, y+1)) {
+				return x, y, true
+			}
+		} else {
+			if (walkable(x-1, y) && !walkable(x-1, y-dy)) || (walkable(x+1, y) && !walkable(x+1, y-dy)) {
+				return x, y, true
+			}
+			// moving vertically, look for horizontal jump points.
+			if this.jumps(x+1, y, x, y) || this.jumps(x-1, y, x, y) {
+				return x, y, true
+			}
+		}
+		return this.jump(x+dx, y+dy, x, y)
+
+	case core.Always, core.IfAtMostOneObstacle:
+		if dx != 0 && dy != 0 {
+			if (walkable(x-dx, y+dy) && !walkable(x-dx, y)) || (walkable(x+dx, y-dy) && !walkable(x, y-dy)) {
+				return x, y, true
+			}
+			// moving diagonally, look for straight jump points.
+			if this.jumps(x+dx, y, x, y) || this.jumps(x, y+dy, x, y) {
+				return x, y, true
+			}
+		} else if dx != 0 {
+			if (walkable(x+dx, y+1) && !walkable(x, y+1)) || (walkable(x+dx, y-1) && !walkable(x, y-1)) {
+				return x, y, true
+			}
+		} else {
+			if (walkable(x+1, y+dy) && !walkable(x+1, y)) || (walkable(x-1, y+dy) && !walkable(x-1, y)) {
+				return x, y, true
+			}
+		}
+		// a diagonal step needs one of its sides open.
+		if this.opt.DiagonalMovement == core.IfAtMostOneObstacle && !walkable(x+dx, y) && !walkable(x, y+dy) {
+			return 0, 0, false
+		}
+		return this.jump(x+dx, y+dy, x, y)
+
+	case core.OnlyWhenNoObstacles:
+		if dx != 0 && dy != 0 {
+			if this.jumps(x+dx, y, x, y) || this.jumps(x, y+dy, x, y) {
+				return x, y, true
+			}
+		} else if dx != 0 {
+			if (walkable(x, y-1) && !walkable(x-dx, y-1)) || (walkable(x, y+1) && !walkable(x-dx, y+1)) {
+				return x, y, true
+			}
+		} else {
+			if (walkable(x-1, y) && !walkable(x-1, y-dy)) || (walkable(x+1, y) && !walkable(x+1, y-dy)) {
+				return x, y, true
+			}
+		}
+		// a diagonal step needs both of its sides open.
+		if !walkable(x+dx, y) || !walkable(x, y+dy) {
+			return 0, 0, false
+		}
+		return this.jump(x+dx, y+dy, x, y)
+	}
+	panic("Incorrect value of diagonalMovement")
+}
+
+func (this *jumpSearch) jumps(x, y, px, py int) bool {
+	_, _, found := this.jump(x, y, px, py)
+	return found
+}
+
+// expandPath fills in the straight and diagonal segments between the
+// jump points.
+func expandPath(grid *core.TGrid, jumpPoints core.ArrayNodeID) core.ArrayNodeID {
+	var path = core.ArrayNodeID{jumpPoints[0]}
+	for i := 1; i < len(jumpPoints); i++ {
+		x, y := grid.NodeXY(jumpPoints[i-1])
+		ex, ey := grid.NodeXY(jumpPoints[i])
+		dx, dy := sign(ex-x), sign(ey-y)
+		for x != ex || y != ey {
+			x, y = x+dx, y+dy
+			path = append(path, grid.NodeID(x, y))
+		}
+	}
+	return path
+}
+
+// segmentCost is the cost of the steps from a node to a jump point dx
+// and dy cells away, as summed by A*.
+func segmentCost(grid *core.TGrid, dx, dy int) float64 {
+	straight, diagonal := grid.StepCosts()
+	if dx == 0 || dy == 0 {
+		return float64(dx+dy) * straight
+	}
+	return float64(dx) * diagonal
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
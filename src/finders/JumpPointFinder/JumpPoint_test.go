@@ -0,0 +1,105 @@
+package JumpPointFinder
+
+import (
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/finders/DijkstraFinder"
+	"go-PathFinding/finders/config"
+	"go-PathFinding/finders/conformance"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+func TestJumpPointFinder(t *testing.T) {
+	akLog.FmtPrintln("begin JumpPointFinder test...")
+	now := time.Now()
+	for _, item := range config.PathData {
+		grid := core.Grid(len(item.Matrix[0]), len(item.Matrix), item.Matrix)
+		finder := CreateJumpPointFinder(&core.Opt{DiagonalMovement: core.Always})
+		result := grid.PathCoords(finder.FindPath(grid.NodeID(item.StartX, item.StartY), grid.NodeID(item.EndX, item.EndY), grid))
+		akLog.FmtPrintln("result: ", result)
+	}
+	akLog.FmtPrintln("spend: ", float64(time.Since(now).Nanoseconds())/float64(1e9))
+}
+
+func TestJumpPointFinderRandom(t *testing.T) {
+	// as short as Dijkstra on random mazes, for every diagonal movement
+	// and agent size.
+	rnd := rand.New(rand.NewSource(7))
+	for round := 0; round < 40; round++ {
+		width, height := 10+rnd.Intn(20), 10+rnd.Intn(20)
+		matrix := make(core.DoubleInt32, height)
+		for y := range matrix {
+			matrix[y] = make(core.ArrayInt32, width)
+			for x := range matrix[y] {
+				if rnd.Float64() < 0.3 {
+					matrix[y][x] = 1
+				}
+			}
+		}
+		grid := core.Grid(width, height, matrix)
+		for _, move := range conformance.AllMoves {
+			for size := int32(1); size <= 2; size++ {
+				start := grid.NodeID(rnd.Intn(width), rnd.Intn(height))
+				end := grid.NodeID(rnd.Intn(width), rnd.Intn(height))
+				path := CreateJumpPointFinder(&core.Opt{DiagonalMovement: move, AgentSize: size}).FindPath(start, end, grid)
+				reference := DijkstraFinder.CreateDijkstraFinder(&core.Opt{DiagonalMovement: move, AgentSize: size}).FindPath(start, end, grid)
+				if len(path) != len(reference) && (len(path) == 0 || len(reference) == 0) {
+					t.Fatalf("round %d, %v, size %d: path %v, Dijkstra found %v", round, move, size, grid.PathCoords(path), grid.PathCoords(reference))
+				}
+				if math.Abs(core.PathCost(grid, path)-core.PathCost(grid, reference)) > 1e-9 {
+					t.Fatalf("round %d, %v, size %d: path %v, Dijkstra found %v", round, move, size, grid.PathCoords(path), grid.PathCoords(reference))
+				}
+				for i := 1; i < len(path); i++ {
+					if !hasNeighbor(grid, path[i-1], path[i], move, size) {
+						t.Fatalf("round %d, %v, size %d: step %d of %v not allowed", round, move, size, i, grid.PathCoords(path))
+					}
+				}
+			}
+		}
+	}
+}
+
+func hasNeighbor(grid *core.TGrid, from, to core.NodeID, move core.DiagonalMovement, size int32) bool {
+	for _, id := range grid.Neighbors(from, &core.Opt{DiagonalMovement: move, AgentSize: size}) {
+		if id == to {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(opt *core.Opt) finders.FinderBase {
+		return CreateJumpPointFinder(opt)
+	}, conformance.TClaims{Optimal: true})
+}
+
+func TestJumpPointFinderTieBreaking(t *testing.T) {
+	// the policies choose among jump points: paths stay shortest, and turn
+	// once on an open grid.
+	grid := core.Grid(30, 30, nil)
+	start, end := grid.NodeID(2, 3), grid.NodeID(25, 14)
+	for _, move := range []core.DiagonalMovement{core.Never, core.Always, core.OnlyWhenNoObstacles} {
+		shortest := core.PathCost(grid, CreateJumpPointFinder(&core.Opt{DiagonalMovement: move}).FindPath(start, end, grid))
+		for _, policy := range []core.TieBreaking{core.TieLIFO, core.TiePreferHigherG, core.TieCrossProduct, core.TieFewerTurns} {
+			path := CreateJumpPointFinder(&core.Opt{DiagonalMovement: move, TieBreaking: policy}).FindPath(start, end, grid)
+			if cost := core.PathCost(grid, path); math.Abs(cost-shortest) > 1e-9 {
+				t.Fatalf("%v %v: cost %v, expected %v", move, policy, cost, shortest)
+			}
+			coords, turns := grid.PathCoords(path), 0
+			for i := 2; i < len(coords); i++ {
+				if coords[i][0]-coords[i-1][0] != coords[i-1][0]-coords[i-2][0] || coords[i][1]-coords[i-1][1] != coords[i-1][1]-coords[i-2][1] {
+					turns++
+				}
+			}
+			if turns > 1 {
+				t.Fatalf("%v %v: path %v turns %d times", move, policy, coords, turns)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package finders
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-PathFinding/core"
+)
+
+/**
+ * Builds a finder from its options, as the Create<Name>Finder functions do.
+ */
+type Creator func(opt *core.Opt) FinderBase
+
+type registration struct {
+	creator Creator
+	schema  TSchema
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]registration{}
+)
+
+/**
+ * Make a finder available by name, with the options of HeuristicSchema.
+ * Finder packages register themselves from init, so importing one (even
+ * as _) is enough to use it by name.
+ * Registering the same name twice panics.
+ */
+func Register(name string, creator Creator) {
+	RegisterWithSchema(name, creator, HeuristicSchema)
+}
+
+/**
+ * Make a finder available by name, accepting the options of schema
+ * in the config maps given to Build.
+ */
+func RegisterWithSchema(name string, creator Creator, schema TSchema) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if creator == nil {
+		panic("finders: Register creator is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("finders: Register called twice for finder " + name)
+	}
+	registry[name] = registration{creator, schema}
+}
+
+func lookup(name string) (registration, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	entry, ok := registry[name]
+	if !ok {
+		return entry, fmt.Errorf("finders: unknown finder %q", name)
+	}
+	return entry, nil
+}
+
+/**
+ * Build the finder registered under name.
+ * @param {Object} opt - Passed to the finder constructor.
+ */
+func Create(name string, opt *core.Opt) (FinderBase, error) {
+	entry, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return entry.creator(opt), nil
+}
+
+/**
+ * Build the finder registered under name from a config map, such as
+ * {"diagonalMovement": "onlyWhenNoObstacles", "heuristic": "octile"}
+ * decoded from a config file. Options missing from the map keep the
+ * defaults of the finder; unknown options and values of the wrong type
+ * are errors.
+ */
+func Build(name string, config map[string]interface{}) (FinderBase, error) {
+	return BuildWith(name, &core.Opt{}, config)
+}
+
+/**
+ * Build the finder like Build, the config being applied to a copy of
+ * base, which carries what a config map cannot: the Tracer and Done of
+ * the searches.
+ */
+func BuildWith(name string, base *core.Opt, config map[string]interface{}) (FinderBase, error) {
+	entry, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := entry.schema.parse(config)
+	if err != nil {
+		return nil, fmt.Errorf("finders: finder %q: %v", name, err)
+	}
+	var opt = &core.Opt{}
+	*opt = *base
+	for _, s := range settings {
+		if s.option.SetOpt != nil {
+			s.option.SetOpt(opt, s.value)
+		}
+	}
+	finder := entry.creator(opt)
+	for _, s := range settings {
+		if s.option.SetFinder != nil {
+			s.option.SetFinder(finder, s.value)
+		}
+	}
+	return finder, nil
+}
+
+/**
+ * The config map in a canonical form, such as
+ * `diagonalMovement="never",weight=1.5`: its options converted and in the
+ * order of the schema of the finder registered under name. Configs of the
+ * same form build the same finder.
+ */
+func Canonical(name string, config map[string]interface{}) (string, error) {
+	entry, err := lookup(name)
+	if err != nil {
+		return "", err
+	}
+	settings, err := entry.schema.parse(config)
+	if err != nil {
+		return "", fmt.Errorf("finders: finder %q: %v", name, err)
+	}
+	var parts []string
+	for _, s := range settings {
+		parts = append(parts, fmt.Sprintf("%s=%#v", s.option.Name, s.value))
+	}
+	return strings.Join(parts, ","), nil
+}
+
+/**
+ * The options accepted by the finder registered under name.
+ */
+func Schema(name string) (TSchema, error) {
+	entry, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return entry.schema, nil
+}
+
+/**
+ * Sorted names of the registered finders.
+ */
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,129 @@
+package finders_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/BiAStarFinder"
+	_ "go-PathFinding/finders/DijkstraFinder"
+	_ "go-PathFinding/finders/JumpPointFinder"
+	"go-PathFinding/finders/SIPPFinder"
+)
+
+func TestBuild(t *testing.T) {
+	// the config of a map, as read from a file.
+	var config map[string]map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(`{
+		"astar":    {"diagonalMovement": "onlyWhenNoObstacles", "heuristic": "octile", "weight": 1.5},
+		"biastar":  {"allowDiagonal": true, "dontCrossCorners": true},
+		"dijkstra": {"diagonalMovement": "never", "agentSize": 2},
+		"jps":      {"diagonalMovement": "always", "heuristic": "chebyshev"},
+		"sipp":     {"horizon": 50}
+	}`))
+	decoder.UseNumber()
+	if err := decoder.Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	grid := core.Grid(4, 4, nil)
+	for name, options := range config {
+		finder, err := finders.Build(name, options)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if path := finder.FindPath(grid.NodeID(0, 0), grid.NodeID(2, 2), grid); len(path) == 0 {
+			t.Fatalf("%s found no path", name)
+		}
+	}
+
+	finder, _ := finders.Build("astar", config["astar"])
+	opt := finder.(*AStarFinder.TAStarFinder).FinderOpt
+	if opt.DiagonalMovement != core.OnlyWhenNoObstacles || opt.Weight != 1.5 || opt.Heuristic(3, 1) != core.Octile(3, 1) {
+		t.Fatalf("unexpected options %+v", opt)
+	}
+	finder, _ = finders.Build("sipp", config["sipp"])
+	if sipp := finder.(*SIPPFinder.TSIPPFinder); sipp.Horizon != 50 || sipp.FinderOpt.DiagonalMovement != core.Never {
+		t.Fatalf("unexpected horizon %v, %v", sipp.Horizon, sipp.FinderOpt.DiagonalMovement)
+	}
+	// a nil config keeps the defaults.
+	if _, err := finders.Build("jps", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the base options reach the finder, unchanged by the config.
+	stats := &core.TSearchStats{}
+	base := &core.Opt{Tracer: stats}
+	finder, err := finders.BuildWith("astar", base, config["astar"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	finder.FindPath(grid.NodeID(0, 0), grid.NodeID(2, 2), grid)
+	if stats.Closed == 0 || base.Weight != 0 || base.Heuristic != nil {
+		t.Fatalf("stats %+v, base %+v", stats, base)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	// the form ignores the order and the types the values are given in.
+	a, err := finders.Canonical("astar", map[string]interface{}{"weight": json.Number("1.5"), "agentSize": 2.0, "diagonalMovement": "never"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := finders.Canonical("astar", map[string]interface{}{"diagonalMovement": "never", "agentSize": 2, "weight": float32(1.5)})
+	if a != b || a != `diagonalMovement="never",agentSize=2,weight=1.5` {
+		t.Fatalf("forms %s and %s", a, b)
+	}
+	if _, err := finders.Canonical("astar", map[string]interface{}{"weight": 0.5}); err == nil {
+		t.Fatalf("invalid config accepted")
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		options map[string]interface{}
+		reason  string
+	}{
+		{"bfs", nil, `unknown finder "bfs"`},
+		{"dijkstra", map[string]interface{}{"heuristic": "octile"}, "unknown option heuristic"},
+		{"astar", map[string]interface{}{"agentSize": 1.5}, "not a whole number"},
+		{"astar", map[string]interface{}{"agentSize": 0}, "outside [1, "},
+		{"astar", map[string]interface{}{"weight": 0.5}, "below 1"},
+		{"astar", map[string]interface{}{"weight": "2"}, "(string), want number"},
+		{"astar", map[string]interface{}{"allowDiagonal": 1}, "(int), want bool"},
+		{"astar", map[string]interface{}{"heuristic": "taxicab"}, `"taxicab" is not one of chebyshev, euclidean`},
+		{"jps", map[string]interface{}{"diagonalMovement": "sometimes"}, `"sometimes" is not one of never`},
+		{"sipp", map[string]interface{}{"horizon": 0.5}, "below 1"},
+		{"sipp", map[string]interface{}{"tieBreaking": "lifo"}, "unknown option tieBreaking"},
+		{"astar", map[string]interface{}{"tieBreaking": "random"}, `"random" is not one of fifo, lifo`},
+	} {
+		_, err := finders.Build(c.name, c.options)
+		if err == nil || !strings.Contains(err.Error(), c.reason) {
+			t.Fatalf("%s %v: %v, expected %q", c.name, c.options, err, c.reason)
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema, err := finders.Schema("dijkstra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := strings.Join(schema.Names(), ","); names != "diagonalMovement,allowDiagonal,dontCrossCorners,agentSize,tieBreaking" {
+		t.Fatalf("unexpected dijkstra options %s", names)
+	}
+	schema, _ = finders.Schema("sipp")
+	if option := schema.Lookup("horizon"); option == nil || option.Kind != finders.OptionNumber {
+		t.Fatalf("unexpected horizon option %+v", option)
+	}
+	if schema.Lookup("horizon") == nil || finders.HeuristicSchema.Lookup("horizon") != nil {
+		t.Fatal("With changed the schema it extends")
+	}
+	if _, err := finders.Schema("bfs"); err == nil {
+		t.Fatal("schema of an unknown finder")
+	}
+}
@@ -0,0 +1,251 @@
+package SIPPFinder
+
+/*
+	by stefan 2572915286@qq.com
+	Safe Interval Path Planning,
+	based upon M. Phillips and M. Likhachev, "SIPP: Safe Interval Path
+	Planning for Dynamic Environments", ICRA 2011.
+*/
+
+import (
+	"container/heap"
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/finders/AStarFinder"
+	"math"
+)
+
+type TSIPPFinder struct {
+	*AStarFinder.TAStarFinder
+	Horizon float64 // how far periodic schedules are unrolled, blocked past it
+}
+
+type sippNode struct {
+	id       core.NodeID
+	interval int
+	safe     core.TInterval
+	G        float64 // arrival time
+	F        float64
+	Depart   float64 // when the parent was left to come here
+	Parent   *sippNode
+	Closed   bool
+	index    int
+}
+
+type sippKey struct {
+	id       core.NodeID
+	interval int
+}
+
+type openList []*sippNode
+
+func (this openList) Len() int { return len(this) }
+
+// ties go to the lower node, then the earlier interval, so that the order
+// does not depend on the heap history.
+func (this openList) Less(i, j int) bool {
+	a, b := this[i], this[j]
+	if a.F != b.F {
+		return a.F < b.F
+	}
+	if a.id != b.id {
+		return a.id < b.id
+	}
+	return a.interval < b.interval
+}
+func (this openList) Swap(i, j int) {
+	this[i], this[j] = this[j], this[i]
+	this[i].index = i
+	this[j].index = j
+}
+func (this *openList) Push(x interface{}) {
+	node := x.(*sippNode)
+	node.index = len(*this)
+	*this = append(*this, node)
+}
+func (this *openList) Pop() interface{} {
+	old := *this
+	node := old[len(old)-1]
+	*this = old[:len(old)-1]
+	node.index = -1
+	return node
+}
+
+/**
+ * SIPP path-finder, for grids carrying availability schedules.
+ * Moves take their cost in time, in straight steps (1 straight, SQRT2
+ * diagonally, whatever the cost model of the grid) and the agent may
+ * wait on a cell as long as the cell stays safe.
+ * @constructor
+ * @param {Object} opt - see AStarFinder.CreateAStarFinder.
+ */
+func CreateSIPPFinder(opt *core.Opt) (this *TSIPPFinder) {
+	return &TSIPPFinder{
+		TAStarFinder: AStarFinder.CreateAStarFinder(opt),
+		Horizon:      1000,
+	}
+}
+
+func init() {
+	finders.RegisterWithSchema("sipp", func(opt *core.Opt) finders.FinderBase {
+		return CreateSIPPFinder(opt)
+	}, finders.HeuristicSchema.With(finders.TOption{
+		Name: "horizon",
+		Kind: finders.OptionNumber,
+		Doc:  "how far periodic schedules are unrolled, cells being blocked past it",
+		Min:  1,
+		SetFinder: func(finder finders.FinderBase, value interface{}) {
+			finder.(*TSIPPFinder).Horizon = value.(float64)
+		},
+	}))
+}
+
+/**
+ * Find and return the earliest-arrival path.
+ * @param {number} startT - Time the agent leaves the start cell.
+ * @return {core.TimedPath} The timed waypoints, including both start and
+ *     end positions; a wait shows as two consecutive waypoints on the
+ *     same cell. Empty if there is no path.
+ */
+func (this *TSIPPFinder) FindTimedPath(startX, startY, endX, endY int, startT float64, grid *core.TGrid) core.TimedPath {
+	opt := this.FinderOpt
+	start := grid.NodeID(startX, startY)
+	end := grid.NodeID(endX, endY)
+	if !grid.IsWalkable(start, opt) || !grid.IsWalkable(end, opt) {
+		return core.TimedPath{}
+	}
+
+	// the search runs on the costs of the grid, the schedules and the
+	// timed path on straight steps: under FixedCosts a step costs 10.
+	step, _ := grid.StepCosts()
+	intervals := map[core.NodeID][]core.TInterval{}
+	safeIntervals := func(id core.NodeID) []core.TInterval {
+		if list, ok := intervals[id]; ok {
+			return list
+		}
+		x, y := grid.NodeXY(id)
+		list := grid.SafeIntervals(x, y, startT, startT+this.Horizon)
+		for i := range list {
+			list[i].Start *= step
+			list[i].End *= step
+		}
+		intervals[id] = list
+		return list
+	}
+	departure := startT * step
+
+	weight := opt.Weight
+	var startNode *sippNode
+	for i, safe := range safeIntervals(start) {
+		if departure >= safe.Start && departure < safe.End {
+			startNode = &sippNode{id: start, interval: i, safe: safe, G: departure, Depart: departure}
+		}
+	}
+	if startNode == nil {
+		return core.TimedPath{}
+	}
+	startNode.F = departure + weight*grid.Heuristic(start, end, opt)
+
+	visited := map[sippKey]*sippNode{{id: start, interval: startNode.interval}: startNode}
+	open := &openList{}
+	heap.Push(open, startNode)
+	tracer := opt.Tracer
+	if tracer != nil {
+		tracer.OnOpen(start, startNode.G, startNode.F-startNode.G)
+	}
+
+	for open.Len() > 0 {
+		if opt.Stopped() {
+			break
+		}
+		node := heap.Pop(open).(*sippNode)
+		node.Closed = true
+		if tracer != nil {
+			tracer.OnClose(node.id, node.G, node.F-node.G)
+		}
+
+		if node.id == end {
+			return this.backtrace(node, grid)
+		}
+
+		for _, next := range grid.Neighbors(node.id, opt) {
+			duration := grid.Cost(node.id, next)
+			for i, safe := range safeIntervals(next) {
+				// earliest arrival in this interval, leaving before the
+				// current one closes.
+				arrival := math.Max(node.G+duration, safe.Start)
+				if arrival >= safe.End || arrival-duration >= node.safe.End {
+					continue
+				}
+				key := sippKey{id: next, interval: i}
+				neighbor := visited[key]
+				if neighbor != nil && (neighbor.Closed || neighbor.G <= arrival) {
+					continue
+				}
+				if neighbor == nil {
+					neighbor = &sippNode{id: next, interval: i, safe: safe}
+					visited[key] = neighbor
+					neighbor.G = arrival
+					neighbor.F = arrival + weight*grid.Heuristic(next, end, opt)
+					neighbor.Depart = arrival - duration
+					neighbor.Parent = node
+					heap.Push(open, neighbor)
+				} else {
+					neighbor.F += arrival - neighbor.G
+					neighbor.G = arrival
+					neighbor.Depart = arrival - duration
+					neighbor.Parent = node
+					heap.Fix(open, neighbor.index)
+				}
+				if tracer != nil {
+					tracer.OnOpen(next, neighbor.G, neighbor.F-neighbor.G)
+				}
+			}
+		}
+	}
+
+	// fail to find the path
+	return core.TimedPath{}
+}
+
+func (this *TSIPPFinder) backtrace(node *sippNode, grid *core.TGrid) core.TimedPath {
+	step, _ := grid.StepCosts()
+	var path = core.TimedPath{}
+	for ; node != nil; node = node.Parent {
+		x, y := grid.NodeXY(node.id)
+		path = append(path, core.TimedCoordinate{X: int32(x), Y: int32(y), T: node.G / step})
+		if node.Parent != nil && node.Depart > node.Parent.G {
+			// waited on the parent cell before leaving it.
+			px, py := grid.NodeXY(node.Parent.id)
+			path = append(path, core.TimedCoordinate{X: int32(px), Y: int32(py), T: node.Depart / step})
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+/**
+ * Find and return the the path, leaving at time 0.
+ * On a core.TGrid the schedules are honoured and the waits dropped from
+ * the result; any other graph is searched with plain A*.
+ * @return {core.ArrayNodeID} The path, including both start and
+ *     end nodes. Empty if there is none.
+ */
+func (this *TSIPPFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	grid, ok := graph.(*core.TGrid)
+	if !ok {
+		return this.TAStarFinder.FindPath(start, end, graph)
+	}
+	startX, startY := grid.NodeXY(start)
+	endX, endY := grid.NodeXY(end)
+	var path = core.ArrayNodeID{}
+	for _, step := range this.FindTimedPath(startX, startY, endX, endY, 0, grid) {
+		id := grid.NodeID(int(step.X), int(step.Y))
+		if len(path) == 0 || path[len(path)-1] != id {
+			path = append(path, id)
+		}
+	}
+	return path
+}
@@ -0,0 +1,114 @@
+package SIPPFinder
+
+import (
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/finders/conformance"
+	"reflect"
+	"testing"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+func TestSIPPFinderDoor(t *testing.T) {
+	// a door on (2, 0) opens at t = 5.
+	grid := core.Grid(5, 1, nil)
+	grid.SetSchedule(2, 0, &core.TSchedule{Blocked: []core.TInterval{{Start: 0, End: 5}}})
+
+	finder := CreateSIPPFinder(&core.Opt{DiagonalMovement: core.Never})
+	result := finder.FindTimedPath(0, 0, 4, 0, 0, grid)
+	akLog.FmtPrintln("result: ", result)
+	if len(result) == 0 {
+		t.Fatal("no path through the door")
+	}
+	last := result[len(result)-1]
+	if last.X != 4 || last.T != 7 {
+		t.Fatalf("expected to reach (4, 0) at 7, got %v", result)
+	}
+	waited := false
+	for i := 1; i < len(result); i++ {
+		if result[i].X == result[i-1].X && result[i].Y == result[i-1].Y {
+			waited = true
+		}
+		if !grid.IsWalkableAtTime(int(result[i].X), int(result[i].Y), result[i].T) {
+			t.Fatalf("waypoint %v is blocked", result[i])
+		}
+	}
+	if !waited {
+		t.Fatalf("no wait action in %v", result)
+	}
+
+	// times stay in steps under the 10/14 costs.
+	grid.SetCostModel(core.FixedCosts)
+	if fixed := finder.FindTimedPath(0, 0, 4, 0, 0, grid); !reflect.DeepEqual(fixed, result) {
+		t.Fatalf("fixed costs: %v, expected %v", fixed, result)
+	}
+}
+
+func TestSIPPFinderPatrol(t *testing.T) {
+	// a hazard patrols the middle row every 4 time units: the agent has
+	// to time its crossing of (1, 1).
+	grid := core.Grid(3, 3, core.DoubleInt32{
+		{0, 1, 0},
+		{0, 0, 0},
+		{0, 1, 0},
+	})
+	for x := 0; x < 3; x++ {
+		grid.SetSchedule(x, 1, &core.TSchedule{
+			Blocked: []core.TInterval{{Start: float64(x), End: float64(x) + 1}},
+			Period:  4,
+		})
+	}
+	finder := CreateSIPPFinder(&core.Opt{DiagonalMovement: core.Never})
+	result := finder.FindTimedPath(0, 0, 2, 0, 0, grid)
+	akLog.FmtPrintln("result: ", result)
+	if len(result) == 0 {
+		t.Fatal("no path found")
+	}
+	for _, step := range result {
+		if !grid.IsWalkableAtTime(int(step.X), int(step.Y), step.T) {
+			t.Fatalf("waypoint %v is blocked", step)
+		}
+	}
+
+	// FindPath follows the same route, waits dropped.
+	path := finder.FindPath(grid.NodeID(0, 0), grid.NodeID(2, 0), grid)
+	if len(path) != 5 {
+		t.Fatalf("unexpected path: %v", grid.PathCoords(path))
+	}
+}
+
+func TestSIPPFinderHorizon(t *testing.T) {
+	grid := core.Grid(5, 1, nil)
+	grid.SetSchedule(2, 0, &core.TSchedule{Blocked: []core.TInterval{{Start: 0, End: 3}}, Period: 4})
+
+	// unrolled from the period of the start only, blocked past the horizon.
+	expected := []core.TInterval{{Start: 103, End: 104}}
+	if safe := grid.SafeIntervals(2, 0, 102.5, 106); !reflect.DeepEqual(safe, expected) {
+		t.Fatalf("safe intervals %v, expected %v", safe, expected)
+	}
+
+	// the patrolled cell is only crossed within the horizon.
+	finder := CreateSIPPFinder(&core.Opt{DiagonalMovement: core.Never})
+	finder.Horizon = 1
+	if result := finder.FindTimedPath(0, 0, 4, 0, 0, grid); len(result) != 0 {
+		t.Fatalf("crossed past the horizon: %v", result)
+	}
+	finder.Horizon = 1000
+	result := finder.FindTimedPath(0, 0, 4, 0, 1e12, grid)
+	if len(result) == 0 {
+		t.Fatal("no path leaving late")
+	}
+	for _, step := range result {
+		if !grid.IsWalkableAtTime(int(step.X), int(step.Y), step.T) {
+			t.Fatalf("waypoint %v is blocked", step)
+		}
+	}
+}
+
+func TestConformance(t *testing.T) {
+	// without a schedule SIPP is a plain A*.
+	conformance.Run(t, func(opt *core.Opt) finders.FinderBase {
+		return CreateSIPPFinder(opt)
+	}, conformance.TClaims{Optimal: true})
+}
@@ -0,0 +1,293 @@
+package finders
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"go-PathFinding/core"
+)
+
+/**
+ * Type of the value of a finder option.
+ */
+type OptionKind int
+
+const (
+	OptionBool   OptionKind = 1
+	OptionInt    OptionKind = 2
+	OptionNumber OptionKind = 3
+	OptionString OptionKind = 4
+)
+
+var optionKindNames = map[OptionKind]string{
+	OptionBool:   "bool",
+	OptionInt:    "int",
+	OptionNumber: "number",
+	OptionString: "string",
+}
+
+func (this OptionKind) String() string {
+	if name, ok := optionKindNames[this]; ok {
+		return name
+	}
+	return fmt.Sprintf("OptionKind(%d)", int(this))
+}
+
+/**
+ * An option a finder accepts in a config map, as given to Build.
+ * Values are converted after the Kind before being set: bool, int64,
+ * float64 or string. Options of the core.Opt are set with SetOpt, before
+ * the finder is created; the others with SetFinder, on the finder.
+ */
+type TOption struct {
+	Name      string
+	Kind      OptionKind
+	Doc       string
+	Values    []string // accepted values of a string option, any when empty
+	Min       float64  // lowest accepted int or number
+	Max       float64  // highest accepted int or number, no limit when not above Min
+	SetOpt    func(opt *core.Opt, value interface{})
+	SetFinder func(finder FinderBase, value interface{})
+}
+
+/**
+ * The options a finder accepts, in the order they are applied.
+ */
+type TSchema []TOption
+
+var diagonalMovements = []core.DiagonalMovement{core.Never, core.Always, core.IfAtMostOneObstacle, core.OnlyWhenNoObstacles}
+
+func movementNames() []string {
+	var names []string
+	for _, move := range diagonalMovements {
+		names = append(names, move.String())
+	}
+	return names
+}
+
+var (
+	DiagonalMovementOption = TOption{
+		Name:   "diagonalMovement",
+		Kind:   OptionString,
+		Doc:    "allowed diagonal movement",
+		Values: movementNames(),
+		SetOpt: func(opt *core.Opt, value interface{}) {
+			opt.DiagonalMovement, _ = core.ParseDiagonalMovement(value.(string))
+		},
+	}
+	AllowDiagonalOption = TOption{
+		Name: "allowDiagonal",
+		Kind: OptionBool,
+		Doc:  "whether diagonal movement is allowed, deprecated",
+		SetOpt: func(opt *core.Opt, value interface{}) {
+			opt.AllowDiagonal = value.(bool)
+		},
+	}
+	DontCrossCornersOption = TOption{
+		Name: "dontCrossCorners",
+		Kind: OptionBool,
+		Doc:  "disallow diagonal movement touching block corners, deprecated",
+		SetOpt: func(opt *core.Opt, value interface{}) {
+			opt.DontCrossCorners = value.(bool)
+		},
+	}
+	AgentSizeOption = TOption{
+		Name: "agentSize",
+		Kind: OptionInt,
+		Doc:  "side in cells of the square agent",
+		Min:  1,
+		Max:  math.MaxInt32,
+		SetOpt: func(opt *core.Opt, value interface{}) {
+			opt.AgentSize = int32(value.(int64))
+		},
+	}
+	HeuristicOption = TOption{
+		Name:   "heuristic",
+		Kind:   OptionString,
+		Doc:    "heuristic estimating the distance to the goal",
+		Values: core.HeuristicNames(),
+		SetOpt: func(opt *core.Opt, value interface{}) {
+			opt.Heuristic, _ = core.HeuristicByName(value.(string))
+		},
+	}
+	WeightOption = TOption{
+		Name: "weight",
+		Kind: OptionNumber,
+		Doc:  "weight of the heuristic, above 1 for faster but longer paths",
+		Min:  1,
+		SetOpt: func(opt *core.Opt, value interface{}) {
+			opt.Weight = value.(float64)
+		},
+	}
+	TieBreakingOption = TOption{
+		Name:   "tieBreaking",
+		Kind:   OptionString,
+		Doc:    "which of the paths of equal cost is found",
+		Values: core.TieBreakingNames(),
+		SetOpt: func(opt *core.Opt, value interface{}) {
+			opt.TieBreaking, _ = core.ParseTieBreaking(value.(string))
+		},
+	}
+)
+
+/**
+ * Options of every finder searching grids.
+ */
+var GridSchema = TSchema{DiagonalMovementOption, AllowDiagonalOption, DontCrossCornersOption, AgentSizeOption}
+
+/**
+ * Options of the finders guided by a heuristic, the default of Register.
+ */
+var HeuristicSchema = GridSchema.With(HeuristicOption, WeightOption)
+
+/**
+ * A copy of the schema with more options.
+ */
+func (this TSchema) With(options ...TOption) TSchema {
+	schema := make(TSchema, 0, len(this)+len(options))
+	return append(append(schema, this...), options...)
+}
+
+/**
+ * The option called name, nil if there is none.
+ */
+func (this TSchema) Lookup(name string) *TOption {
+	for i := range this {
+		if this[i].Name == name {
+			return &this[i]
+		}
+	}
+	return nil
+}
+
+/**
+ * Names of the options.
+ */
+func (this TSchema) Names() []string {
+	var names []string
+	for _, option := range this {
+		names = append(names, option.Name)
+	}
+	return names
+}
+
+/**
+ * Convert a config value to the Kind of the option and check it.
+ * Numbers may be of any Go numeric type or json.Number, as decoded from
+ * config files; ints must be whole.
+ */
+func (this *TOption) Convert(value interface{}) (interface{}, error) {
+	switch this.Kind {
+	case OptionBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case OptionInt, OptionNumber:
+		v, ok := toFloat(value)
+		if !ok {
+			break
+		}
+		if this.Kind == OptionInt && v != math.Trunc(v) {
+			return nil, fmt.Errorf("option %s: %v is not a whole number", this.Name, value)
+		}
+		if v < this.Min || (this.Max > this.Min && v > this.Max) {
+			if this.Max > this.Min {
+				return nil, fmt.Errorf("option %s: %v outside [%g, %g]", this.Name, value, this.Min, this.Max)
+			}
+			return nil, fmt.Errorf("option %s: %v below %g", this.Name, value, this.Min)
+		}
+		if this.Kind == OptionInt {
+			return int64(v), nil
+		}
+		return v, nil
+	case OptionString:
+		v, ok := value.(string)
+		if !ok {
+			break
+		}
+		if len(this.Values) == 0 {
+			return v, nil
+		}
+		for _, accepted := range this.Values {
+			if v == accepted {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("option %s: %q is not one of %s", this.Name, v, strings.Join(this.Values, ", "))
+	}
+	return nil, fmt.Errorf("option %s: %v (%T), want %s", this.Name, value, value, this.Kind)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, !math.IsNaN(v) && !math.IsInf(v, 0)
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+type setting struct {
+	option *TOption
+	value  interface{}
+}
+
+// parse checks the config against the schema and returns its settings in
+// the order of the schema.
+func (this TSchema) parse(config map[string]interface{}) ([]setting, error) {
+	var unknown []string
+	for name := range config {
+		if this.Lookup(name) == nil {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown option %s, expected %s", strings.Join(unknown, ", "), strings.Join(this.Names(), ", "))
+	}
+	var settings []setting
+	for i := range this {
+		option := &this[i]
+		value, ok := config[option.Name]
+		if !ok {
+			continue
+		}
+		converted, err := option.Convert(value)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, setting{option, converted})
+	}
+	return settings, nil
+}
@@ -4,6 +4,10 @@ import (
 	"go-PathFinding/core"
 )
 
+/**
+ * Search scenarios. ExpectedLength is the number of nodes of a shortest
+ * path without diagonal moves.
+ */
 var (
 	PathData = []struct {
 		StartX         int
@@ -50,7 +54,7 @@ func init() {
 			{0, 0, 0},
 			{1, 1, 0},
 			{0, 0, 0}},
-		ExpectedLength: 3,
+		ExpectedLength: 5,
 	})
 
 	PathData = append(PathData, struct {
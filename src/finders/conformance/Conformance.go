@@ -0,0 +1,219 @@
+package conformance
+
+/*
+	by stefan 2572915286@qq.com
+
+	Conformance suite for finders.FinderBase implementations on grids. A
+	finder package plugs in from its tests:
+
+		func TestConformance(t *testing.T) {
+			conformance.Run(t, func(opt *core.Opt) finders.FinderBase {
+				return CreateMyFinder(opt)
+			}, conformance.TClaims{Optimal: true})
+		}
+*/
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"testing"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	"go-PathFinding/finders/config"
+	"go-PathFinding/validate"
+)
+
+/**
+ * What a finder promises. Optimal finders must return paths as short as
+ * the reference search. Moves lists the diagonal movements it supports,
+ * all of them when empty.
+ */
+type TClaims struct {
+	Optimal bool
+	Moves   []core.DiagonalMovement
+}
+
+/**
+ * A search of the suite. NeverLength is the number of nodes of a shortest
+ * path without diagonal moves, 0 when it is not recorded.
+ */
+type TCase struct {
+	Name        string
+	Matrix      core.DoubleInt32
+	StartX      int
+	StartY      int
+	EndX        int
+	EndY        int
+	NeverLength int
+}
+
+/**
+ * Every diagonal movement, in the order the suite runs them.
+ */
+var AllMoves = []core.DiagonalMovement{core.Never, core.Always, core.IfAtMostOneObstacle, core.OnlyWhenNoObstacles}
+
+/**
+ * The searches of config.PathData, then the edge cases.
+ */
+func Cases() []TCase {
+	var cases []TCase
+	for i, item := range config.PathData {
+		cases = append(cases, TCase{
+			Name:        fmt.Sprintf("pathData%d", i),
+			Matrix:      item.Matrix,
+			StartX:      item.StartX,
+			StartY:      item.StartY,
+			EndX:        item.EndX,
+			EndY:        item.EndY,
+			NeverLength: item.ExpectedLength,
+		})
+	}
+	open := core.DoubleInt32{
+		{0, 0, 0},
+		{0, 0, 0},
+		{0, 0, 0},
+	}
+	walledIn := core.DoubleInt32{
+		{0, 0, 0, 0},
+		{0, 0, 1, 1},
+		{0, 0, 1, 0},
+	}
+	field := core.DoubleInt32{
+		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 1, 1, 1, 0, 0, 1, 0, 0, 0},
+		{0, 0, 0, 1, 0, 0, 1, 0, 1, 0},
+		{1, 1, 0, 1, 0, 1, 1, 0, 1, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0, 1, 0},
+		{0, 1, 1, 1, 1, 1, 1, 0, 1, 0},
+		{0, 0, 0, 0, 0, 0, 1, 0, 0, 0},
+	}
+	return append(cases,
+		TCase{Name: "startIsGoal", Matrix: open, StartX: 1, StartY: 1, EndX: 1, EndY: 1, NeverLength: 1},
+		TCase{Name: "singleCell", Matrix: core.DoubleInt32{{0}}, NeverLength: 1},
+		TCase{Name: "unreachable", Matrix: walledIn, EndX: 3, EndY: 2},
+		TCase{Name: "blockedStart", Matrix: walledIn, StartX: 2, StartY: 1, EndX: 0, EndY: 0},
+		TCase{Name: "blockedGoal", Matrix: walledIn, EndX: 3, EndY: 1},
+		TCase{Name: "row", Matrix: core.DoubleInt32{{0, 0, 0, 0, 0, 0}}, EndX: 5, NeverLength: 6},
+		TCase{Name: "column", Matrix: core.DoubleInt32{{0}, {0}, {0}, {0}}, StartY: 3, NeverLength: 4},
+		TCase{Name: "corners", Matrix: field, EndX: 9, EndY: 6, NeverLength: 16},
+		TCase{Name: "otherCorners", Matrix: field, StartX: 9, EndY: 6, NeverLength: 16},
+		TCase{Name: "alongTheEdge", Matrix: field, StartX: 0, StartY: 6, EndX: 0, EndY: 4, NeverLength: 3},
+		// only diagonal moves between two blocked cells lead to the goal.
+		TCase{Name: "squeeze", Matrix: core.DoubleInt32{{0, 1}, {1, 0}}, EndX: 1, EndY: 1},
+	)
+}
+
+// reference is a plain Dijkstra over the grid neighbours, the cost of the
+// shortest path and whether there is one.
+func reference(grid *core.TGrid, opt *core.Opt, start, end core.NodeID) (float64, bool) {
+	if !grid.IsWalkable(start, opt) || !grid.IsWalkable(end, opt) {
+		return 0, false
+	}
+	var dist = map[core.NodeID]float64{start: 0}
+	var open = &queue{{start, 0}}
+	for open.Len() > 0 {
+		item := heap.Pop(open).(entry)
+		if item.id == end {
+			return item.g, true
+		}
+		if item.g > dist[item.id] {
+			continue
+		}
+		for _, next := range grid.Neighbors(item.id, opt) {
+			g := item.g + grid.Cost(item.id, next)
+			if old, ok := dist[next]; !ok || g < old {
+				dist[next] = g
+				heap.Push(open, entry{next, g})
+			}
+		}
+	}
+	return 0, false
+}
+
+type entry struct {
+	id core.NodeID
+	g  float64
+}
+
+type queue []entry
+
+func (this queue) Len() int            { return len(this) }
+func (this queue) Less(i, j int) bool  { return this[i].g < this[j].g }
+func (this queue) Swap(i, j int)       { this[i], this[j] = this[j], this[i] }
+func (this *queue) Push(x interface{}) { *this = append(*this, x.(entry)) }
+func (this *queue) Pop() interface{} {
+	old := *this
+	item := old[len(old)-1]
+	*this = old[:len(old)-1]
+	return item
+}
+
+/**
+ * Run one case under one diagonal movement and return what is wrong with
+ * the result, nil when nothing is. The path must be valid, empty exactly
+ * when there is none, as short as the reference for optimal finders, and
+ * the same when searched again by the same finder.
+ */
+func Check(create finders.Creator, claims TClaims, c TCase, move core.DiagonalMovement) error {
+	grid := core.Grid(len(c.Matrix[0]), len(c.Matrix), c.Matrix)
+	start, end := grid.NodeID(c.StartX, c.StartY), grid.NodeID(c.EndX, c.EndY)
+	hash := grid.ContentHash()
+
+	finder := create(&core.Opt{DiagonalMovement: move})
+	path := finder.FindPath(start, end, grid)
+	if grid.ContentHash() != hash {
+		return fmt.Errorf("the search changed the grid")
+	}
+	optimal, found := reference(grid, &core.Opt{DiagonalMovement: move}, start, end)
+	if !found {
+		if len(path) != 0 {
+			return fmt.Errorf("path %v where there is none", grid.PathCoords(path))
+		}
+		return nil
+	}
+	if len(path) == 0 {
+		return fmt.Errorf("no path found, the shortest costs %g", optimal)
+	}
+
+	coords := grid.PathCoords(path)
+	if err := validate.NewValidator(grid, &core.Opt{DiagonalMovement: move}).Path(coords, c.StartX, c.StartY, c.EndX, c.EndY); err != nil {
+		return fmt.Errorf("path %v: %v", coords, err)
+	}
+	if claims.Optimal {
+		if cost := core.PathCost(grid, path); math.Abs(cost-optimal) > 1e-9 {
+			return fmt.Errorf("path %v costs %g, the shortest %g", coords, cost, optimal)
+		}
+		if move == core.Never && c.NeverLength > 0 && len(path) != c.NeverLength {
+			return fmt.Errorf("path %v has %d nodes, expected %d", coords, len(path), c.NeverLength)
+		}
+	}
+
+	// a second search must not see the state left by the first one.
+	again := finder.FindPath(start, end, grid)
+	if fmt.Sprint(again) != fmt.Sprint(path) {
+		return fmt.Errorf("path %v searched again as %v", coords, grid.PathCoords(again))
+	}
+	return nil
+}
+
+/**
+ * Run every case under every claimed diagonal movement, one subtest each.
+ */
+func Run(t *testing.T, create finders.Creator, claims TClaims) {
+	moves := claims.Moves
+	if len(moves) == 0 {
+		moves = AllMoves
+	}
+	for _, c := range Cases() {
+		for _, move := range moves {
+			c, move := c, move
+			t.Run(c.Name+"/"+move.String(), func(t *testing.T) {
+				if err := Check(create, claims, c, move); err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+	}
+}
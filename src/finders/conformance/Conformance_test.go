@@ -0,0 +1,112 @@
+package conformance
+
+import (
+	"strings"
+	"testing"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+)
+
+func TestCases(t *testing.T) {
+	// the recorded lengths are those of the reference search.
+	for _, c := range Cases() {
+		grid := core.Grid(len(c.Matrix[0]), len(c.Matrix), c.Matrix)
+		cost, found := reference(grid, &core.Opt{DiagonalMovement: core.Never}, grid.NodeID(c.StartX, c.StartY), grid.NodeID(c.EndX, c.EndY))
+		if c.NeverLength > 0 && (!found || int(cost)+1 != c.NeverLength) {
+			t.Fatalf("%s: shortest path of %v nodes, recorded %d", c.Name, cost+1, c.NeverLength)
+		}
+	}
+}
+
+// straightFinder walks straight at the goal, through walls.
+type straightFinder struct{}
+
+func (this straightFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	return core.ArrayNodeID{start, end}
+}
+
+// detourFinder goes through every walkable neighbour of the start first.
+type detourFinder struct {
+	finder finders.FinderBase
+}
+
+func (this detourFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	path := this.finder.FindPath(start, end, graph)
+	if len(path) < 2 {
+		return path
+	}
+	return append(core.ArrayNodeID{start, path[1]}, path...)
+}
+
+// forgetfulFinder finds nothing once it has searched.
+type forgetfulFinder struct {
+	finder finders.FinderBase
+	used   bool
+}
+
+func (this *forgetfulFinder) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	if this.used {
+		return core.ArrayNodeID{}
+	}
+	this.used = true
+	return this.finder.FindPath(start, end, graph)
+}
+
+// referenceFinder follows the reference search through a finder.
+func referenceFinder() finders.Creator {
+	return func(opt *core.Opt) finders.FinderBase {
+		return finderFunc(func(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+			grid := graph.(*core.TGrid)
+			if _, found := reference(grid, opt, start, end); !found {
+				return core.ArrayNodeID{}
+			}
+			// walk back greedily from the goal on the distances to the start.
+			var path = core.ArrayNodeID{end}
+			for path[len(path)-1] != start {
+				current := path[len(path)-1]
+				here, _ := reference(grid, opt, start, current)
+				for _, prev := range grid.Neighbors(current, opt) {
+					if g, ok := reference(grid, opt, start, prev); ok && g+grid.Cost(prev, current) <= here+1e-9 {
+						path = append(path, prev)
+						break
+					}
+				}
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path
+		})
+	}
+}
+
+type finderFunc func(start, end core.NodeID, graph core.Graph) core.ArrayNodeID
+
+func (this finderFunc) FindPath(start, end core.NodeID, graph core.Graph) core.ArrayNodeID {
+	return this(start, end, graph)
+}
+
+func TestCheck(t *testing.T) {
+	Run(t, referenceFinder(), TClaims{Optimal: true})
+
+	corners := Cases()[len(Cases())-4]
+	for _, c := range []struct {
+		create finders.Creator
+		claims TClaims
+		reason string
+	}{
+		{func(opt *core.Opt) finders.FinderBase { return straightFinder{} }, TClaims{}, "jumps from"},
+		{func(opt *core.Opt) finders.FinderBase { return detourFinder{referenceFinder()(opt)} }, TClaims{Optimal: true}, "costs"},
+		{func(opt *core.Opt) finders.FinderBase { return &forgetfulFinder{finder: referenceFinder()(opt)} }, TClaims{}, "searched again"},
+	} {
+		err := Check(c.create, c.claims, corners, core.Never)
+		if err == nil || !strings.Contains(err.Error(), c.reason) {
+			t.Fatalf("%v, expected %q", err, c.reason)
+		}
+	}
+	// a detour is fine for finders not claiming optimality.
+	if err := Check(func(opt *core.Opt) finders.FinderBase { return detourFinder{referenceFinder()(opt)} }, TClaims{}, corners, core.Always); err != nil {
+		t.Fatal(err)
+	}
+}
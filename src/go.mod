@@ -8,10 +8,11 @@ require (
 	github.com/axgle/mahonia v0.0.0-20180208002826-3358181d7394 // indirect
 	github.com/bwmarrin/snowflake v0.3.0 // indirect
 	github.com/go-ole/go-ole v1.2.4 // indirect
-	github.com/golang/protobuf v1.4.3 // indirect
-	github.com/gomodule/redigo v1.8.2 // indirect
+	github.com/golang/protobuf v1.4.3
+	github.com/gomodule/redigo v1.8.2
 	github.com/gonutz/ide v0.0.0-20200517034207-df64a3832118 // indirect
 	github.com/satori/go.uuid v1.2.0 // indirect
 	github.com/sony/sonyflake v1.0.0 // indirect
 	golang.org/x/sys v0.0.0-20201029080932-201ba4db2418 // indirect
+	google.golang.org/protobuf v1.23.0
 )
@@ -0,0 +1,382 @@
+package mapf
+
+/*
+	by stefan 2572915286@qq.com
+	Conflict-Based Search and its bounded-suboptimal variant ECBS,
+	based upon G. Sharon et al., "Conflict-based search for optimal
+	multi-agent pathfinding", AIJ 2015 and M. Barer et al.,
+	"Suboptimal variants of the conflict-based search algorithm", SoCS 2014.
+*/
+
+import (
+	"errors"
+	"go-PathFinding/core"
+	"time"
+)
+
+var (
+	ErrNoSolution = errors.New("mapf: no collision-free solution")
+	ErrTimeLimit  = errors.New("mapf: time limit reached")
+)
+
+type TCBSSolver struct {
+	FinderOpt     *core.Opt
+	Suboptimality float64       // ECBS bound w, 1 for optimal CBS
+	TimeLimit     time.Duration // 0 for none
+	MaxTime       int32         // last timestep a path may reach
+}
+
+/**
+ * Conflict-Based Search solver.
+ * @constructor
+ * @param {Object} opt
+ * @param {DiagonalMovement} opt.diagonalMovement Allowed diagonal movement.
+ * @param {number} w Suboptimality bound: the sum of costs found is at most
+ *     w times the optimal one. 1 runs plain CBS, more runs ECBS, which
+ *     trades cost for far fewer expansions on crowded maps.
+ */
+func CreateCBSSolver(opt *core.Opt, w float64) (this *TCBSSolver) {
+	this = &TCBSSolver{
+		FinderOpt:     opt,
+		Suboptimality: w,
+		MaxTime:       1024,
+	}
+	if this.Suboptimality < 1 {
+		this.Suboptimality = 1
+	}
+	if this.FinderOpt.DiagonalMovement == 0 {
+		if !this.FinderOpt.AllowDiagonal {
+			this.FinderOpt.DiagonalMovement = core.Never
+		} else {
+			if this.FinderOpt.DontCrossCorners {
+				this.FinderOpt.DiagonalMovement = core.OnlyWhenNoObstacles
+			} else {
+				this.FinderOpt.DiagonalMovement = core.IfAtMostOneObstacle
+			}
+		}
+	}
+	return
+}
+
+type constraint struct {
+	agent int
+	edge  bool
+	v     vertexKey // vertex constraint, or time and origin of an edge
+	to    cellKey   // destination of an edge constraint
+	next  *constraint
+}
+
+/**
+ * The constraints of one agent, collected along a constraint tree branch.
+ */
+type constraintSet struct {
+	vertices map[vertexKey]bool
+	edges    map[edgeKey]bool
+	last     map[cellKey]int32
+}
+
+func collectConstraints(agent int, head *constraint) *constraintSet {
+	set := &constraintSet{
+		vertices: map[vertexKey]bool{},
+		edges:    map[edgeKey]bool{},
+		last:     map[cellKey]int32{},
+	}
+	for c := head; c != nil; c = c.next {
+		if c.agent != agent {
+			continue
+		}
+		if c.edge {
+			set.edges[edgeKey{x1: c.v.x, y1: c.v.y, x2: c.to.x, y2: c.to.y, t: c.v.t}] = true
+			continue
+		}
+		set.vertices[c.v] = true
+		cell := cellKey{x: c.v.x, y: c.v.y}
+		if last, ok := set.last[cell]; !ok || c.v.t > last {
+			set.last[cell] = c.v.t
+		}
+	}
+	return set
+}
+
+func (this *constraintSet) VertexBlocked(agent int, x, y int, t int32) bool {
+	return this.vertices[vertexKey{x: int32(x), y: int32(y), t: t}]
+}
+
+func (this *constraintSet) EdgeBlocked(agent int, x1, y1, x2, y2 int, t int32) bool {
+	return this.edges[edgeKey{x1: int32(x1), y1: int32(y1), x2: int32(x2), y2: int32(y2), t: t}]
+}
+
+func (this *constraintSet) LastReserved(agent int, x, y int) int32 {
+	if last, ok := this.last[cellKey{x: int32(x), y: int32(y)}]; ok {
+		return last
+	}
+	return -1
+}
+
+/**
+ * How many other agents a step collides with, the focal heuristic of
+ * the ECBS low level.
+ */
+type conflictTable struct {
+	vertices map[vertexKey]int
+	edges    map[edgeKey]int
+	parked   map[cellKey][]int32
+}
+
+func newConflictTable(paths []core.TimedPath, skip int) *conflictTable {
+	table := &conflictTable{
+		vertices: map[vertexKey]int{},
+		edges:    map[edgeKey]int{},
+		parked:   map[cellKey][]int32{},
+	}
+	for i, path := range paths {
+		if i == skip || len(path) == 0 {
+			continue
+		}
+		for s, step := range path {
+			table.vertices[vertexKey{x: step.X, y: step.Y, t: int32(step.T)}]++
+			if s > 0 {
+				prev := path[s-1]
+				table.edges[edgeKey{x1: prev.X, y1: prev.Y, x2: step.X, y2: step.Y, t: int32(prev.T)}]++
+			}
+		}
+		last := path[len(path)-1]
+		cell := cellKey{x: last.X, y: last.Y}
+		table.parked[cell] = append(table.parked[cell], int32(last.T))
+	}
+	return table
+}
+
+func (this *conflictTable) count(x1, y1, x2, y2 int, t int32) int {
+	n := this.vertices[vertexKey{x: int32(x2), y: int32(y2), t: t + 1}]
+	n += this.edges[edgeKey{x1: int32(x2), y1: int32(y2), x2: int32(x1), y2: int32(y1), t: t}]
+	for _, from := range this.parked[cellKey{x: int32(x2), y: int32(y2)}] {
+		if t+1 > from {
+			n++
+		}
+	}
+	return n
+}
+
+type conflict struct {
+	a    int
+	b    int
+	edge bool
+	x1   int32 // the vertex, or where a comes from
+	y1   int32
+	x2   int32 // where a goes to on an edge conflict
+	y2   int32
+	t    int32 // time of the vertex, or departure time of the edge
+}
+
+func positionAt(path core.TimedPath, t int32) core.TimedCoordinate {
+	if int(t) >= len(path) {
+		return path[len(path)-1]
+	}
+	return path[t]
+}
+
+/**
+ * Find the first conflict between the paths, and count them all.
+ * Agents stay on their goal once their path is over.
+ */
+func findConflicts(paths []core.TimedPath) (*conflict, int) {
+	var first *conflict
+	var count int
+	var horizon int32
+	for _, path := range paths {
+		if int32(len(path)) > horizon {
+			horizon = int32(len(path))
+		}
+	}
+	for t := int32(0); t < horizon; t++ {
+		for a := range paths {
+			for b := a + 1; b < len(paths); b++ {
+				pa, pb := positionAt(paths[a], t), positionAt(paths[b], t)
+				if pa.X == pb.X && pa.Y == pb.Y {
+					count++
+					if first == nil {
+						first = &conflict{a: a, b: b, x1: pa.X, y1: pa.Y, t: t}
+					}
+					continue
+				}
+				if t == 0 {
+					continue
+				}
+				qa, qb := positionAt(paths[a], t-1), positionAt(paths[b], t-1)
+				if qa.X == pb.X && qa.Y == pb.Y && qb.X == pa.X && qb.Y == pa.Y {
+					count++
+					if first == nil {
+						first = &conflict{a: a, b: b, edge: true, x1: qa.X, y1: qa.Y, x2: pa.X, y2: pa.Y, t: t - 1}
+					}
+				}
+			}
+		}
+	}
+	return first, count
+}
+
+type ctNode struct {
+	constraints *constraint
+	paths       []core.TimedPath
+	costs       []float64
+	bounds      []float64
+	cost        float64
+	bound       float64
+	conflicts   int
+	first       *conflict
+}
+
+func (this *ctNode) total() {
+	this.cost = 0
+	this.bound = 0
+	for i := range this.paths {
+		this.cost += this.costs[i]
+		this.bound += this.bounds[i]
+	}
+	this.first, this.conflicts = findConflicts(this.paths)
+}
+
+/**
+ * Find collision-free timed paths for every agent, minimising the sum
+ * of their costs (within the suboptimality bound).
+ * @return {[]core.TimedPath} One path per agent, in the agents order;
+ *     ErrNoSolution or ErrTimeLimit when there is none.
+ */
+func (this *TCBSSolver) Solve(agents []TAgent, grid *core.TGrid) ([]core.TimedPath, error) {
+	started := time.Now()
+	w := this.Suboptimality
+	queries := make([]*spaceTimeQuery, len(agents))
+	for i, agent := range agents {
+		queries[i] = &spaceTimeQuery{
+			agent:  agent.Id,
+			grid:   grid,
+			opt:    this.FinderOpt,
+			startX: agent.StartX,
+			startY: agent.StartY,
+			goalX:  agent.GoalX,
+			goalY:  agent.GoalY,
+			maxT:   this.MaxTime,
+			dist:   distanceMap(grid, this.FinderOpt, agent.GoalX, agent.GoalY),
+		}
+	}
+
+	replan := func(node *ctNode, i int) bool {
+		var table *conflictTable
+		if w > 1 {
+			table = newConflictTable(node.paths, i)
+		}
+		path, cost, bound := queries[i].searchFocal(collectConstraints(agents[i].Id, node.constraints), w, table)
+		if path == nil {
+			return false
+		}
+		node.paths[i] = path
+		node.costs[i] = cost
+		node.bounds[i] = bound
+		return true
+	}
+
+	root := &ctNode{
+		paths:  make([]core.TimedPath, len(agents)),
+		costs:  make([]float64, len(agents)),
+		bounds: make([]float64, len(agents)),
+	}
+	for i := range agents {
+		if !replan(root, i) {
+			return nil, ErrNoSolution
+		}
+	}
+	root.total()
+
+	var open = []*ctNode{root}
+	for len(open) > 0 {
+		if this.TimeLimit > 0 && time.Since(started) > this.TimeLimit {
+			return nil, ErrTimeLimit
+		}
+
+		// FOCAL: the nodes within w of the best lower bound, fewest
+		// conflicts first. With w = 1 this is the cheapest node.
+		var lb = open[0].bound
+		for _, node := range open {
+			if node.bound < lb {
+				lb = node.bound
+			}
+		}
+		best := -1
+		for i, node := range open {
+			if node.cost > lb*w+1e-9 {
+				continue
+			}
+			if best < 0 || node.conflicts < open[best].conflicts ||
+				(node.conflicts == open[best].conflicts && node.cost < open[best].cost) {
+				best = i
+			}
+		}
+		if best < 0 {
+			// costs rounded just above the bound.
+			best = 0
+			for i, node := range open {
+				if node.cost < open[best].cost {
+					best = i
+				}
+			}
+		}
+		node := open[best]
+		open = append(open[:best], open[best+1:]...)
+
+		if node.first == nil {
+			return node.paths, nil
+		}
+
+		c := node.first
+		for side, agent := range []int{c.a, c.b} {
+			child := &ctNode{
+				paths:  append([]core.TimedPath{}, node.paths...),
+				costs:  append([]float64{}, node.costs...),
+				bounds: append([]float64{}, node.bounds...),
+			}
+			added := &constraint{agent: agents[agent].Id, next: node.constraints}
+			switch {
+			case !c.edge:
+				added.v = vertexKey{x: c.x1, y: c.y1, t: c.t}
+			case side == 0:
+				added.edge = true
+				added.v = vertexKey{x: c.x1, y: c.y1, t: c.t}
+				added.to = cellKey{x: c.x2, y: c.y2}
+			default:
+				added.edge = true
+				added.v = vertexKey{x: c.x2, y: c.y2, t: c.t}
+				added.to = cellKey{x: c.x1, y: c.y1}
+			}
+			child.constraints = added
+			if !replan(child, agent) {
+				continue
+			}
+			child.total()
+			open = append(open, child)
+		}
+	}
+
+	return nil, ErrNoSolution
+}
+
+/**
+ * The cost of a timed path: every move costs its length and every wait a
+ * straight step, except the waits closing the path.
+ */
+func PathCost(grid *core.TGrid, path core.TimedPath) float64 {
+	var cost float64
+	wait, _ := grid.StepCosts()
+	end := len(path)
+	for end > 1 && path[end-1].X == path[end-2].X && path[end-1].Y == path[end-2].Y {
+		end--
+	}
+	for i := 1; i < end; i++ {
+		if path[i].X == path[i-1].X && path[i].Y == path[i-1].Y {
+			cost += wait
+			continue
+		}
+		cost += grid.Cost(grid.NodeID(int(path[i-1].X), int(path[i-1].Y)), grid.NodeID(int(path[i].X), int(path[i].Y)))
+	}
+	return cost
+}
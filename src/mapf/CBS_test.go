@@ -0,0 +1,87 @@
+package mapf
+
+import (
+	"go-PathFinding/core"
+	"testing"
+	"time"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+func sumOfCosts(grid *core.TGrid, paths []core.TimedPath) float64 {
+	var sum float64
+	for _, path := range paths {
+		sum += PathCost(grid, path)
+	}
+	return sum
+}
+
+func TestCBS(t *testing.T) {
+	grid := core.Grid(7, 2, corridor)
+	agents := []TAgent{
+		{Id: 1, StartX: 0, StartY: 0, GoalX: 6, GoalY: 0},
+		{Id: 2, StartX: 6, StartY: 0, GoalX: 0, GoalY: 0},
+	}
+	solver := CreateCBSSolver(&core.Opt{}, 1)
+	paths, err := solver.Solve(agents, grid)
+	akLog.FmtPrintln("result: ", paths, err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPlans(t, agents, paths)
+	// one agent waits once, steps into the pocket and back: 6 + 6 + 3.
+	if cost := sumOfCosts(grid, paths); cost != 15 {
+		t.Fatalf("sum of costs %v, expected 15", cost)
+	}
+
+	// the 10/14 costs price the waits as straight steps too.
+	grid.SetCostModel(core.FixedCosts)
+	fixed, err := solver.Solve(agents, grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPlans(t, agents, fixed)
+	if cost := sumOfCosts(grid, fixed); cost != 150 {
+		t.Fatalf("sum of costs %v under fixed costs, expected 150", cost)
+	}
+}
+
+func TestECBS(t *testing.T) {
+	grid := core.Grid(5, 5, nil)
+	agents := []TAgent{
+		{Id: 1, StartX: 0, StartY: 2, GoalX: 4, GoalY: 2},
+		{Id: 2, StartX: 4, StartY: 2, GoalX: 0, GoalY: 2},
+		{Id: 3, StartX: 2, StartY: 0, GoalX: 2, GoalY: 4},
+		{Id: 4, StartX: 2, StartY: 4, GoalX: 2, GoalY: 0},
+	}
+	optimal, err := CreateCBSSolver(&core.Opt{}, 1).Solve(agents, grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPlans(t, agents, optimal)
+
+	solver := CreateCBSSolver(&core.Opt{}, 1.5)
+	paths, err := solver.Solve(agents, grid)
+	akLog.FmtPrintln("result: ", paths, err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPlans(t, agents, paths)
+	if sumOfCosts(grid, paths) > 1.5*sumOfCosts(grid, optimal) {
+		t.Fatalf("ECBS cost %v above bound of %v", sumOfCosts(grid, paths), sumOfCosts(grid, optimal))
+	}
+}
+
+func TestCBSNoSolution(t *testing.T) {
+	grid := core.Grid(3, 1, nil)
+	agents := []TAgent{
+		{Id: 1, StartX: 0, StartY: 0, GoalX: 2, GoalY: 0},
+		{Id: 2, StartX: 2, StartY: 0, GoalX: 0, GoalY: 0},
+	}
+	solver := CreateCBSSolver(&core.Opt{}, 1)
+	solver.TimeLimit = 200 * time.Millisecond
+	solver.MaxTime = 16
+	if _, err := solver.Solve(agents, grid); err == nil {
+		t.Fatal("agents swapped in a dead end corridor")
+	}
+}
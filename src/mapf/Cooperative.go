@@ -0,0 +1,260 @@
+package mapf
+
+/*
+	by stefan 2572915286@qq.com
+	Cooperative A* (CA*) and Windowed Hierarchical Cooperative A* (WHCA*),
+	based upon D. Silver, "Cooperative Pathfinding", AIIDE 2005.
+*/
+
+import (
+	"go-PathFinding/core"
+)
+
+type TAgent struct {
+	Id     int
+	StartX int
+	StartY int
+	GoalX  int
+	GoalY  int
+}
+
+type TCooperativeFinder struct {
+	FinderOpt *core.Opt
+	Window    int32 // planning window in timesteps, 0 for plain CA*
+	MaxTime   int32 // last timestep a plan may reach
+}
+
+/**
+ * Cooperative path-finder.
+ * Agents plan one after another over (x, y, t), each one avoiding the
+ * cells and moves reserved by the agents planned before it.
+ * @constructor
+ * @param {Object} opt
+ * @param {DiagonalMovement} opt.diagonalMovement Allowed diagonal movement.
+ * @param {number} window Planning window: every agent plans window
+ *     timesteps ahead, and plans are redone as the window rolls forward
+ *     (WHCA*). 0 plans every path to its goal once (CA*).
+ */
+func CreateCooperativeFinder(opt *core.Opt, window int32) (this *TCooperativeFinder) {
+	this = &TCooperativeFinder{
+		FinderOpt: opt,
+		Window:    window,
+		MaxTime:   1024,
+	}
+	if this.FinderOpt.DiagonalMovement == 0 {
+		if !this.FinderOpt.AllowDiagonal {
+			this.FinderOpt.DiagonalMovement = core.Never
+		} else {
+			if this.FinderOpt.DontCrossCorners {
+				this.FinderOpt.DiagonalMovement = core.OnlyWhenNoObstacles
+			} else {
+				this.FinderOpt.DiagonalMovement = core.IfAtMostOneObstacle
+			}
+		}
+	}
+	return
+}
+
+/**
+ * Find collision-free timed paths for every agent.
+ * @return {[]core.TimedPath} One path per agent, in the agents order,
+ *     nil for the agents CA* failed to route.
+ */
+func (this *TCooperativeFinder) FindPaths(agents []TAgent, grid *core.TGrid) []core.TimedPath {
+	if this.Window == 0 {
+		var table = NewReservationTable()
+		var paths = make([]core.TimedPath, len(agents))
+		for i, agent := range agents {
+			query := this.query(grid, agent, agent.StartX, agent.StartY, 0)
+			paths[i] = query.search(table)
+			if paths[i] != nil {
+				table.Reserve(agent.Id, paths[i], true)
+			}
+		}
+		return paths
+	}
+
+	session := this.NewSession(agents, grid)
+	for !session.Done() && session.Now() < this.MaxTime {
+		session.Replan()
+		step := this.Window / 2
+		if step < 1 {
+			step = 1
+		}
+		session.Advance(step)
+	}
+	return session.Paths()
+}
+
+func (this *TCooperativeFinder) query(grid *core.TGrid, agent TAgent, x, y int, t int32) *spaceTimeQuery {
+	return &spaceTimeQuery{
+		agent:   agent.Id,
+		grid:    grid,
+		opt:     this.FinderOpt,
+		startX:  x,
+		startY:  y,
+		startT:  t,
+		goalX:   agent.GoalX,
+		goalY:   agent.GoalY,
+		horizon: this.Window,
+		maxT:    this.MaxTime,
+		dist:    distanceMap(grid, this.FinderOpt, agent.GoalX, agent.GoalY),
+	}
+}
+
+/**
+ * Rolling WHCA* state: the position of every agent at the current time,
+ * the steps executed so far and the window planned from now.
+ */
+type TCooperativeSession struct {
+	finder  *TCooperativeFinder
+	grid    *core.TGrid
+	agents  []TAgent
+	queries []*spaceTimeQuery
+	table   *TReservationTable
+	now     int32
+	round   int
+	plans   []core.TimedPath
+	history []core.TimedPath
+}
+
+func (this *TCooperativeFinder) NewSession(agents []TAgent, grid *core.TGrid) *TCooperativeSession {
+	session := &TCooperativeSession{
+		finder:  this,
+		grid:    grid,
+		agents:  agents,
+		queries: make([]*spaceTimeQuery, len(agents)),
+		table:   NewReservationTable(),
+		plans:   make([]core.TimedPath, len(agents)),
+		history: make([]core.TimedPath, len(agents)),
+	}
+	for i, agent := range agents {
+		session.queries[i] = this.query(grid, agent, agent.StartX, agent.StartY, 0)
+		session.history[i] = core.TimedPath{{X: int32(agent.StartX), Y: int32(agent.StartY), T: 0}}
+	}
+	return session
+}
+
+func (this *TCooperativeSession) Now() int32 {
+	return this.now
+}
+
+/**
+ * Plan the next window for every agent from their current positions.
+ * The reservation table is rebuilt from scratch and the agents order is
+ * rotated on every call, so that no agent is always planned last. An
+ * agent failing to plan its window is moved to the front of the order
+ * and the window planned again.
+ */
+func (this *TCooperativeSession) Replan() {
+	n := len(this.agents)
+	var order = make([]int, n)
+	for k := range order {
+		order[k] = (k + this.round) % n
+	}
+	this.round++
+
+	for attempt := 0; ; attempt++ {
+		failed := this.planWindow(order)
+		if failed < 0 {
+			return
+		}
+		if attempt >= n {
+			break
+		}
+		for k, i := range order {
+			if i == failed {
+				copy(order[1:k+1], order[:k])
+				order[0] = failed
+				break
+			}
+		}
+	}
+
+	// still stuck, whoever failed waits for the next window.
+	for i, plan := range this.plans {
+		if plan != nil {
+			continue
+		}
+		current := this.history[i][len(this.history[i])-1]
+		plan = core.TimedPath{}
+		for t := this.now; t <= this.now+this.finder.Window; t++ {
+			plan = append(plan, core.TimedCoordinate{X: current.X, Y: current.Y, T: float64(t)})
+		}
+		this.plans[i] = plan
+	}
+}
+
+/**
+ * Plan the window of every agent in order, returns the first agent that
+ * failed or -1.
+ */
+func (this *TCooperativeSession) planWindow(order []int) int {
+	var failed = -1
+	this.table.Clear()
+	// everybody holds its current cell until it is planned.
+	for i, agent := range this.agents {
+		this.table.Reserve(agent.Id, this.history[i][len(this.history[i])-1:], false)
+	}
+	for _, i := range order {
+		agent := this.agents[i]
+		current := this.history[i][len(this.history[i])-1]
+		query := this.queries[i]
+		query.startX = int(current.X)
+		query.startY = int(current.Y)
+		query.startT = this.now
+		query.maxT = this.now + this.finder.Window
+
+		this.table.Release(agent.Id)
+		this.plans[i] = query.search(this.table)
+		if this.plans[i] == nil {
+			if failed < 0 {
+				failed = i
+			}
+			continue
+		}
+		this.table.Reserve(agent.Id, this.plans[i], false)
+	}
+	return failed
+}
+
+/**
+ * Execute steps timesteps of the current plans.
+ */
+func (this *TCooperativeSession) Advance(steps int32) {
+	for i := range this.agents {
+		plan := this.plans[i]
+		for s := int32(1); s <= steps && int(s) < len(plan); s++ {
+			this.history[i] = append(this.history[i], plan[s])
+		}
+	}
+	this.now += steps
+}
+
+/**
+ * Whether every agent stands on its goal.
+ */
+func (this *TCooperativeSession) Done() bool {
+	for i, agent := range this.agents {
+		last := this.history[i][len(this.history[i])-1]
+		if int(last.X) != agent.GoalX || int(last.Y) != agent.GoalY {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * The executed paths, trailing waits on the goal removed.
+ */
+func (this *TCooperativeSession) Paths() []core.TimedPath {
+	var paths = make([]core.TimedPath, len(this.agents))
+	for i, path := range this.history {
+		end := len(path)
+		for end > 1 && path[end-1].X == path[end-2].X && path[end-1].Y == path[end-2].Y {
+			end--
+		}
+		paths[i] = append(core.TimedPath{}, path[:end]...)
+	}
+	return paths
+}
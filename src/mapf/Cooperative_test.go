@@ -0,0 +1,90 @@
+package mapf
+
+import (
+	"go-PathFinding/core"
+	"testing"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+// checkPlans fails on vertex or swap conflicts, teleports and missed goals.
+func checkPlans(t *testing.T, agents []TAgent, paths []core.TimedPath) {
+	horizon := 0
+	for i, path := range paths {
+		if path == nil {
+			t.Fatalf("agent %v has no path", agents[i].Id)
+		}
+		last := path[len(path)-1]
+		if int(last.X) != agents[i].GoalX || int(last.Y) != agents[i].GoalY {
+			t.Fatalf("agent %v ends on %v", agents[i].Id, last)
+		}
+		for s := 1; s < len(path); s++ {
+			dx := path[s].X - path[s-1].X
+			dy := path[s].Y - path[s-1].Y
+			if dx*dx > 1 || dy*dy > 1 || path[s].T != path[s-1].T+1 {
+				t.Fatalf("agent %v jumps from %v to %v", agents[i].Id, path[s-1], path[s])
+			}
+		}
+		if len(path) > horizon {
+			horizon = len(path)
+		}
+	}
+	for step := 0; step <= horizon; step++ {
+		for i := range paths {
+			for j := i + 1; j < len(paths); j++ {
+				a, b := positionAt(paths[i], int32(step)), positionAt(paths[j], int32(step))
+				if a.X == b.X && a.Y == b.Y {
+					t.Fatalf("agents %v and %v meet on (%v, %v) at %v", i, j, a.X, a.Y, step)
+				}
+				if step == 0 {
+					continue
+				}
+				pa, pb := positionAt(paths[i], int32(step-1)), positionAt(paths[j], int32(step-1))
+				if pa.X == b.X && pa.Y == b.Y && pb.X == a.X && pb.Y == a.Y {
+					t.Fatalf("agents %v and %v swap at %v", i, j, step)
+				}
+			}
+		}
+	}
+}
+
+// a corridor with a single side pocket.
+var corridor = core.DoubleInt32{
+	{0, 0, 0, 0, 0, 0, 0},
+	{1, 1, 1, 1, 0, 1, 1},
+}
+
+func TestCooperativeAStar(t *testing.T) {
+	grid := core.Grid(7, 2, corridor)
+	agents := []TAgent{
+		{Id: 1, StartX: 0, StartY: 0, GoalX: 6, GoalY: 0},
+		{Id: 2, StartX: 6, StartY: 0, GoalX: 0, GoalY: 0},
+	}
+	finder := CreateCooperativeFinder(&core.Opt{}, 0)
+	paths := finder.FindPaths(agents, grid)
+	akLog.FmtPrintln("result: ", paths)
+	checkPlans(t, agents, paths)
+}
+
+func TestWindowedCooperativeAStar(t *testing.T) {
+	grid := core.Grid(7, 2, corridor)
+	agents := []TAgent{
+		{Id: 1, StartX: 0, StartY: 0, GoalX: 6, GoalY: 0},
+		{Id: 2, StartX: 6, StartY: 0, GoalX: 0, GoalY: 0},
+		{Id: 3},
+	}
+	finder := CreateCooperativeFinder(&core.Opt{}, 8)
+	paths := finder.FindPaths(agents[:2], grid)
+	akLog.FmtPrintln("result: ", paths)
+	checkPlans(t, agents[:2], paths)
+
+	grid = core.Grid(7, 3, core.DoubleInt32{
+		{0, 0, 0, 0, 0, 0, 0},
+		{1, 1, 1, 0, 1, 1, 1},
+		{1, 1, 1, 0, 1, 1, 1},
+	})
+	agents[2] = TAgent{Id: 3, StartX: 3, StartY: 2, GoalX: 3, GoalY: 0}
+	paths = finder.FindPaths(agents, grid)
+	akLog.FmtPrintln("result: ", paths)
+	checkPlans(t, agents, paths)
+}
@@ -0,0 +1,154 @@
+package mapf
+
+/*
+	by stefan 2572915286@qq.com
+	Space-time reservation table shared by cooperative planners.
+*/
+
+import (
+	"go-PathFinding/core"
+)
+
+type vertexKey struct {
+	x int32
+	y int32
+	t int32
+}
+
+type edgeKey struct {
+	x1 int32
+	y1 int32
+	x2 int32
+	y2 int32
+	t  int32
+}
+
+type cellKey struct {
+	x int32
+	y int32
+}
+
+/**
+ * Space-time reservation table.
+ * A vertex reservation holds a cell at one timestep, an edge reservation
+ * the move between two cells from t to t+1. A parked agent holds its
+ * cell from a timestep onwards.
+ */
+type TReservationTable struct {
+	vertices map[vertexKey]int
+	edges    map[edgeKey]int
+	parked   map[cellKey]parking
+	owned    map[int][]interface{}
+}
+
+type parking struct {
+	agent int
+	from  int32
+}
+
+func NewReservationTable() *TReservationTable {
+	return &TReservationTable{
+		vertices: map[vertexKey]int{},
+		edges:    map[edgeKey]int{},
+		parked:   map[cellKey]parking{},
+		owned:    map[int][]interface{}{},
+	}
+}
+
+/**
+ * Reserve every state of the path for agent. With park, the agent also
+ * keeps the last cell of the path forever.
+ */
+func (this *TReservationTable) Reserve(agent int, path core.TimedPath, park bool) {
+	for i, step := range path {
+		t := int32(step.T)
+		vk := vertexKey{x: step.X, y: step.Y, t: t}
+		this.vertices[vk] = agent
+		this.owned[agent] = append(this.owned[agent], vk)
+		if i == 0 {
+			continue
+		}
+		prev := path[i-1]
+		ek := edgeKey{x1: prev.X, y1: prev.Y, x2: step.X, y2: step.Y, t: int32(prev.T)}
+		this.edges[ek] = agent
+		this.owned[agent] = append(this.owned[agent], ek)
+	}
+	if park && len(path) > 0 {
+		last := path[len(path)-1]
+		ck := cellKey{x: last.X, y: last.Y}
+		this.parked[ck] = parking{agent: agent, from: int32(last.T)}
+		this.owned[agent] = append(this.owned[agent], ck)
+	}
+}
+
+/**
+ * Drop every reservation held by agent.
+ */
+func (this *TReservationTable) Release(agent int) {
+	for _, key := range this.owned[agent] {
+		switch k := key.(type) {
+		case vertexKey:
+			if this.vertices[k] == agent {
+				delete(this.vertices, k)
+			}
+		case edgeKey:
+			if this.edges[k] == agent {
+				delete(this.edges, k)
+			}
+		case cellKey:
+			if this.parked[k].agent == agent {
+				delete(this.parked, k)
+			}
+		}
+	}
+	delete(this.owned, agent)
+}
+
+/**
+ * Drop every reservation.
+ */
+func (this *TReservationTable) Clear() {
+	this.vertices = map[vertexKey]int{}
+	this.edges = map[edgeKey]int{}
+	this.parked = map[cellKey]parking{}
+	this.owned = map[int][]interface{}{}
+}
+
+/**
+ * Whether the cell is held by another agent at time t (vertex conflict).
+ */
+func (this *TReservationTable) VertexBlocked(agent int, x, y int, t int32) bool {
+	if owner, ok := this.vertices[vertexKey{x: int32(x), y: int32(y), t: t}]; ok && owner != agent {
+		return true
+	}
+	if p, ok := this.parked[cellKey{x: int32(x), y: int32(y)}]; ok && p.agent != agent && t >= p.from {
+		return true
+	}
+	return false
+}
+
+/**
+ * Whether another agent moves from (x2, y2) to (x1, y1) between t and
+ * t+1, which would make the move (x1, y1) -> (x2, y2) a swap conflict.
+ */
+func (this *TReservationTable) EdgeBlocked(agent int, x1, y1, x2, y2 int, t int32) bool {
+	owner, ok := this.edges[edgeKey{x1: int32(x2), y1: int32(y2), x2: int32(x1), y2: int32(y1), t: t}]
+	return ok && owner != agent
+}
+
+/**
+ * The last timestep the cell is held by another agent, -1 if never.
+ * Parked cells are held forever.
+ */
+func (this *TReservationTable) LastReserved(agent int, x, y int) int32 {
+	if p, ok := this.parked[cellKey{x: int32(x), y: int32(y)}]; ok && p.agent != agent {
+		return 1<<31 - 1
+	}
+	var last int32 = -1
+	for k, owner := range this.vertices {
+		if owner != agent && k.x == int32(x) && k.y == int32(y) && k.t > last {
+			last = k.t
+		}
+	}
+	return last
+}
@@ -0,0 +1,246 @@
+package mapf
+
+/*
+	by stefan 2572915286@qq.com
+	A* over (x, y, t) states of a core.TGrid.
+*/
+
+import (
+	"container/heap"
+	"go-PathFinding/core"
+	"math"
+)
+
+/**
+ * What a space-time search must avoid, implemented by TReservationTable
+ * and by the constraint sets of Conflict-Based Search.
+ */
+type Constraints interface {
+	// whether agent may not stand on (x, y) at time t.
+	VertexBlocked(agent int, x, y int, t int32) bool
+	// whether agent may not move from (x1, y1) at t to (x2, y2) at t+1.
+	EdgeBlocked(agent int, x1, y1, x2, y2 int, t int32) bool
+	// the last time (x, y) is blocked for agent, -1 if never.
+	LastReserved(agent int, x, y int) int32
+}
+
+/**
+ * Exact distance from every cell to the goal, used as the heuristic of
+ * the space-time searches (+Inf where the goal is unreachable).
+ */
+func distanceMap(grid *core.TGrid, opt *core.Opt, goalX, goalY int) []float64 {
+	var dist = make([]float64, grid.Width()*grid.Height())
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	goal := grid.NodeID(goalX, goalY)
+	if !grid.IsWalkable(goal, opt) {
+		return dist
+	}
+	open := core.NewGridHeap()
+	nodes := map[core.NodeID]*core.AStarGrid{goal: {Id: goal, Opened: true}}
+	open.Push(nodes[goal])
+	dist[goal] = 0
+	for !open.Empty() {
+		node := open.Pop()
+		node.Closed = true
+		for _, id := range grid.Neighbors(node.Id, opt) {
+			ng := node.G + grid.Cost(id, node.Id)
+			neighbor := nodes[id]
+			if neighbor == nil {
+				neighbor = &core.AStarGrid{Id: id, G: ng, F: ng, Opened: true}
+				nodes[id] = neighbor
+				dist[id] = ng
+				open.Push(neighbor)
+			} else if !neighbor.Closed && ng < neighbor.G {
+				neighbor.G = ng
+				neighbor.F = ng
+				dist[id] = ng
+				open.UpdateItem(neighbor)
+			}
+		}
+	}
+	return dist
+}
+
+type stNode struct {
+	x      int
+	y      int
+	t      int32
+	G      float64
+	F      float64
+	C      int // conflicts with the other agents on the way here
+	Parent *stNode
+	Closed bool
+	index  int
+}
+
+type stOpenList []*stNode
+
+func (this stOpenList) Len() int { return len(this) }
+func (this stOpenList) Less(i, j int) bool {
+	if this[i].F == this[j].F {
+		// deeper in time first, it is closer to the goal.
+		return this[i].t > this[j].t
+	}
+	return this[i].F < this[j].F
+}
+func (this stOpenList) Swap(i, j int) {
+	this[i], this[j] = this[j], this[i]
+	this[i].index = i
+	this[j].index = j
+}
+func (this *stOpenList) Push(x interface{}) {
+	node := x.(*stNode)
+	node.index = len(*this)
+	*this = append(*this, node)
+}
+func (this *stOpenList) Pop() interface{} {
+	old := *this
+	node := old[len(old)-1]
+	*this = old[:len(old)-1]
+	node.index = -1
+	return node
+}
+
+/**
+ * One space-time search.
+ * With Horizon > 0 the search stops at StartT + Horizon wherever the
+ * agent is, waiting on the goal being free (windowed search). Otherwise
+ * it stops on the goal, once no other agent needs the goal any more.
+ */
+type spaceTimeQuery struct {
+	agent   int
+	grid    *core.TGrid
+	opt     *core.Opt
+	startX  int
+	startY  int
+	startT  int32
+	goalX   int
+	goalY   int
+	horizon int32
+	maxT    int32
+	dist    []float64
+}
+
+func (this *spaceTimeQuery) search(constraints Constraints) core.TimedPath {
+	path, _, _ := this.searchFocal(constraints, 1, nil)
+	return path
+}
+
+/**
+ * Focal search variant, as used by the low level of ECBS.
+ * Every expansion picks, among the open nodes whose f is within w times
+ * the smallest f, the one with the fewest conflicts reported by
+ * conflicts. With w = 1 it is a plain A* breaking ties on conflicts.
+ * @return the path, its cost and the smallest f value of the open list
+ *     when it was found (a lower bound of the optimal cost).
+ */
+func (this *spaceTimeQuery) searchFocal(constraints Constraints, w float64, conflicts *conflictTable) (core.TimedPath, float64, float64) {
+	grid := this.grid
+	if math.IsInf(this.dist[grid.NodeID(this.startX, this.startY)], 1) {
+		return nil, 0, 0
+	}
+	if constraints.VertexBlocked(this.agent, this.startX, this.startY, this.startT) {
+		return nil, 0, 0
+	}
+
+	var goalFreeAfter int32 = -1
+	if this.horizon == 0 {
+		goalFreeAfter = constraints.LastReserved(this.agent, this.goalX, this.goalY)
+	}
+
+	startNode := &stNode{x: this.startX, y: this.startY, t: this.startT}
+	startNode.F = this.dist[grid.NodeID(this.startX, this.startY)]
+	visited := map[vertexKey]*stNode{{x: int32(this.startX), y: int32(this.startY), t: this.startT}: startNode}
+	open := &stOpenList{}
+	heap.Push(open, startNode)
+	// a wait lasts a step and costs a straight one, in the cost model of
+	// the grid.
+	wait, _ := grid.StepCosts()
+
+	for open.Len() > 0 {
+		fmin := (*open)[0].F
+		var node *stNode
+		if w <= 1 && conflicts == nil {
+			node = heap.Pop(open).(*stNode)
+		} else {
+			best := 0
+			for i, candidate := range *open {
+				if candidate.F > fmin*w+1e-9 {
+					continue
+				}
+				current := (*open)[best]
+				if candidate.C < current.C || (candidate.C == current.C && candidate.F < current.F) {
+					best = i
+				}
+			}
+			node = heap.Remove(open, best).(*stNode)
+		}
+		node.Closed = true
+
+		atGoal := node.x == this.goalX && node.y == this.goalY
+		if (this.horizon > 0 && node.t >= this.startT+this.horizon) ||
+			(this.horizon == 0 && atGoal && node.t > goalFreeAfter) {
+			return backtraceTimed(node), node.G, fmin
+		}
+		if node.t >= this.maxT {
+			continue
+		}
+
+		id := grid.NodeID(node.x, node.y)
+		moves := append(core.ArrayNodeID{id}, grid.Neighbors(id, this.opt)...)
+		for _, next := range moves {
+			nx, ny := grid.NodeXY(next)
+			nt := node.t + 1
+			if constraints.VertexBlocked(this.agent, nx, ny, nt) {
+				continue
+			}
+			var cost float64
+			if next == id {
+				// waiting on the goal is free within a window.
+				if !(atGoal && this.horizon > 0) {
+					cost = wait
+				}
+			} else {
+				if constraints.EdgeBlocked(this.agent, node.x, node.y, nx, ny, node.t) {
+					continue
+				}
+				cost = grid.Cost(id, next)
+			}
+
+			key := vertexKey{x: int32(nx), y: int32(ny), t: nt}
+			ng := node.G + cost
+			nc := node.C
+			if conflicts != nil {
+				nc += conflicts.count(node.x, node.y, nx, ny, node.t)
+			}
+			neighbor := visited[key]
+			if neighbor == nil {
+				neighbor = &stNode{x: nx, y: ny, t: nt, G: ng, F: ng + this.dist[next], C: nc, Parent: node}
+				visited[key] = neighbor
+				heap.Push(open, neighbor)
+			} else if !neighbor.Closed && (ng < neighbor.G || (ng == neighbor.G && nc < neighbor.C)) {
+				neighbor.F += ng - neighbor.G
+				neighbor.G = ng
+				neighbor.C = nc
+				neighbor.Parent = node
+				heap.Fix(open, neighbor.index)
+			}
+		}
+	}
+
+	// fail to find the path
+	return nil, 0, 0
+}
+
+func backtraceTimed(node *stNode) core.TimedPath {
+	var path = core.TimedPath{}
+	for ; node != nil; node = node.Parent {
+		path = append(path, core.TimedCoordinate{X: int32(node.x), Y: int32(node.y), T: float64(node.t)})
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
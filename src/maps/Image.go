@@ -0,0 +1,291 @@
+package maps
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"go-PathFinding/core"
+)
+
+func init() {
+	Readers[".png"] = ReadImage
+	Readers[".pgm"] = ReadImage
+}
+
+/**
+ * Converts images into grids, one cell per CellSize x CellSize pixels.
+ * A cell is blocked when one of its pixels is darker than Threshold.
+ * When Cost is set it decides instead: it returns the cost of a pixel,
+ * 0 or less for a blocked one, and a cell takes the highest cost of its
+ * pixels.
+ * For ROS occupancy maps (free 254, unknown 205, occupied 0) a Threshold
+ * of 206 blocks the unknown cells too.
+ */
+type TImageImporter struct {
+	Threshold uint8
+	Cost      func(c color.Color) float64
+	CellSize  int
+}
+
+/**
+ * @constructor
+ */
+func NewImageImporter() *TImageImporter {
+	return &TImageImporter{
+		Threshold: 128,
+		CellSize:  1,
+	}
+}
+
+/**
+ * Cost from the gray level: white costs 1, darker pixels cost more, up to
+ * maxCost at threshold, and pixels darker than threshold are blocked.
+ */
+func GrayCost(threshold uint8, maxCost float64) func(c color.Color) float64 {
+	return func(c color.Color) float64 {
+		level := color.GrayModel.Convert(c).(color.Gray).Y
+		if level < threshold {
+			return 0
+		}
+		if threshold == 255 {
+			return 1
+		}
+		return 1 + (maxCost-1)*float64(255-level)/float64(255-threshold)
+	}
+}
+
+/**
+ * Cost from a palette of exact colours, as painted in an image editor.
+ * Colours missing from the palette cost other.
+ */
+func PaletteCost(palette map[color.NRGBA]float64, other float64) func(c color.Color) float64 {
+	return func(c color.Color) float64 {
+		if cost, ok := palette[color.NRGBAModel.Convert(c).(color.NRGBA)]; ok {
+			return cost
+		}
+		return other
+	}
+}
+
+/**
+ * Read an image in any format registered with the image package (PNG and
+ * PGM here) with the default importer.
+ */
+func ReadImage(r io.Reader) (*core.TGrid, error) {
+	return NewImageImporter().Read(r)
+}
+
+func (this *TImageImporter) Read(r io.Reader) (*core.TGrid, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("maps: %v", err)
+	}
+	return this.Grid(img)
+}
+
+/**
+ * Convert a decoded image. Pixels of the last row and column of cells
+ * beyond the image bounds are ignored.
+ */
+func (this *TImageImporter) Grid(img image.Image) (*core.TGrid, error) {
+	size := this.CellSize
+	if size < 1 {
+		size = 1
+	}
+	bounds := img.Bounds()
+	width, height := (bounds.Dx()+size-1)/size, (bounds.Dy()+size-1)/size
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("maps: empty image")
+	}
+
+	var costs = make([]float64, width*height)
+	for i := range costs {
+		costs[i] = 1
+	}
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			cost := 1.0
+			if this.Cost != nil {
+				cost = this.Cost(img.At(px, py))
+				if math.IsNaN(cost) || math.IsInf(cost, 0) {
+					return nil, fmt.Errorf("maps: pixel (%d, %d) costs %v", px, py, cost)
+				}
+			} else if color.GrayModel.Convert(img.At(px, py)).(color.Gray).Y < this.Threshold {
+				cost = 0
+			}
+			i := (py-bounds.Min.Y)/size*width + (px-bounds.Min.X)/size
+			if costs[i] > 0 && (cost <= 0 || cost > costs[i]) {
+				costs[i] = cost
+			}
+		}
+	}
+
+	var matrix = make(core.DoubleInt32, height)
+	for y := range matrix {
+		matrix[y] = make(core.ArrayInt32, width)
+		for x := range matrix[y] {
+			if costs[y*width+x] <= 0 {
+				matrix[y][x] = 1
+			}
+		}
+	}
+	grid := core.Grid(width, height, matrix)
+	for i, cost := range costs {
+		if cost > 0 && cost != 1 {
+			grid.SetCostAt(i%width, i/width, cost)
+		}
+	}
+	return grid, nil
+}
+
+/**
+ * Draws grids, search sets and paths, Scale pixels per cell side.
+ * Opened cells are those opened but never closed. Paths take the colours
+ * of Paths in turn; their cells are joined by lines, so that compressed
+ * and any-angle paths are drawn as well.
+ */
+type TImageRenderer struct {
+	Scale    int
+	Walkable color.Color
+	Blocked  color.Color
+	Opened   color.Color
+	Closed   color.Color
+	Paths    []color.Color
+}
+
+/**
+ * @constructor
+ */
+func NewImageRenderer() *TImageRenderer {
+	return &TImageRenderer{
+		Scale:    8,
+		Walkable: color.NRGBA{0xff, 0xff, 0xff, 0xff},
+		Blocked:  color.NRGBA{0x40, 0x40, 0x40, 0xff},
+		Opened:   color.NRGBA{0x98, 0xfb, 0x98, 0xff},
+		Closed:   color.NRGBA{0xaf, 0xee, 0xee, 0xff},
+		Paths: []color.Color{
+			color.NRGBA{0xe0, 0x20, 0x20, 0xff},
+			color.NRGBA{0x20, 0x40, 0xe0, 0xff},
+			color.NRGBA{0xe0, 0xa0, 0x00, 0xff},
+			color.NRGBA{0xa0, 0x20, 0xc0, 0xff},
+		},
+	}
+}
+
+/**
+ * Draw the grid, then the search sets if sets is not nil, then the paths.
+ */
+func (this *TImageRenderer) Render(grid *core.TGrid, sets *core.TSearchSets, paths ...core.DoubleInt32) *image.RGBA {
+	scale := this.Scale
+	if scale < 1 {
+		scale = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, grid.Width()*scale, grid.Height()*scale))
+	fill := func(x, y int, c color.Color) {
+		draw.Draw(img, image.Rect(x*scale, y*scale, (x+1)*scale, (y+1)*scale), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			if grid.IsWalkableAt(x, y) {
+				fill(x, y, this.Walkable)
+			} else {
+				fill(x, y, this.Blocked)
+			}
+		}
+	}
+
+	if sets != nil {
+		var closed = map[core.NodeID]bool{}
+		for _, id := range sets.Closed {
+			closed[id] = true
+			x, y := grid.NodeXY(id)
+			fill(x, y, this.Closed)
+		}
+		for _, id := range sets.Opened {
+			if !closed[id] {
+				x, y := grid.NodeXY(id)
+				fill(x, y, this.Opened)
+			}
+		}
+	}
+
+	for i, path := range paths {
+		var c color.Color = color.Black
+		if len(this.Paths) > 0 {
+			c = this.Paths[i%len(this.Paths)]
+		}
+		this.drawPath(img, path, scale, c)
+	}
+	return img
+}
+
+/**
+ * Render and encode as PNG.
+ */
+func (this *TImageRenderer) WritePNG(w io.Writer, grid *core.TGrid, sets *core.TSearchSets, paths ...core.DoubleInt32) error {
+	return png.Encode(w, this.Render(grid, sets, paths...))
+}
+
+// drawPath joins the cell centres of the path with lines about a quarter
+// of a cell thick and marks its cells with squares half a cell wide.
+func (this *TImageRenderer) drawPath(img *image.RGBA, path core.DoubleInt32, scale int, c color.Color) {
+	pen := image.NewUniform(c)
+	dot := func(px, py, size int) {
+		r := image.Rect(px-size/2, py-size/2, px-size/2+size, py-size/2+size)
+		draw.Draw(img, r, pen, image.Point{}, draw.Src)
+	}
+	thickness := scale / 4
+	if thickness < 1 {
+		thickness = 1
+	}
+	centre := func(p core.ArrayInt32) (int, int) {
+		return int(p[0])*scale + scale/2, int(p[1])*scale + scale/2
+	}
+
+	for i := 1; i < len(path); i++ {
+		x0, y0 := centre(path[i-1])
+		x1, y1 := centre(path[i])
+		// Bresenham from one centre to the next.
+		dx, dy := x1-x0, y1-y0
+		sx, sy := 1, 1
+		if dx < 0 {
+			dx, sx = -dx, -1
+		}
+		if dy < 0 {
+			dy, sy = -dy, -1
+		}
+		err := dx - dy
+		for {
+			dot(x0, y0, thickness)
+			if x0 == x1 && y0 == y1 {
+				break
+			}
+			e2 := 2 * err
+			if e2 > -dy {
+				err -= dy
+				x0 += sx
+			}
+			if e2 < dx {
+				err += dx
+				y0 += sy
+			}
+		}
+	}
+	size := scale / 2
+	if size < 1 {
+		size = 1
+	}
+	for _, p := range path {
+		x, y := centre(p)
+		dot(x, y, size)
+	}
+}
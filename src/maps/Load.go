@@ -0,0 +1,58 @@
+package maps
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-PathFinding/core"
+)
+
+/**
+ * Reads a map format.
+ */
+type Reader func(r io.Reader) (*core.TGrid, error)
+
+/**
+ * Readers by lower-case file extension, used by Load.
+ */
+var Readers = map[string]Reader{
+	".txt": ReadText,
+}
+
+/**
+ * Loaders by lower-case file extension, for the formats that need more
+ * than the map file (e.g. Tiled external tilesets). They take precedence
+ * over Readers.
+ */
+var Loaders = map[string]func(filename string) (*core.TGrid, error){}
+
+/**
+ * Load a map file, its format chosen by extension.
+ */
+func Load(filename string) (*core.TGrid, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if loader, ok := Loaders[ext]; ok {
+		return loader(filename)
+	}
+	reader, ok := Readers[ext]
+	if !ok {
+		return nil, fmt.Errorf("maps: unknown map format %q", ext)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	grid, err := reader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return grid, nil
+}
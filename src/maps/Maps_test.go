@@ -0,0 +1,438 @@
+package maps
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-PathFinding/core"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+const textMap = `; a comment
+....#
+.##.#
+....
+`
+
+func TestText(t *testing.T) {
+	grid, err := ReadText(strings.NewReader(textMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grid.Width() != 5 || grid.Height() != 3 || grid.IsWalkableAt(1, 1) || !grid.IsWalkableAt(3, 1) || grid.IsWalkableAt(4, 2) {
+		t.Fatalf("unexpected %dx%d grid", grid.Width(), grid.Height())
+	}
+
+	var out bytes.Buffer
+	if err := WriteText(&out, grid); err != nil {
+		t.Fatal(err)
+	}
+	if want := "....#\n.##.#\n....#\n"; out.String() != want {
+		t.Fatalf("wrote %q, expected %q", out.String(), want)
+	}
+
+	out.Reset()
+	path := core.DoubleInt32{{0, 2}, {0, 1}, {0, 0}, {1, 0}}
+	if err := WriteOverlay(&out, grid, path); err != nil {
+		t.Fatal(err)
+	}
+	akLog.FmtPrintln("\n" + out.String())
+	if want := "*G..#\n*##.#\nS...#\n"; out.String() != want {
+		t.Fatalf("overlay %q, expected %q", out.String(), want)
+	}
+
+	if _, err := ReadText(strings.NewReader("..x\n")); err == nil {
+		t.Fatalf("unexpected character accepted")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "maps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "town.txt")
+	if err := ioutil.WriteFile(filename, []byte(textMap), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if grid, err := Load(filename); err != nil || grid.Width() != 5 {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, err := Load(filepath.Join(dir, "town.unknown")); err == nil {
+		t.Fatalf("unknown extension accepted")
+	}
+
+	// .json files are loaded only when they are Tiled maps.
+	for content, tiled := range map[string]bool{
+		`{"type": "map", "orientation": "orthogonal", "width": 2, "height": 1, "tilewidth": 1, "tileheight": 1, "layers": []}`: true,
+		`{"orientation": "orthogonal", "width": 2, "height": 1, "tilewidth": 1, "tileheight": 1, "layers": []}`:                true,
+		`{"finders": ["astar"], "results": []}`: false,
+	} {
+		filename = filepath.Join(dir, "town.json")
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		grid, err := Load(filename)
+		if tiled && (err != nil || grid.Width() != 2) {
+			t.Fatalf("Tiled map %s: %v", content, err)
+		}
+		if !tiled && (err == nil || !strings.Contains(err.Error(), "is not a Tiled map")) {
+			t.Fatalf("%s loaded as a Tiled map: %v", content, err)
+		}
+	}
+}
+
+const movingAIMap = `type octile
+height 4
+width 5
+map
+.....
+.@@@.
+.TG..
+....W
+`
+
+const movingAIScen = "version 1\n" +
+	"0\tarena.map\t5\t4\t0\t0\t4\t2\t6.00000000\n" +
+	"1\tarena.map\t5\t4\t2\t2\t0\t0\t6.00000000\n"
+
+func TestMovingAI(t *testing.T) {
+	grid, err := ReadMovingAIMap(strings.NewReader(movingAIMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grid.Width() != 5 || grid.Height() != 4 || grid.IsWalkableAt(1, 2) || !grid.IsWalkableAt(2, 2) || grid.IsWalkableAt(4, 3) {
+		t.Fatalf("unexpected %dx%d grid", grid.Width(), grid.Height())
+	}
+	var out bytes.Buffer
+	if err := WriteMovingAIMap(&out, grid); err != nil {
+		t.Fatal(err)
+	}
+	if want := "type octile\nheight 4\nwidth 5\nmap\n.....\n.@@@.\n.@...\n....@\n"; out.String() != want {
+		t.Fatalf("wrote %q, expected %q", out.String(), want)
+	}
+	if _, err := ReadMovingAIMap(strings.NewReader("type octile\nheight 2\nwidth 2\nmap\n..\n")); err == nil {
+		t.Fatalf("missing row accepted")
+	}
+
+	scenarios, err := ReadScenarios(strings.NewReader(movingAIScen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	akLog.FmtPrintln("scenarios: ", scenarios)
+	if len(scenarios) != 2 || scenarios[1].StartX != 2 || scenarios[1].Map != "arena.map" || scenarios[0].Optimal != 6 {
+		t.Fatalf("unexpected scenarios %+v", scenarios)
+	}
+	out.Reset()
+	if err := WriteScenarios(&out, scenarios); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != movingAIScen {
+		t.Fatalf("wrote %q, expected %q", out.String(), movingAIScen)
+	}
+}
+
+// encode gids the way Tiled does for base64 layers.
+func tiledBase64(t *testing.T, gids []uint32, compression string) string {
+	var raw bytes.Buffer
+	for _, gid := range gids {
+		binary.Write(&raw, binary.LittleEndian, gid)
+	}
+	var out bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case "zlib":
+		w = zlib.NewWriter(&out)
+	case "gzip":
+		w = gzip.NewWriter(&out)
+	default:
+		return base64.StdEncoding.EncodeToString(raw.Bytes())
+	}
+	w.Write(raw.Bytes())
+	w.Close()
+	return base64.StdEncoding.EncodeToString(out.Bytes())
+}
+
+// 6x4 map of 16px tiles: tile 2 is a wall, tile 3 mud (cost 3), tile 4 a
+// wall through a rule on its id; a flipped wall sits at (5, 0).
+var tiledGround = []uint32{
+	1, 1, 1, 1, 1, 2 | 0x80000000,
+	1, 2, 3, 3, 1, 1,
+	1, 1, 1, 1, 4, 1,
+	1, 1, 1, 1, 1, 1,
+}
+
+const tiledTSX = `<?xml version="1.0" encoding="UTF-8"?>
+<tileset name="terrain" tilewidth="16" tileheight="16" tilecount="4">
+ <tile id="1"><properties><property name="walkable" type="bool" value="false"/></properties></tile>
+ <tile id="2"><properties><property name="cost" type="float" value="3"/></properties></tile>
+</tileset>`
+
+func checkTiledGrid(t *testing.T, grid *core.TGrid) {
+	var out bytes.Buffer
+	WriteText(&out, grid)
+	akLog.FmtPrintln("\n" + out.String())
+	// walls from the tiles, the rectangle (0..2, 3), the polygon covering
+	// (3, 3) and the ellipse centred on (4, 0).
+	if want := "....##\n.#....\n....#.\n####.."; strings.TrimSpace(out.String()) != want {
+		t.Fatalf("grid\n%s\nexpected\n%s", out.String(), want)
+	}
+	if grid.GetCostAt(2, 1) != 3 || grid.GetCostAt(0, 0) != 1 {
+		t.Fatalf("costs %v %v", grid.GetCostAt(2, 1), grid.GetCostAt(0, 0))
+	}
+}
+
+func TestTiled(t *testing.T) {
+	tiledCSV := func(gids []uint32) string {
+		return strings.Trim(strings.Replace(fmt.Sprint(gids), " ", ",", -1), "[]")
+	}
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.4" orientation="orthogonal" width="6" height="4" tilewidth="16" tileheight="16" infinite="0">
+ <tileset firstgid="1" source="terrain.tsx"/>
+ <group name="ground">
+  <layer name="top" width="6" height="4"><data encoding="csv">` +
+		tiledCSV(append(append([]uint32{}, tiledGround[:12]...), make([]uint32, 12)...)) + `</data></layer>
+ </group>
+ <layer name="all" width="6" height="4"><data encoding="base64" compression="zlib">` +
+		tiledBase64(t, append(make([]uint32, 12), tiledGround[12:]...), "zlib") + `</data></layer>
+ <objectgroup name="walls">
+  <object id="1" x="0" y="48" width="48" height="16"/>
+  <object id="2" x="48" y="48"><polygon points="0,0 16,0 16,16 0,16"/></object>
+  <object id="3" x="66" y="2" width="12" height="12"><ellipse/></object>
+  <object id="4" x="80" y="48"><point/></object>
+ </objectgroup>
+</map>`
+
+	importer := NewTiledImporter()
+	importer.Rules = append(importer.Rules, TTileRule{GIDs: []uint32{4}, Blocked: true})
+	importer.Open = func(source string) (io.ReadCloser, error) {
+		if source != "terrain.tsx" {
+			t.Fatalf("opened %s", source)
+		}
+		return ioutil.NopCloser(strings.NewReader(tiledTSX)), nil
+	}
+	grid, err := importer.ReadTMX(strings.NewReader(tmx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTiledGrid(t, grid)
+
+	tmj := `{"orientation": "orthogonal", "width": 6, "height": 4, "tilewidth": 16, "tileheight": 16, "infinite": false,
+ "tilesets": [{"firstgid": 1, "tiles": [
+   {"id": 1, "properties": [{"name": "blocked", "type": "bool", "value": true}]},
+   {"id": 2, "properties": [{"name": "cost", "type": "float", "value": 3}]}]}],
+ "layers": [
+  {"type": "tilelayer", "name": "ground", "width": 6, "height": 4, "encoding": "base64", "compression": "gzip",
+   "data": "` + tiledBase64(t, tiledGround, "gzip") + `"},
+  {"type": "group", "name": "objects", "layers": [
+   {"type": "objectgroup", "name": "walls", "objects": [
+    {"x": 0, "y": 48, "width": 48, "height": 16},
+    {"x": 48, "y": 48, "polygon": [{"x": 0, "y": 0}, {"x": 16, "y": 0}, {"x": 16, "y": 16}, {"x": 0, "y": 16}]},
+    {"x": 66, "y": 2, "width": 12, "height": 12, "ellipse": true}]},
+   {"type": "objectgroup", "name": "decals", "objects": [{"x": 0, "y": 0, "width": 96, "height": 64}]}]}]}`
+	importer.ObstacleLayers = []string{"walls"}
+	if grid, err = importer.ReadJSON(strings.NewReader(tmj)); err != nil {
+		t.Fatal(err)
+	}
+	checkTiledGrid(t, grid)
+
+	// a rectangle rotated by 90 degrees around its top-left corner.
+	rotated := `{"orientation": "orthogonal", "width": 3, "height": 3, "tilewidth": 10, "tileheight": 10,
+ "layers": [{"type": "objectgroup", "name": "walls", "objects": [{"x": 30, "y": 0, "width": 30, "height": 10, "rotation": 90}]}]}`
+	if grid, err = NewTiledImporter().ReadJSON(strings.NewReader(rotated)); err != nil {
+		t.Fatal(err)
+	}
+	if grid.IsWalkableAt(2, 0) || grid.IsWalkableAt(2, 2) || !grid.IsWalkableAt(1, 0) {
+		t.Fatalf("rotated rectangle not rasterised on the last column")
+	}
+
+	// an ellipse rotated the same way: its bounding box corners stay free.
+	rotated = `{"orientation": "orthogonal", "width": 12, "height": 12, "tilewidth": 5, "tileheight": 5,
+ "layers": [{"type": "objectgroup", "name": "walls", "objects": [{"x": 60, "y": 0, "width": 60, "height": 20, "rotation": 90, "ellipse": true}]}]}`
+	if grid, err = NewTiledImporter().ReadJSON(strings.NewReader(rotated)); err != nil {
+		t.Fatal(err)
+	}
+	if grid.IsWalkableAt(9, 6) || !grid.IsWalkableAt(8, 0) || !grid.IsWalkableAt(11, 11) || !grid.IsWalkableAt(7, 6) {
+		t.Fatalf("rotated ellipse not rasterised as an ellipse")
+	}
+
+	for _, cost := range []string{"NaN", "+Inf", "0"} {
+		bad := `{"orientation": "orthogonal", "width": 1, "height": 1, "tilewidth": 1, "tileheight": 1,
+ "tilesets": [{"firstgid": 1, "tiles": [{"id": 0, "properties": [{"name": "cost", "type": "string", "value": "` + cost + `"}]}]}],
+ "layers": [{"type": "tilelayer", "name": "ground", "width": 1, "height": 1, "data": [1]}]}`
+		if _, err := NewTiledImporter().ReadJSON(strings.NewReader(bad)); err == nil {
+			t.Fatalf("cost %s accepted", cost)
+		}
+	}
+
+	if _, err := NewTiledImporter().ReadJSON(strings.NewReader(`{"orientation": "isometric", "width": 1, "height": 1, "tilewidth": 1, "tileheight": 1}`)); err == nil {
+		t.Fatalf("isometric map accepted")
+	}
+	short := `<map orientation="orthogonal" width="2" height="2" tilewidth="1" tileheight="1"><layer name="l" width="2" height="2"><data encoding="csv">1,1,1</data></layer></map>`
+	if _, err := NewTiledImporter().ReadTMX(strings.NewReader(short)); err == nil {
+		t.Fatalf("layer of 3 tiles accepted on a 2x2 map")
+	}
+}
+
+func TestImage(t *testing.T) {
+	// 4x2 graymap: a wall, an unknown ROS cell and a muddy one.
+	pgm := "P2\n# occupancy\n4 2\n255\n254 0 205 254\n254 254 128 254\n"
+	grid, err := ReadImage(strings.NewReader(pgm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grid.Width() != 4 || grid.IsWalkableAt(1, 0) || !grid.IsWalkableAt(2, 0) || !grid.IsWalkableAt(2, 1) {
+		t.Fatalf("threshold not applied")
+	}
+	importer := NewImageImporter()
+	importer.Cost = GrayCost(200, 5)
+	raw := append([]byte("P5 4 2 255\n"), 254, 0, 205, 254, 254, 254, 128, 254)
+	if grid, err = importer.Read(bytes.NewReader(raw)); err != nil {
+		t.Fatal(err)
+	}
+	if grid.IsWalkableAt(2, 1) || grid.GetCostAt(0, 0) != 1+4.0/55 || grid.GetCostAt(2, 0) != 1+4*50.0/55 {
+		t.Fatalf("gray costs %v %v", grid.GetCostAt(0, 0), grid.GetCostAt(2, 0))
+	}
+	if _, err := ReadImage(strings.NewReader("P5 4 2 255\n\x00")); err == nil {
+		t.Fatalf("truncated graymap accepted")
+	}
+
+	// rendered at 3 pixels per cell and read back 3 pixels per cell.
+	grid = core.Grid(4, 3, core.DoubleInt32{{0, 0, 0, 0}, {1, 1, 1, 0}, {0, 0, 0, 0}})
+	sets := &core.TSearchSets{}
+	sets.OnOpen(grid.NodeID(1, 0), 1, 0)
+	sets.OnOpen(grid.NodeID(0, 2), 2, 0)
+	sets.OnClose(grid.NodeID(1, 0), 1, 0)
+	renderer := NewImageRenderer()
+	renderer.Scale = 3
+	img := renderer.Render(grid, sets, core.DoubleInt32{{0, 0}, {3, 0}, {3, 2}, {0, 2}})
+	// the path runs through the middle pixel of its cells.
+	for _, check := range []struct {
+		x, y int
+		c    color.Color
+	}{
+		{4, 1, renderer.Paths[0]}, {1, 7, renderer.Paths[0]}, {4, 0, renderer.Closed},
+		{0, 6, renderer.Opened}, {1, 4, renderer.Blocked}, {7, 0, renderer.Walkable},
+	} {
+		if img.At(check.x, check.y) != color.RGBAModel.Convert(check.c) {
+			t.Fatalf("pixel (%d, %d) %v, expected %v", check.x, check.y, img.At(check.x, check.y), check.c)
+		}
+	}
+	var out bytes.Buffer
+	if err := renderer.WritePNG(&out, grid, nil); err != nil {
+		t.Fatal(err)
+	}
+	importer = NewImageImporter()
+	importer.CellSize = 3
+	back, err := importer.Read(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var text bytes.Buffer
+	WriteText(&text, back)
+	if text.String() != "....\n###.\n....\n" {
+		t.Fatalf("read back\n%s", text.String())
+	}
+
+	mud := color.NRGBA{0x80, 0x60, 0x20, 0xff}
+	palette := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	palette.Set(0, 0, mud)
+	palette.Set(1, 0, color.Black)
+	importer = NewImageImporter()
+	importer.Cost = PaletteCost(map[color.NRGBA]float64{mud: 3}, 0)
+	if grid, err = importer.Grid(palette); err != nil || grid.GetCostAt(0, 0) != 3 || grid.IsWalkableAt(1, 0) {
+		t.Fatalf("palette costs: %v", err)
+	}
+	for _, cost := range []float64{math.NaN(), math.Inf(1)} {
+		importer.Cost = PaletteCost(map[color.NRGBA]float64{mud: cost}, 0)
+		if _, err := importer.Grid(palette); err == nil {
+			t.Fatalf("cost %v accepted", cost)
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	grid := core.Grid(4, 3, core.DoubleInt32{{0, 0, 0, 0}, {1, 1, 1, 0}, {0, 0, 0, 0}})
+	start, goal := grid.NodeID(0, 0), grid.NodeID(0, 2)
+	trace := &core.TSearchTrace{}
+	path := core.ArrayNodeID{start}
+	trace.OnOpen(start, 0, 2)
+	for i := 0; i < 8; i++ {
+		x, y := grid.NodeXY(path[len(path)-1])
+		trace.OnClose(path[len(path)-1], float64(i), 2)
+		switch {
+		case y == 0 && x < 3:
+			x++
+		case x == 3 && y < 2:
+			y++
+		default:
+			x--
+		}
+		path = append(path, grid.NodeID(x, y))
+		trace.OnOpen(path[len(path)-1], float64(i+1), 2)
+	}
+	// a node closed twice keeps its first rank.
+	trace.OnClose(start, 0, 2)
+	trace.OnClose(goal, 8, 0)
+	if order := trace.ExpansionOrder(); len(order) != 9 || order[start] != 0 || order[goal] != 8 {
+		t.Fatalf("expansion order %v", order)
+	}
+
+	renderer := NewSVGRenderer()
+	renderer.Levels = 9
+	renderer.Titles = true
+	var out bytes.Buffer
+	if err := renderer.WriteSVG(&out, grid, start, goal, trace, path); err != nil {
+		t.Fatal(err)
+	}
+	svg := out.String()
+	akLog.FmtPrintln("\n" + svg)
+	var doc struct {
+		Width  int `xml:"width,attr"`
+		Groups []struct {
+			Class string `xml:"class,attr"`
+			Rects []struct {
+				X     int    `xml:"x,attr"`
+				Width int    `xml:"width,attr"`
+				Class string `xml:"class,attr"`
+			} `xml:"rect"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Width != 40 || len(doc.Groups) != 2 || len(doc.Groups[0].Rects) != 9 || doc.Groups[0].Rects[5].Class != "h8" {
+		t.Fatalf("unexpected heatmap")
+	}
+	if blocked := doc.Groups[1].Rects; len(blocked) != 1 || blocked[0].Width != 3 {
+		t.Fatalf("blocked cells not drawn as one run")
+	}
+	if !strings.Contains(svg, `points="0.5,0.5 1.5,0.5 2.5,0.5 3.5,0.5 3.5,1.5 3.5,2.5 2.5,2.5 1.5,2.5 0.5,2.5"`) ||
+		!strings.Contains(svg, "<title>(3, 2) #5 g 5 h 2</title>") {
+		t.Fatalf("path or tooltip missing")
+	}
+
+	// the same search gives the same file.
+	var again bytes.Buffer
+	renderer.WriteSVG(&again, grid, start, goal, trace, path)
+	if again.String() != svg {
+		t.Fatalf("output not deterministic")
+	}
+}
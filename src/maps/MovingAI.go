@@ -0,0 +1,187 @@
+package maps
+
+/*
+	by stefan 2572915286@qq.com
+	MovingAI benchmark formats, https://movingai.com/benchmarks/formats.html
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go-PathFinding/core"
+)
+
+func init() {
+	Readers[".map"] = ReadMovingAIMap
+}
+
+/**
+ * Read a MovingAI .map grid. '.', 'G' and 'S' (swamp) are walkable;
+ * '@', 'O', 'T' (trees) and 'W' (water) are blocked.
+ */
+func ReadMovingAIMap(r io.Reader) (*core.TGrid, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	var width, height = -1, -1
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "map" {
+			break
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("maps: invalid header line %q", scanner.Text())
+		}
+		switch fields[0] {
+		case "type":
+			// octile is the only type in use.
+		case "width", "height":
+			value, err := strconv.Atoi(fields[1])
+			if err != nil || value <= 0 {
+				return nil, fmt.Errorf("maps: invalid %s %q", fields[0], fields[1])
+			}
+			if fields[0] == "width" {
+				width = value
+			} else {
+				height = value
+			}
+		default:
+			return nil, fmt.Errorf("maps: unknown header %q", fields[0])
+		}
+	}
+	if width < 0 || height < 0 {
+		return nil, fmt.Errorf("maps: missing width or height")
+	}
+
+	var matrix = make(core.DoubleInt32, height)
+	for y := 0; y < height; y++ {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("maps: %d rows, expected %d", y, height)
+		}
+		row := strings.TrimRight(scanner.Text(), "\r")
+		if len(row) != width {
+			return nil, fmt.Errorf("maps: row %d has %d cells, expected %d", y, len(row), width)
+		}
+		matrix[y] = make(core.ArrayInt32, width)
+		for x := 0; x < width; x++ {
+			switch row[x] {
+			case '.', 'G', 'S':
+			case '@', 'O', 'T', 'W':
+				matrix[y][x] = 1
+			default:
+				return nil, fmt.Errorf("maps: row %d: unexpected %q", y, row[x])
+			}
+		}
+	}
+	return core.Grid(width, height, matrix), nil
+}
+
+/**
+ * Write a grid as a MovingAI .map, '.' walkable and '@' blocked.
+ */
+func WriteMovingAIMap(w io.Writer, grid *core.TGrid) error {
+	if _, err := fmt.Fprintf(w, "type octile\nheight %d\nwidth %d\nmap\n", grid.Height(), grid.Width()); err != nil {
+		return err
+	}
+	return writeRows(w, grid, func(x, y int) byte {
+		if grid.IsWalkableAt(x, y) {
+			return '.'
+		}
+		return '@'
+	})
+}
+
+/**
+ * One problem of a MovingAI .scen file. Optimal is the length of the
+ * shortest path moving in 8 directions without cutting corners, diagonal
+ * steps costing sqrt(2).
+ */
+type TScenario struct {
+	Bucket    int
+	Map       string
+	MapWidth  int
+	MapHeight int
+	StartX    int
+	StartY    int
+	GoalX     int
+	GoalY     int
+	Optimal   float64
+}
+
+/**
+ * Read the scenarios of a MovingAI .scen file (version 1).
+ */
+func ReadScenarios(r io.Reader) ([]TScenario, error) {
+	scanner := bufio.NewScanner(r)
+	var scenarios []TScenario
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if line == 1 && strings.HasPrefix(text, "version") {
+			if fields := strings.Fields(text); len(fields) != 2 || (fields[1] != "1" && fields[1] != "1.0") {
+				return nil, fmt.Errorf("maps: unsupported scenario %s", text)
+			}
+			continue
+		}
+		// the map name may contain spaces, the other fields do not.
+		fields := strings.Split(text, "\t")
+		if len(fields) != 9 {
+			fields = strings.Fields(text)
+		}
+		if len(fields) != 9 {
+			return nil, fmt.Errorf("maps: line %d: %d fields, expected 9", line, len(fields))
+		}
+		var ints [8]int
+		for _, i := range []int{0, 2, 3, 4, 5, 6, 7} {
+			value, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("maps: line %d: %v", line, err)
+			}
+			ints[i] = value
+		}
+		optimal, err := strconv.ParseFloat(fields[8], 64)
+		if err != nil {
+			return nil, fmt.Errorf("maps: line %d: %v", line, err)
+		}
+		s := TScenario{
+			Bucket:    ints[0],
+			Map:       fields[1],
+			MapWidth:  ints[2],
+			MapHeight: ints[3],
+			StartX:    ints[4],
+			StartY:    ints[5],
+			GoalX:     ints[6],
+			GoalY:     ints[7],
+			Optimal:   optimal,
+		}
+		scenarios = append(scenarios, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return scenarios, nil
+}
+
+/**
+ * Write scenarios as a MovingAI .scen file (version 1).
+ */
+func WriteScenarios(w io.Writer, scenarios []TScenario) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "version 1")
+	for _, s := range scenarios {
+		fmt.Fprintf(bw, "%d\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%.8f\n",
+			s.Bucket, s.Map, s.MapWidth, s.MapHeight, s.StartX, s.StartY, s.GoalX, s.GoalY, s.Optimal)
+	}
+	return bw.Flush()
+}
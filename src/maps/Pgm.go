@@ -0,0 +1,131 @@
+package maps
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+)
+
+func init() {
+	// plain (P2) and raw (P5) graymaps, e.g. ROS occupancy maps, decoded
+	// by image.Decode like PNG.
+	image.RegisterFormat("pgm", "P2", decodePGM, decodePGMConfig)
+	image.RegisterFormat("pgm", "P5", decodePGM, decodePGMConfig)
+}
+
+type pgmHeader struct {
+	plain  bool
+	width  int
+	height int
+	maxval int
+}
+
+// pgmToken reads the next header field, skipping whitespace and comments.
+func pgmToken(r *bufio.Reader) (string, error) {
+	var token []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(token) > 0 {
+				return string(token), nil
+			}
+			return "", err
+		}
+		switch {
+		case c == '#' && len(token) == 0:
+			if _, err := r.ReadString('\n'); err != nil {
+				return "", err
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if len(token) > 0 {
+				return string(token), nil
+			}
+		default:
+			token = append(token, c)
+		}
+	}
+}
+
+func readPGMHeader(r *bufio.Reader) (*pgmHeader, error) {
+	magic, err := pgmToken(r)
+	if err != nil {
+		return nil, err
+	}
+	if magic != "P2" && magic != "P5" {
+		return nil, fmt.Errorf("pgm: not a graymap")
+	}
+	header := &pgmHeader{plain: magic == "P2"}
+	for _, field := range []*int{&header.width, &header.height, &header.maxval} {
+		token, err := pgmToken(r)
+		if err != nil {
+			return nil, fmt.Errorf("pgm: truncated header")
+		}
+		if *field, err = strconv.Atoi(token); err != nil || *field <= 0 {
+			return nil, fmt.Errorf("pgm: invalid header field %q", token)
+		}
+	}
+	if header.maxval > 65535 {
+		return nil, fmt.Errorf("pgm: invalid maximum gray value %d", header.maxval)
+	}
+	return header, nil
+}
+
+func decodePGMConfig(r io.Reader) (image.Config, error) {
+	header, err := readPGMHeader(bufio.NewReader(r))
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: color.Gray16Model, Width: header.width, Height: header.height}, nil
+}
+
+/**
+ * Decode a PGM graymap, its gray levels scaled to 16 bits.
+ */
+func decodePGM(r io.Reader) (image.Image, error) {
+	br := bufio.NewReader(r)
+	header, err := readPGMHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewGray16(image.Rect(0, 0, header.width, header.height))
+	wide := header.maxval > 255
+	for y := 0; y < header.height; y++ {
+		for x := 0; x < header.width; x++ {
+			var value int
+			switch {
+			case header.plain:
+				token, err := pgmToken(br)
+				if err != nil {
+					return nil, fmt.Errorf("pgm: truncated data")
+				}
+				if value, err = strconv.Atoi(token); err != nil {
+					return nil, fmt.Errorf("pgm: invalid gray value %q", token)
+				}
+			case wide:
+				var buf [2]byte
+				if _, err := io.ReadFull(br, buf[:]); err != nil {
+					return nil, fmt.Errorf("pgm: truncated data")
+				}
+				value = int(buf[0])<<8 | int(buf[1])
+			default:
+				c, err := br.ReadByte()
+				if err != nil {
+					return nil, fmt.Errorf("pgm: truncated data")
+				}
+				value = int(c)
+			}
+			if value < 0 || value > header.maxval {
+				return nil, fmt.Errorf("pgm: gray value %d above %d", value, header.maxval)
+			}
+			img.SetGray16(x, y, color.Gray16{Y: uint16(value * 0xffff / header.maxval)})
+		}
+	}
+	return img, nil
+}
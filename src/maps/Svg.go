@@ -0,0 +1,177 @@
+package maps
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"go-PathFinding/core"
+)
+
+/**
+ * What the heatmap of the SVG renderer shows for the expanded cells.
+ */
+type THeatmap int
+
+const (
+	HeatmapNone  THeatmap = iota
+	HeatmapOrder          // rank of expansion
+	HeatmapG              // g value when expanded
+)
+
+/**
+ * Writes a grid and a recorded search as SVG.
+ * The drawing is in cell units, Scale pixels per cell, with one rectangle
+ * per run of blocked cells and per expanded cell, each coloured from one
+ * of Levels classes, in row order: the same search gives the same file.
+ * Titles adds the rank, g and h of every expanded cell as a tooltip.
+ */
+type TSVGRenderer struct {
+	Scale   int
+	Heatmap THeatmap
+	Levels  int
+	Titles  bool
+}
+
+/**
+ * @constructor
+ */
+func NewSVGRenderer() *TSVGRenderer {
+	return &TSVGRenderer{
+		Scale:   10,
+		Heatmap: HeatmapOrder,
+		Levels:  16,
+	}
+}
+
+// heatColor interpolates from a pale to a dark orange.
+func heatColor(level, levels int) string {
+	var from, to = [3]int{0xff, 0xf5, 0xeb}, [3]int{0x7f, 0x27, 0x04}
+	var rgb [3]int
+	for i := range rgb {
+		if levels > 1 {
+			rgb[i] = from[i] + (to[i]-from[i])*level/(levels-1)
+		} else {
+			rgb[i] = to[i]
+		}
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
+
+/**
+ * Write the grid, the heatmap of the trace (which may be nil), the path
+ * and the start and goal.
+ */
+func (this *TSVGRenderer) WriteSVG(w io.Writer, grid *core.TGrid, start, goal core.NodeID, trace *core.TSearchTrace, path core.ArrayNodeID) error {
+	scale, levels := this.Scale, this.Levels
+	if scale < 1 {
+		scale = 1
+	}
+	if levels < 1 {
+		levels = 1
+	}
+	width, height := grid.Width(), grid.Height()
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" shape-rendering=\"crispEdges\">\n",
+		width*scale, height*scale, width, height)
+	fmt.Fprintf(bw, "<style>\n.b{fill:#404040}\n")
+	for level := 0; level < levels; level++ {
+		fmt.Fprintf(bw, ".h%d{fill:%s}\n", level, heatColor(level, levels))
+	}
+	fmt.Fprintf(bw, ".p{fill:none;stroke:#2040e0;stroke-width:0.3;stroke-linejoin:round;stroke-linecap:round;shape-rendering:auto}\n")
+	fmt.Fprintf(bw, "</style>\n<rect width=\"%d\" height=\"%d\" fill=\"#fff\"/>\n", width, height)
+
+	var order map[core.NodeID]int
+	var values map[core.NodeID]float64
+	if trace != nil && this.Heatmap != HeatmapNone {
+		order = trace.ExpansionOrder()
+		values = trace.ClosedG()
+	}
+	var heat func(id core.NodeID) float64
+	var label string
+	switch this.Heatmap {
+	case HeatmapOrder:
+		heat, label = func(id core.NodeID) float64 { return float64(order[id]) }, "order"
+	case HeatmapG:
+		heat, label = func(id core.NodeID) float64 { return values[id] }, "g"
+	}
+	if len(order) > 0 {
+		var top float64
+		for id := range order {
+			if v := heat(id); v > top {
+				top = v
+			}
+		}
+		var h = map[core.NodeID]float64{}
+		if this.Titles {
+			for _, e := range trace.Events {
+				if _, ok := h[e.ID]; e.Close && !ok {
+					h[e.ID] = e.H
+				}
+			}
+		}
+		fmt.Fprintf(bw, "<g class=\"heat\"><desc>%s 0 to %g</desc>\n", label, top)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				id := grid.NodeID(x, y)
+				rank, ok := order[id]
+				if !ok {
+					continue
+				}
+				level := 0
+				if top > 0 {
+					level = int(heat(id) / top * float64(levels-1))
+				}
+				if this.Titles {
+					fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"1\" height=\"1\" class=\"h%d\"><title>(%d, %d) #%d g %g h %g</title></rect>\n",
+						x, y, level, x, y, rank, values[id], h[id])
+				} else {
+					fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"1\" height=\"1\" class=\"h%d\"/>\n", x, y, level)
+				}
+			}
+		}
+		fmt.Fprintf(bw, "</g>\n")
+	}
+
+	fmt.Fprintf(bw, "<g class=\"b\">\n")
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; {
+			if grid.IsWalkableAt(x, y) {
+				x++
+				continue
+			}
+			run := x
+			for x < width && !grid.IsWalkableAt(x, y) {
+				x++
+			}
+			fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"1\"/>\n", run, y, x-run)
+		}
+	}
+	fmt.Fprintf(bw, "</g>\n")
+
+	if len(path) > 0 {
+		fmt.Fprintf(bw, "<polyline class=\"p\" points=\"")
+		for i, id := range path {
+			x, y := grid.NodeXY(id)
+			if i > 0 {
+				bw.WriteByte(' ')
+			}
+			fmt.Fprintf(bw, "%d.5,%d.5", x, y)
+		}
+		fmt.Fprintf(bw, "\"/>\n")
+	}
+	for _, end := range []struct {
+		id    core.NodeID
+		color string
+	}{{start, "#20a020"}, {goal, "#e02020"}} {
+		x, y := grid.NodeXY(end.id)
+		fmt.Fprintf(bw, "<circle cx=\"%d.5\" cy=\"%d.5\" r=\"0.4\" fill=\"%s\"/>\n", x, y, end.color)
+	}
+	fmt.Fprintf(bw, "</svg>\n")
+	return bw.Flush()
+}
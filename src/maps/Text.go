@@ -0,0 +1,117 @@
+package maps
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-PathFinding/core"
+)
+
+/**
+ * Read a text map: one line per row, '.' or '0' for walkable cells and
+ * '#' or '1' for blocked ones. Lines starting with ';' are comments.
+ * Rows shorter than the longest one are padded with blocked cells.
+ */
+func ReadText(r io.Reader) (*core.TGrid, error) {
+	var rows []string
+	var width int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, ";") {
+			continue
+		}
+		rows = append(rows, line)
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// trailing empty lines are not rows.
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 || width == 0 {
+		return nil, fmt.Errorf("maps: empty text map")
+	}
+
+	var matrix = make(core.DoubleInt32, len(rows))
+	for y, row := range rows {
+		matrix[y] = make(core.ArrayInt32, width)
+		for x := 0; x < width; x++ {
+			if x >= len(row) {
+				matrix[y][x] = 1
+				continue
+			}
+			switch row[x] {
+			case '.', '0':
+			case '#', '1':
+				matrix[y][x] = 1
+			default:
+				return nil, fmt.Errorf("maps: line %d: unexpected %q", y+1, row[x])
+			}
+		}
+	}
+	return core.Grid(width, len(rows), matrix), nil
+}
+
+/**
+ * Write a grid as a text map, '.' walkable and '#' blocked.
+ */
+func WriteText(w io.Writer, grid *core.TGrid) error {
+	return writeRows(w, grid, func(x, y int) byte {
+		if grid.IsWalkableAt(x, y) {
+			return '.'
+		}
+		return '#'
+	})
+}
+
+/**
+ * Draw the path over the text map: '*' for its cells, 'S' and 'G' for
+ * its first and last one.
+ */
+func WriteOverlay(w io.Writer, grid *core.TGrid, path core.DoubleInt32) error {
+	var marks = map[[2]int]byte{}
+	for i, p := range path {
+		mark := byte('*')
+		if i == 0 {
+			mark = 'S'
+		} else if i == len(path)-1 {
+			mark = 'G'
+		}
+		marks[[2]int{int(p[0]), int(p[1])}] = mark
+	}
+	return writeRows(w, grid, func(x, y int) byte {
+		if mark, ok := marks[[2]int{x, y}]; ok {
+			return mark
+		}
+		if grid.IsWalkableAt(x, y) {
+			return '.'
+		}
+		return '#'
+	})
+}
+
+func writeRows(w io.Writer, grid *core.TGrid, cell func(x, y int) byte) error {
+	bw := bufio.NewWriter(w)
+	line := make([]byte, grid.Width()+1)
+	line[grid.Width()] = '\n'
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			line[x] = cell(x, y)
+		}
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,727 @@
+package maps
+
+/*
+	by stefan 2572915286@qq.com
+	Tiled maps, https://doc.mapeditor.org/en/stable/reference/tmx-map-format/
+*/
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go-PathFinding/core"
+)
+
+func init() {
+	Loaders[".tmx"] = func(filename string) (*core.TGrid, error) {
+		return NewTiledImporter().Load(filename)
+	}
+	Loaders[".tmj"] = Loaders[".tmx"]
+	// other tools write .json files too: only Tiled maps are loaded.
+	Loaders[".json"] = func(filename string) (*core.TGrid, error) {
+		if err := checkTiledJSON(filename); err != nil {
+			return nil, err
+		}
+		return NewTiledImporter().Load(filename)
+	}
+}
+
+// checkTiledJSON fails unless the JSON file is a Tiled map, of "type"
+// "map" or, before Tiled 1.2, with an orientation and layers.
+func checkTiledJSON(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var probe struct {
+		Type        string          `json:"type"`
+		Orientation string          `json:"orientation"`
+		Layers      json.RawMessage `json:"layers"`
+	}
+	if err := json.NewDecoder(file).Decode(&probe); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	if probe.Type != "map" && (probe.Type != "" || probe.Orientation == "" || probe.Layers == nil) {
+		return fmt.Errorf("maps: %s is not a Tiled map", filename)
+	}
+	return nil
+}
+
+/**
+ * Maps the tiles of the tile layers to walkability and terrain cost.
+ * A rule matches a tile when all of its set criteria do. Every matching
+ * rule applies, in order, so that later rules override earlier ones.
+ */
+type TTileRule struct {
+	Layer    string   // layer name, "" for any layer
+	GIDs     []uint32 // global tile ids, empty for any tile
+	Property string   // tile property that must be set, "" for none
+	Value    string   // value Property must have, "" for any value
+
+	Blocked      bool    // block the cell
+	Walkable     bool    // unblock the cell, e.g. a bridge layer
+	Cost         float64 // set the cell cost, 0 to leave it
+	CostProperty string  // set the cell cost from this tile property
+}
+
+/**
+ * Rules following the usual tile properties: "walkable" false or
+ * "blocked" true block the cell, "cost" sets its cost.
+ */
+func DefaultTileRules() []TTileRule {
+	return []TTileRule{
+		{Property: "walkable", Value: "false", Blocked: true},
+		{Property: "blocked", Value: "true", Blocked: true},
+		{Property: "cost", CostProperty: "cost"},
+	}
+}
+
+/**
+ * Importer of Tiled maps (TMX and JSON), orthogonal and finite.
+ * Cells start walkable at cost 1, then go through Rules tile layer by
+ * tile layer; the shapes of the object layers are blocked last, every
+ * cell whose centre they cover.
+ */
+type TTiledImporter struct {
+	Rules []TTileRule
+	// object layers rasterised as obstacles, all of them when empty.
+	ObstacleLayers []string
+	// opens external tilesets by their source; Load opens them next to
+	// the map when nil.
+	Open func(source string) (io.ReadCloser, error)
+}
+
+/**
+ * @constructor
+ */
+func NewTiledImporter() *TTiledImporter {
+	return &TTiledImporter{
+		Rules: DefaultTileRules(),
+	}
+}
+
+/**
+ * Load a .tmx, .tmj or .json Tiled map.
+ */
+func (this *TTiledImporter) Load(filename string) (*core.TGrid, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	importer := *this
+	if importer.Open == nil {
+		dir := filepath.Dir(filename)
+		importer.Open = func(source string) (io.ReadCloser, error) {
+			return os.Open(filepath.Join(dir, source))
+		}
+	}
+	var grid *core.TGrid
+	if strings.ToLower(filepath.Ext(filename)) == ".tmx" {
+		grid, err = importer.ReadTMX(file)
+	} else {
+		grid, err = importer.ReadJSON(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return grid, nil
+}
+
+const (
+	flippedHorizontally = 0x80000000
+	flippedVertically   = 0x40000000
+	flippedDiagonally   = 0x20000000
+	rotatedHexagonal    = 0x10000000
+	gidMask             = ^uint32(flippedHorizontally | flippedVertically | flippedDiagonally | rotatedHexagonal)
+)
+
+type tiledPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// a layer in the format independent form.
+type tiledLayer struct {
+	name    string
+	object  bool
+	gids    []uint32
+	shapes  [][]tiledPoint // polygons, in pixels
+	ellipse [][5]float64   // x, y, width, height in pixels, rotation in degrees
+}
+
+type tiledMap struct {
+	width, height         int
+	tileWidth, tileHeight int
+	layers                []tiledLayer
+	properties            map[uint32]map[string]string // by global tile id
+}
+
+// ---------------------------------------------------------------- TMX
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+type tmxTileset struct {
+	FirstGID uint32 `xml:"firstgid,attr"`
+	Source   string `xml:"source,attr"`
+	Tiles    []struct {
+		ID         uint32        `xml:"id,attr"`
+		Properties []tmxProperty `xml:"properties>property"`
+	} `xml:"tile"`
+}
+
+type tmxData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Text        string `xml:",chardata"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+	Chunks []struct{} `xml:"chunk"`
+}
+
+type tmxObject struct {
+	X        float64   `xml:"x,attr"`
+	Y        float64   `xml:"y,attr"`
+	Width    float64   `xml:"width,attr"`
+	Height   float64   `xml:"height,attr"`
+	Rotation float64   `xml:"rotation,attr"`
+	GID      uint32    `xml:"gid,attr"`
+	Ellipse  *struct{} `xml:"ellipse"`
+	Point    *struct{} `xml:"point"`
+	Polyline *struct{} `xml:"polyline"`
+	Text     *struct{} `xml:"text"`
+	Polygon  *struct {
+		Points string `xml:"points,attr"`
+	} `xml:"polygon"`
+}
+
+// layer, objectgroup or group, told apart by XMLName.
+type tmxLayer struct {
+	XMLName xml.Name
+	Name    string      `xml:"name,attr"`
+	Data    *tmxData    `xml:"data"`
+	Objects []tmxObject `xml:"object"`
+	Layers  []tmxLayer  `xml:",any"`
+}
+
+type tmxMap struct {
+	Orientation string       `xml:"orientation,attr"`
+	Width       int          `xml:"width,attr"`
+	Height      int          `xml:"height,attr"`
+	TileWidth   int          `xml:"tilewidth,attr"`
+	TileHeight  int          `xml:"tileheight,attr"`
+	Infinite    int          `xml:"infinite,attr"`
+	Tilesets    []tmxTileset `xml:"tileset"`
+	Layers      []tmxLayer   `xml:",any"`
+}
+
+/**
+ * Read a TMX map.
+ */
+func (this *TTiledImporter) ReadTMX(r io.Reader) (*core.TGrid, error) {
+	var raw tmxMap
+	if err := xml.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("maps: %v", err)
+	}
+	if err := checkTiledMap(raw.Orientation, raw.Infinite != 0, raw.Width, raw.Height, raw.TileWidth, raw.TileHeight); err != nil {
+		return nil, err
+	}
+	var m = &tiledMap{
+		width:      raw.Width,
+		height:     raw.Height,
+		tileWidth:  raw.TileWidth,
+		tileHeight: raw.TileHeight,
+		properties: map[uint32]map[string]string{},
+	}
+	for _, tileset := range raw.Tilesets {
+		if err := this.addTMXTileset(m, tileset); err != nil {
+			return nil, err
+		}
+	}
+	if err := this.addTMXLayers(m, raw.Layers); err != nil {
+		return nil, err
+	}
+	return this.build(m)
+}
+
+func (this *TTiledImporter) loadTileset(m *tiledMap, firstGID uint32, source string) error {
+	if this.Open == nil {
+		return fmt.Errorf("maps: external tileset %s without Open", source)
+	}
+	file, err := this.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if strings.ToLower(filepath.Ext(source)) != ".tsx" {
+		return this.addJSONTileset(m, firstGID, file)
+	}
+	var tileset tmxTileset
+	if err := xml.NewDecoder(file).Decode(&tileset); err != nil {
+		return fmt.Errorf("maps: %s: %v", source, err)
+	}
+	tileset.FirstGID = firstGID
+	tileset.Source = ""
+	return this.addTMXTileset(m, tileset)
+}
+
+func (this *TTiledImporter) addTMXTileset(m *tiledMap, tileset tmxTileset) error {
+	if tileset.Source != "" {
+		return this.loadTileset(m, tileset.FirstGID, tileset.Source)
+	}
+	for _, tile := range tileset.Tiles {
+		for _, property := range tile.Properties {
+			value := property.Value
+			if value == "" {
+				// multi-line strings are stored as text.
+				value = property.Text
+			}
+			setTileProperty(m, tileset.FirstGID+tile.ID, property.Name, value)
+		}
+	}
+	return nil
+}
+
+func (this *TTiledImporter) addTMXLayers(m *tiledMap, layers []tmxLayer) error {
+	for _, layer := range layers {
+		switch layer.XMLName.Local {
+		case "layer":
+			if layer.Data == nil {
+				return fmt.Errorf("maps: layer %q has no data", layer.Name)
+			}
+			if len(layer.Data.Chunks) > 0 {
+				return fmt.Errorf("maps: infinite maps are not supported")
+			}
+			var gids []uint32
+			var err error
+			if layer.Data.Encoding == "" {
+				for _, tile := range layer.Data.Tiles {
+					gids = append(gids, tile.GID)
+				}
+			} else {
+				gids, err = decodeTileData(layer.Data.Encoding, layer.Data.Compression, layer.Data.Text)
+				if err != nil {
+					return fmt.Errorf("maps: layer %q: %v", layer.Name, err)
+				}
+			}
+			m.layers = append(m.layers, tiledLayer{name: layer.Name, gids: gids})
+		case "objectgroup":
+			out := tiledLayer{name: layer.Name, object: true}
+			for _, object := range layer.Objects {
+				var points []tiledPoint
+				if object.Polygon != nil {
+					for _, pair := range strings.Fields(object.Polygon.Points) {
+						xy := strings.Split(pair, ",")
+						if len(xy) != 2 {
+							return fmt.Errorf("maps: invalid polygon point %q", pair)
+						}
+						x, err1 := strconv.ParseFloat(xy[0], 64)
+						y, err2 := strconv.ParseFloat(xy[1], 64)
+						if err1 != nil || err2 != nil {
+							return fmt.Errorf("maps: invalid polygon point %q", pair)
+						}
+						points = append(points, tiledPoint{x, y})
+					}
+				}
+				addObject(&out, object.X, object.Y, object.Width, object.Height, object.Rotation, object.GID,
+					object.Ellipse != nil, object.Point != nil || object.Polyline != nil || object.Text != nil, points)
+			}
+			m.layers = append(m.layers, out)
+		case "group":
+			if err := this.addTMXLayers(m, layer.Layers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// --------------------------------------------------------------- JSON
+
+type jsonProperty struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonTileset struct {
+	FirstGID uint32 `json:"firstgid"`
+	Source   string `json:"source"`
+	Tiles    []struct {
+		ID         uint32         `json:"id"`
+		Properties []jsonProperty `json:"properties"`
+	} `json:"tiles"`
+}
+
+type jsonObject struct {
+	X        float64      `json:"x"`
+	Y        float64      `json:"y"`
+	Width    float64      `json:"width"`
+	Height   float64      `json:"height"`
+	Rotation float64      `json:"rotation"`
+	GID      uint32       `json:"gid"`
+	Ellipse  bool         `json:"ellipse"`
+	Point    bool         `json:"point"`
+	Polygon  []tiledPoint `json:"polygon"`
+	Polyline []tiledPoint `json:"polyline"`
+	Text     interface{}  `json:"text"`
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Data        json.RawMessage `json:"data"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Chunks      []interface{}   `json:"chunks"`
+	Objects     []jsonObject    `json:"objects"`
+	Layers      []jsonLayer     `json:"layers"`
+}
+
+type jsonMap struct {
+	Orientation string        `json:"orientation"`
+	Width       int           `json:"width"`
+	Height      int           `json:"height"`
+	TileWidth   int           `json:"tilewidth"`
+	TileHeight  int           `json:"tileheight"`
+	Infinite    bool          `json:"infinite"`
+	Tilesets    []jsonTileset `json:"tilesets"`
+	Layers      []jsonLayer   `json:"layers"`
+}
+
+/**
+ * Read a Tiled JSON map.
+ */
+func (this *TTiledImporter) ReadJSON(r io.Reader) (*core.TGrid, error) {
+	var raw jsonMap
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("maps: %v", err)
+	}
+	if err := checkTiledMap(raw.Orientation, raw.Infinite, raw.Width, raw.Height, raw.TileWidth, raw.TileHeight); err != nil {
+		return nil, err
+	}
+	var m = &tiledMap{
+		width:      raw.Width,
+		height:     raw.Height,
+		tileWidth:  raw.TileWidth,
+		tileHeight: raw.TileHeight,
+		properties: map[uint32]map[string]string{},
+	}
+	for _, tileset := range raw.Tilesets {
+		if tileset.Source != "" {
+			if err := this.loadTileset(m, tileset.FirstGID, tileset.Source); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		addJSONTiles(m, tileset)
+	}
+	if err := this.addJSONLayers(m, raw.Layers); err != nil {
+		return nil, err
+	}
+	return this.build(m)
+}
+
+func (this *TTiledImporter) addJSONTileset(m *tiledMap, firstGID uint32, r io.Reader) error {
+	var tileset jsonTileset
+	if err := json.NewDecoder(r).Decode(&tileset); err != nil {
+		return fmt.Errorf("maps: tileset: %v", err)
+	}
+	tileset.FirstGID = firstGID
+	addJSONTiles(m, tileset)
+	return nil
+}
+
+func addJSONTiles(m *tiledMap, tileset jsonTileset) {
+	for _, tile := range tileset.Tiles {
+		for _, property := range tile.Properties {
+			// strings are unquoted, booleans and numbers kept as written.
+			var value string
+			if err := json.Unmarshal(property.Value, &value); err != nil {
+				value = string(property.Value)
+			}
+			setTileProperty(m, tileset.FirstGID+tile.ID, property.Name, value)
+		}
+	}
+}
+
+func (this *TTiledImporter) addJSONLayers(m *tiledMap, layers []jsonLayer) error {
+	for _, layer := range layers {
+		switch layer.Type {
+		case "tilelayer":
+			if len(layer.Chunks) > 0 {
+				return fmt.Errorf("maps: infinite maps are not supported")
+			}
+			var gids []uint32
+			if layer.Encoding == "" || layer.Encoding == "csv" {
+				if err := json.Unmarshal(layer.Data, &gids); err != nil {
+					return fmt.Errorf("maps: layer %q: %v", layer.Name, err)
+				}
+			} else {
+				var text string
+				if err := json.Unmarshal(layer.Data, &text); err != nil {
+					return fmt.Errorf("maps: layer %q: %v", layer.Name, err)
+				}
+				var err error
+				if gids, err = decodeTileData(layer.Encoding, layer.Compression, text); err != nil {
+					return fmt.Errorf("maps: layer %q: %v", layer.Name, err)
+				}
+			}
+			m.layers = append(m.layers, tiledLayer{name: layer.Name, gids: gids})
+		case "objectgroup":
+			out := tiledLayer{name: layer.Name, object: true}
+			for _, object := range layer.Objects {
+				addObject(&out, object.X, object.Y, object.Width, object.Height, object.Rotation, object.GID,
+					object.Ellipse, object.Point || object.Polyline != nil || object.Text != nil, object.Polygon)
+			}
+			m.layers = append(m.layers, out)
+		case "group":
+			if err := this.addJSONLayers(m, layer.Layers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ------------------------------------------------------------- common
+
+func checkTiledMap(orientation string, infinite bool, width, height, tileWidth, tileHeight int) error {
+	if orientation != "orthogonal" {
+		return fmt.Errorf("maps: %q maps are not supported", orientation)
+	}
+	if infinite {
+		return fmt.Errorf("maps: infinite maps are not supported")
+	}
+	if width <= 0 || height <= 0 || tileWidth <= 0 || tileHeight <= 0 {
+		return fmt.Errorf("maps: invalid map size %dx%d of %dx%d tiles", width, height, tileWidth, tileHeight)
+	}
+	return nil
+}
+
+func setTileProperty(m *tiledMap, gid uint32, name, value string) {
+	if m.properties[gid] == nil {
+		m.properties[gid] = map[string]string{}
+	}
+	m.properties[gid][name] = value
+}
+
+/**
+ * Decode csv or base64 (optionally zlib or gzip compressed) tile data.
+ */
+func decodeTileData(encoding, compression, text string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		var gids []uint32
+		for _, field := range strings.Split(text, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			gids = append(gids, uint32(gid))
+		}
+		return gids, nil
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+		if err != nil {
+			return nil, err
+		}
+		var r io.Reader = bytes.NewReader(data)
+		switch compression {
+		case "":
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return nil, err
+			}
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported compression %q", compression)
+		}
+		if data, err = ioutil.ReadAll(r); err != nil {
+			return nil, err
+		}
+		if len(data)%4 != 0 {
+			return nil, fmt.Errorf("tile data of %d bytes", len(data))
+		}
+		var gids = make([]uint32, len(data)/4)
+		for i := range gids {
+			gids[i] = binary.LittleEndian.Uint32(data[4*i:])
+		}
+		return gids, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q", encoding)
+}
+
+/**
+ * Add an object of an object layer as a shape, in pixels. Points,
+ * polylines and texts enclose nothing and are skipped.
+ */
+func addObject(layer *tiledLayer, x, y, width, height, rotation float64, gid uint32, ellipse, open bool, polygon []tiledPoint) {
+	if open {
+		return
+	}
+	var points []tiledPoint
+	switch {
+	case len(polygon) > 0:
+		points = polygon
+	case ellipse:
+		layer.ellipse = append(layer.ellipse, [5]float64{x, y, width, height, rotation})
+		return
+	case gid != 0:
+		// tile objects hang from their bottom-left corner.
+		points = []tiledPoint{{0, -height}, {width, -height}, {width, 0}, {0, 0}}
+	default:
+		points = []tiledPoint{{0, 0}, {width, 0}, {width, height}, {0, height}}
+	}
+	// relative points, rotated clockwise in degrees around the object position.
+	sin, cos := math.Sincos(rotation * math.Pi / 180)
+	var shape = make([]tiledPoint, len(points))
+	for i, p := range points {
+		shape[i] = tiledPoint{x + p.X*cos - p.Y*sin, y + p.X*sin + p.Y*cos}
+	}
+	layer.shapes = append(layer.shapes, shape)
+}
+
+func insideShape(shape []tiledPoint, x, y float64) bool {
+	var inside bool
+	for i, j := 0, len(shape)-1; i < len(shape); j, i = i, i+1 {
+		a, b := shape[i], shape[j]
+		if (a.Y > y) != (b.Y > y) && x < (b.X-a.X)*(y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func (this *TTiledImporter) obstacleLayer(name string) bool {
+	if len(this.ObstacleLayers) == 0 {
+		return true
+	}
+	for _, layer := range this.ObstacleLayers {
+		if layer == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *TTileRule) matches(layer string, gid uint32, properties map[string]string) bool {
+	if this.Layer != "" && this.Layer != layer {
+		return false
+	}
+	if len(this.GIDs) > 0 {
+		var found bool
+		for _, id := range this.GIDs {
+			found = found || id == gid
+		}
+		if !found {
+			return false
+		}
+	}
+	if this.Property != "" {
+		value, ok := properties[this.Property]
+		if !ok || (this.Value != "" && value != this.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *TTiledImporter) build(m *tiledMap) (*core.TGrid, error) {
+	var grid = core.Grid(m.width, m.height, nil)
+	for _, layer := range m.layers {
+		if layer.object {
+			continue
+		}
+		if len(layer.gids) != m.width*m.height {
+			return nil, fmt.Errorf("maps: layer %q has %d tiles, expected %d", layer.name, len(layer.gids), m.width*m.height)
+		}
+		for i, gid := range layer.gids {
+			gid &= gidMask
+			if gid == 0 {
+				continue
+			}
+			x, y := i%m.width, i/m.width
+			properties := m.properties[gid]
+			for _, rule := range this.Rules {
+				if !rule.matches(layer.name, gid, properties) {
+					continue
+				}
+				if rule.Blocked {
+					grid.SetWalkableAt(x, y, false)
+				}
+				if rule.Walkable {
+					grid.SetWalkableAt(x, y, true)
+				}
+				if rule.Cost > 0 {
+					grid.SetCostAt(x, y, rule.Cost)
+				}
+				if value, ok := properties[rule.CostProperty]; ok && rule.CostProperty != "" {
+					cost, err := strconv.ParseFloat(value, 64)
+					if err != nil || !core.ValidCost(cost) {
+						return nil, fmt.Errorf("maps: tile %d: invalid %s %q", gid, rule.CostProperty, value)
+					}
+					grid.SetCostAt(x, y, cost)
+				}
+			}
+		}
+	}
+
+	tw, th := float64(m.tileWidth), float64(m.tileHeight)
+	for _, layer := range m.layers {
+		if !layer.object || !this.obstacleLayer(layer.name) {
+			continue
+		}
+		for y := 0; y < m.height; y++ {
+			for x := 0; x < m.width; x++ {
+				cx, cy := (float64(x)+0.5)*tw, (float64(y)+0.5)*th
+				var covered bool
+				for _, shape := range layer.shapes {
+					covered = covered || insideShape(shape, cx, cy)
+				}
+				for _, e := range layer.ellipse {
+					rx, ry := e[2]/2, e[3]/2
+					if rx > 0 && ry > 0 {
+						// the cell centre in the frame of the unrotated
+						// ellipse, rotated around its position.
+						sin, cos := math.Sincos(e[4] * math.Pi / 180)
+						px, py := cx-e[0], cy-e[1]
+						lx, ly := px*cos+py*sin, py*cos-px*sin
+						dx, dy := (lx-rx)/rx, (ly-ry)/ry
+						covered = covered || dx*dx+dy*dy <= 1
+					}
+				}
+				if covered {
+					grid.SetWalkableAt(x, y, false)
+				}
+			}
+		}
+	}
+	return grid, nil
+}
@@ -0,0 +1,103 @@
+package navmesh
+
+/*
+	by stefan 2572915286@qq.com
+	Simple stupid funnel algorithm,
+	based upon http://digestingduck.blogspot.com/2010/03/simple-stupid-funnel-algorithm.html
+*/
+
+/**
+ * Twice the signed area of the triangle (a, b, c).
+ */
+func triarea2(a, b, c Point) float64 {
+	ax := b.X - a.X
+	ay := b.Y - a.Y
+	bx := c.X - a.X
+	by := c.Y - a.Y
+	return bx*ay - ax*by
+}
+
+func vequal(a, b Point) bool {
+	const eq = 0.001 * 0.001
+	dx := a.X - b.X
+	dy := a.Y - b.Y
+	return dx*dx+dy*dy < eq
+}
+
+/**
+ * String-pull a corridor of portals into the shortest path.
+ * @param {[][2]Point} portals - {left, right} pairs, the first one being
+ *     {start, start} and the last one {goal, goal}.
+ * @return {[]Point} The waypoints, including both start and goal.
+ */
+func StringPull(portals [][2]Point) []Point {
+	if len(portals) == 0 {
+		return []Point{}
+	}
+
+	var (
+		pts                  = []Point{}
+		portalApex           = portals[0][0]
+		portalLeft           = portals[0][0]
+		portalRight          = portals[0][1]
+		apexIndex, leftIndex int
+		rightIndex           int
+	)
+
+	// add start point.
+	pts = append(pts, portalApex)
+
+	for i := 1; i < len(portals); i++ {
+		left := portals[i][0]
+		right := portals[i][1]
+
+		// update right vertex.
+		if triarea2(portalApex, portalRight, right) <= 0 {
+			if vequal(portalApex, portalRight) || triarea2(portalApex, portalLeft, right) > 0 {
+				// tighten the funnel.
+				portalRight = right
+				rightIndex = i
+			} else {
+				// right over left, insert left to path and restart scan
+				// from portal left point.
+				pts = append(pts, portalLeft)
+				portalApex = portalLeft
+				apexIndex = leftIndex
+				portalLeft = portalApex
+				portalRight = portalApex
+				leftIndex = apexIndex
+				rightIndex = apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+
+		// update left vertex.
+		if triarea2(portalApex, portalLeft, left) >= 0 {
+			if vequal(portalApex, portalLeft) || triarea2(portalApex, portalRight, left) < 0 {
+				// tighten the funnel.
+				portalLeft = left
+				leftIndex = i
+			} else {
+				// left over right, insert right to path and restart scan
+				// from portal right point.
+				pts = append(pts, portalRight)
+				portalApex = portalRight
+				apexIndex = rightIndex
+				portalLeft = portalApex
+				portalRight = portalApex
+				leftIndex = apexIndex
+				rightIndex = apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+	}
+
+	// append last point to path.
+	last := portals[len(portals)-1][0]
+	if !vequal(pts[len(pts)-1], last) {
+		pts = append(pts, last)
+	}
+	return pts
+}
@@ -0,0 +1,237 @@
+package navmesh
+
+/*
+	by stefan 2572915286@qq.com
+	Navigation mesh built from the walkable cells of a core.TGrid.
+*/
+
+import (
+	"go-PathFinding/core"
+	"math"
+)
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+func dist(a, b Point) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+/**
+ * A shared edge between two polygons, from A to B along the border.
+ */
+type TPortal struct {
+	Id    int
+	A     Point
+	B     Point
+	Polys [2]int
+}
+
+/**
+ * The polygon on the other side of the portal.
+ */
+func (this *TPortal) Other(poly int) int {
+	if this.Polys[0] == poly {
+		return this.Polys[1]
+	}
+	return this.Polys[0]
+}
+
+func (this *TPortal) Joins(poly int) bool {
+	return this.Polys[0] == poly || this.Polys[1] == poly
+}
+
+func (this *TPortal) Mid() Point {
+	return Point{X: (this.A.X + this.B.X) / 2, Y: (this.A.Y + this.B.Y) / 2}
+}
+
+/**
+ * A convex polygon of the mesh. Polygons built from a grid are the
+ * axis-aligned rectangles [MinX, MaxX] x [MinY, MaxY] in cell units.
+ */
+type TPolygon struct {
+	Id      int
+	Verts   []Point
+	MinX    float64
+	MinY    float64
+	MaxX    float64
+	MaxY    float64
+	Portals []*TPortal
+}
+
+func (this *TPolygon) Center() Point {
+	return Point{X: (this.MinX + this.MaxX) / 2, Y: (this.MinY + this.MaxY) / 2}
+}
+
+func (this *TPolygon) Contains(p Point) bool {
+	return p.X >= this.MinX && p.X <= this.MaxX && p.Y >= this.MinY && p.Y <= this.MaxY
+}
+
+type TNavMesh struct {
+	Polygons []*TPolygon
+	Portals  []*TPortal
+	width    int
+	height   int
+	cellPoly []int // polygon id of every cell, -1 when blocked
+}
+
+/**
+ * Build a navigation mesh from a grid.
+ * Walkable cells are greedily merged into maximal rectangles, row by row:
+ * a rectangle grows to the right first, then downwards while the whole
+ * span stays walkable. Rectangles sharing a border become neighbours,
+ * the overlapping part of that border being their portal.
+ * @param {TGrid} grid
+ * @return {TNavMesh}
+ */
+func BuildNavMesh(grid *core.TGrid) *TNavMesh {
+	width := grid.Width()
+	height := grid.Height()
+	mesh := &TNavMesh{
+		Polygons: []*TPolygon{},
+		Portals:  []*TPortal{},
+		width:    width,
+		height:   height,
+		cellPoly: make([]int, width*height),
+	}
+	for i := range mesh.cellPoly {
+		mesh.cellPoly[i] = -1
+	}
+
+	free := func(x, y int) bool {
+		return grid.IsWalkableAt(x, y) && mesh.cellPoly[y*width+x] == -1
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !free(x, y) {
+				continue
+			}
+			x1 := x + 1
+			for x1 < width && free(x1, y) {
+				x1++
+			}
+			y1 := y + 1
+			for y1 < height {
+				full := true
+				for cx := x; cx < x1; cx++ {
+					if !free(cx, y1) {
+						full = false
+						break
+					}
+				}
+				if !full {
+					break
+				}
+				y1++
+			}
+
+			poly := newRectPolygon(len(mesh.Polygons), float64(x), float64(y), float64(x1), float64(y1))
+			mesh.Polygons = append(mesh.Polygons, poly)
+			for cy := y; cy < y1; cy++ {
+				for cx := x; cx < x1; cx++ {
+					mesh.cellPoly[cy*width+cx] = poly.Id
+				}
+			}
+		}
+	}
+
+	mesh.buildPortals()
+	return mesh
+}
+
+func newRectPolygon(id int, minX, minY, maxX, maxY float64) *TPolygon {
+	return &TPolygon{
+		Id: id,
+		Verts: []Point{
+			{X: minX, Y: minY},
+			{X: maxX, Y: minY},
+			{X: maxX, Y: maxY},
+			{X: minX, Y: maxY},
+		},
+		MinX:    minX,
+		MinY:    minY,
+		MaxX:    maxX,
+		MaxY:    maxY,
+		Portals: []*TPortal{},
+	}
+}
+
+/**
+ * Link every pair of rectangles touching along a border of positive length.
+ */
+func (this *TNavMesh) buildPortals() {
+	for i, a := range this.Polygons {
+		for _, b := range this.Polygons[i+1:] {
+			var p1, p2 Point
+			switch {
+			case a.MaxX == b.MinX || a.MinX == b.MaxX:
+				x := a.MaxX
+				if a.MinX == b.MaxX {
+					x = a.MinX
+				}
+				lo := math.Max(a.MinY, b.MinY)
+				hi := math.Min(a.MaxY, b.MaxY)
+				if hi <= lo {
+					continue
+				}
+				p1, p2 = Point{X: x, Y: lo}, Point{X: x, Y: hi}
+			case a.MaxY == b.MinY || a.MinY == b.MaxY:
+				y := a.MaxY
+				if a.MinY == b.MaxY {
+					y = a.MinY
+				}
+				lo := math.Max(a.MinX, b.MinX)
+				hi := math.Min(a.MaxX, b.MaxX)
+				if hi <= lo {
+					continue
+				}
+				p1, p2 = Point{X: lo, Y: y}, Point{X: hi, Y: y}
+			default:
+				continue
+			}
+			portal := &TPortal{Id: len(this.Portals), A: p1, B: p2, Polys: [2]int{a.Id, b.Id}}
+			this.Portals = append(this.Portals, portal)
+			a.Portals = append(a.Portals, portal)
+			b.Portals = append(b.Portals, portal)
+		}
+	}
+}
+
+/**
+ * Return the polygon containing the point, or nil when the point is
+ * outside the walkable area.
+ */
+func (this *TNavMesh) FindPolygon(p Point) *TPolygon {
+	x := int(math.Floor(p.X))
+	y := int(math.Floor(p.Y))
+	// points on the right or bottom border belong to the last cell.
+	if x == this.width && p.X == float64(this.width) {
+		x--
+	}
+	if y == this.height && p.Y == float64(this.height) {
+		y--
+	}
+	if x < 0 || x >= this.width || y < 0 || y >= this.height {
+		return nil
+	}
+	if id := this.cellPoly[y*this.width+x]; id >= 0 {
+		return this.Polygons[id]
+	}
+	// on a corner or border shared with a blocked cell.
+	for _, poly := range this.Polygons {
+		if poly.Contains(p) {
+			return poly
+		}
+	}
+	return nil
+}
+
+/**
+ * Center of a grid cell, the point FindPath expects for cell coordinates.
+ */
+func CellCenter(x, y int) Point {
+	return Point{X: float64(x) + 0.5, Y: float64(y) + 0.5}
+}
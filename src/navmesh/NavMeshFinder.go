@@ -0,0 +1,154 @@
+package navmesh
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"go-PathFinding/core"
+	"go-PathFinding/finders/AStarFinder"
+)
+
+const (
+	startNodeId = core.NodeID(-1)
+	goalNodeId  = core.NodeID(-2)
+)
+
+/**
+ * The graph searched for one query: the portal midpoints of the mesh,
+ * plus the start and goal points. Two nodes are linked when they lie on
+ * the same polygon, the cost being the straight distance between them.
+ */
+type corridorGraph struct {
+	mesh      *TNavMesh
+	start     Point
+	goal      Point
+	startPoly *TPolygon
+	goalPoly  *TPolygon
+}
+
+func (this *corridorGraph) pos(id core.NodeID) Point {
+	switch id {
+	case startNodeId:
+		return this.start
+	case goalNodeId:
+		return this.goal
+	}
+	return this.mesh.Portals[id].Mid()
+}
+
+func (this *corridorGraph) IsWalkable(id core.NodeID, opt *core.Opt) bool {
+	return id == startNodeId || id == goalNodeId || (id >= 0 && int(id) < len(this.mesh.Portals))
+}
+
+func (this *corridorGraph) Neighbors(id core.NodeID, opt *core.Opt) core.ArrayNodeID {
+	var ids = core.ArrayNodeID{}
+	var polys []*TPolygon
+	switch id {
+	case startNodeId:
+		polys = []*TPolygon{this.startPoly}
+	case goalNodeId:
+		polys = []*TPolygon{this.goalPoly}
+	default:
+		portal := this.mesh.Portals[id]
+		polys = []*TPolygon{this.mesh.Polygons[portal.Polys[0]], this.mesh.Polygons[portal.Polys[1]]}
+	}
+	for _, poly := range polys {
+		for _, portal := range poly.Portals {
+			if core.NodeID(portal.Id) != id {
+				ids = append(ids, core.NodeID(portal.Id))
+			}
+		}
+		if poly == this.goalPoly && id != goalNodeId {
+			ids = append(ids, goalNodeId)
+		}
+	}
+	return ids
+}
+
+func (this *corridorGraph) Cost(from, to core.NodeID) float64 {
+	return dist(this.pos(from), this.pos(to))
+}
+
+func (this *corridorGraph) Heuristic(from, to core.NodeID, opt *core.Opt) float64 {
+	return dist(this.pos(from), this.pos(to))
+}
+
+/**
+ * Find the polygon corridor from start to goal with A*, searching the
+ * portal midpoints of the mesh.
+ * @return {[]*TPolygon} the corridor and the portal crossed to enter
+ *     each polygon (nil for the first one); nil if there is no path.
+ */
+func (this *TNavMesh) FindCorridor(start, goal Point) ([]*TPolygon, []*TPortal) {
+	graph := &corridorGraph{
+		mesh:      this,
+		start:     start,
+		goal:      goal,
+		startPoly: this.FindPolygon(start),
+		goalPoly:  this.FindPolygon(goal),
+	}
+	if graph.startPoly == nil || graph.goalPoly == nil {
+		return nil, nil
+	}
+	if graph.startPoly == graph.goalPoly {
+		return []*TPolygon{graph.startPoly}, []*TPortal{nil}
+	}
+
+	finder := AStarFinder.CreateAStarFinder(&core.Opt{})
+	ids := finder.FindPath(startNodeId, goalNodeId, graph)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// walk the portals, crossing each one out of the current polygon.
+	var polys = []*TPolygon{graph.startPoly}
+	var portals = []*TPortal{nil}
+	for _, id := range ids[1 : len(ids)-1] {
+		portal := this.Portals[id]
+		current := polys[len(polys)-1]
+		if !portal.Joins(current.Id) {
+			// the previous portal was touched without being crossed.
+			polys = polys[:len(polys)-1]
+			portals = portals[:len(portals)-1]
+			current = polys[len(polys)-1]
+		}
+		polys = append(polys, this.Polygons[portal.Other(current.Id)])
+		portals = append(portals, portal)
+	}
+	return polys, portals
+}
+
+/**
+ * Find and return the shortest path between two points of the mesh.
+ * @return {[]Point} The minimal set of waypoints, including both start
+ *     and goal; empty if there is no path.
+ */
+func (this *TNavMesh) FindPath(start, goal Point) []Point {
+	polys, portals := this.FindCorridor(start, goal)
+	if polys == nil {
+		return []Point{}
+	}
+
+	var funnel = [][2]Point{{start, start}}
+	for i := 1; i < len(polys); i++ {
+		// orient the portal as {left, right} seen from the previous polygon.
+		from := polys[i-1].Center()
+		a, b := portals[i].A, portals[i].B
+		if triarea2(from, a, b) < 0 {
+			funnel = append(funnel, [2]Point{b, a})
+		} else {
+			funnel = append(funnel, [2]Point{a, b})
+		}
+	}
+	funnel = append(funnel, [2]Point{goal, goal})
+
+	return StringPull(funnel)
+}
+
+/**
+ * FindPath between the centers of two grid cells.
+ */
+func (this *TNavMesh) FindCellPath(startX, startY, endX, endY int) []Point {
+	return this.FindPath(CellCenter(startX, startY), CellCenter(endX, endY))
+}
@@ -0,0 +1,77 @@
+package navmesh
+
+import (
+	"go-PathFinding/core"
+	"math"
+	"testing"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+func TestBuildNavMesh(t *testing.T) {
+	grid := core.Grid(4, 3, core.DoubleInt32{
+		{0, 0, 0, 0},
+		{0, 1, 1, 0},
+		{0, 0, 0, 0},
+	})
+	mesh := BuildNavMesh(grid)
+	var area float64
+	for _, poly := range mesh.Polygons {
+		area += (poly.MaxX - poly.MinX) * (poly.MaxY - poly.MinY)
+		if len(poly.Portals) == 0 {
+			t.Errorf("polygon %v has no portal", poly.Id)
+		}
+	}
+	if area != 10 {
+		t.Errorf("mesh covers %v cells, expected 10", area)
+	}
+	if mesh.FindPolygon(Point{X: 1.5, Y: 1.5}) != nil {
+		t.Error("blocked cell is inside the mesh")
+	}
+}
+
+func TestNavMeshFindPath(t *testing.T) {
+	// a wall with a single gap at the bottom.
+	grid := core.Grid(5, 5, core.DoubleInt32{
+		{0, 0, 1, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 0},
+	})
+	mesh := BuildNavMesh(grid)
+
+	path := mesh.FindCellPath(0, 0, 4, 0)
+	akLog.FmtPrintln("result: ", path)
+	expected := []Point{{0.5, 0.5}, {2, 4}, {3, 4}, {4.5, 0.5}}
+	if len(path) != len(expected) {
+		t.Fatalf("unexpected path: %v", path)
+	}
+	for i := range expected {
+		if dist(path[i], expected[i]) > 1e-9 {
+			t.Fatalf("unexpected path: %v", path)
+		}
+	}
+
+	// open space collapses to the straight segment.
+	path = mesh.FindCellPath(0, 0, 1, 4)
+	if len(path) != 2 {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	var length float64
+	for i := 1; i < len(path); i++ {
+		length += dist(path[i-1], path[i])
+	}
+	if math.Abs(length-math.Hypot(1, 4)) > 1e-9 {
+		t.Errorf("unexpected length %v", length)
+	}
+}
+
+func TestNavMeshUnreachable(t *testing.T) {
+	grid := core.Grid(3, 1, core.DoubleInt32{{0, 1, 0}})
+	mesh := BuildNavMesh(grid)
+	if path := mesh.FindCellPath(0, 0, 2, 0); len(path) != 0 {
+		t.Fatalf("unexpected path: %v", path)
+	}
+}
@@ -0,0 +1,218 @@
+package pb
+
+/*
+	by stefan 2572915286@qq.com
+	Conversions between the wire types of pathfinding.proto and core.
+*/
+
+//go:generate protoc --go_out=paths=source_relative:. pathfinding.proto
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"go-PathFinding/core"
+)
+
+var heuristics = []struct {
+	value Heuristic
+	fn    func(dx, dy float64) float64
+}{
+	{Heuristic_HEURISTIC_MANHATTAN, core.Manhattan},
+	{Heuristic_HEURISTIC_EUCLIDEAN, core.Euclidean},
+	{Heuristic_HEURISTIC_OCTILE, core.Octile},
+	{Heuristic_HEURISTIC_CHEBYSHEV, core.Chebyshev},
+}
+
+/**
+ * Run-length encode the cells of a width x height grid.
+ */
+func encodeRuns(width, height int, walkable func(x, y int) bool) *Grid {
+	var grid = &Grid{Width: uint32(width), Height: uint32(height)}
+	var run uint32
+	var state = true
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if walkable(x, y) != state {
+				grid.Runs = append(grid.Runs, run)
+				run = 0
+				state = !state
+			}
+			run++
+		}
+	}
+	if run > 0 {
+		grid.Runs = append(grid.Runs, run)
+	}
+	return grid
+}
+
+/**
+ * Encode the walkability of a core grid.
+ */
+func GridFromCore(grid *core.TGrid) *Grid {
+	return encodeRuns(grid.Width(), grid.Height(), grid.IsWalkableAt)
+}
+
+/**
+ * Encode a 0-1 matrix as taken by core.Grid, indexed [y][x] with 0 for
+ * walkable. A nil matrix is all walkable.
+ */
+func GridFromMatrix(width, height int, matrix core.DoubleInt32) *Grid {
+	return encodeRuns(width, height, func(x, y int) bool {
+		return matrix == nil || matrix[y][x] == 0
+	})
+}
+
+/**
+ * Largest grid Matrix and ToCore decode, in cells: a few bytes of runs
+ * may describe any size. Raise it for larger maps.
+ */
+var MaxCells uint64 = 16 << 20
+
+/**
+ * Decode into a 0-1 matrix, indexed [y][x] with 1 for blocked cells.
+ * Fails if a side is 0, if the grid is larger than MaxCells or if the runs
+ * do not cover it exactly.
+ */
+func (this *Grid) Matrix() (core.DoubleInt32, error) {
+	if this.GetWidth() == 0 || this.GetHeight() == 0 {
+		return nil, fmt.Errorf("pb: empty grid %dx%d", this.GetWidth(), this.GetHeight())
+	}
+	cells := uint64(this.GetWidth()) * uint64(this.GetHeight())
+	if cells > MaxCells {
+		return nil, fmt.Errorf("pb: grid of %dx%d cells, at most %d accepted", this.GetWidth(), this.GetHeight(), MaxCells)
+	}
+	width, height := int(this.GetWidth()), int(this.GetHeight())
+	var total uint64
+	for _, run := range this.GetRuns() {
+		total += uint64(run)
+	}
+	if total != cells {
+		return nil, fmt.Errorf("pb: grid runs cover %d cells, want %dx%d", total, width, height)
+	}
+
+	var matrix = make(core.DoubleInt32, height)
+	for y := range matrix {
+		matrix[y] = make(core.ArrayInt32, width)
+	}
+	var cell int
+	for i, run := range this.GetRuns() {
+		blocked := int32(i % 2)
+		for ; run > 0; run-- {
+			matrix[cell/width][cell%width] = blocked
+			cell++
+		}
+	}
+	return matrix, nil
+}
+
+/**
+ * Decode into a core grid.
+ */
+func (this *Grid) ToCore() (*core.TGrid, error) {
+	matrix, err := this.Matrix()
+	if err != nil {
+		return nil, err
+	}
+	return core.Grid(int(this.GetWidth()), int(this.GetHeight()), matrix), nil
+}
+
+/**
+ * Encode core options. Heuristics other than the core ones cannot be
+ * sent and are left unspecified, as are Tracer and Heuristic3D.
+ */
+func OptFromCore(opt *core.Opt) *Opt {
+	var msg = &Opt{
+		AllowDiagonal:    opt.AllowDiagonal,
+		DontCrossCorners: opt.DontCrossCorners,
+		DiagonalMovement: DiagonalMovement(opt.DiagonalMovement),
+		Neighborhood:     uint32(opt.Neighborhood),
+		AgentSize:        opt.AgentSize,
+		HeuristicWeight:  opt.Weight,
+		TieBreaking:      TieBreaking(opt.TieBreaking),
+	}
+	// whole weights are sent in the deprecated field too, for older peers.
+	if opt.Weight == math.Trunc(opt.Weight) && math.Abs(opt.Weight) <= math.MaxInt32 {
+		msg.Weight = int32(opt.Weight)
+	}
+	if opt.Heuristic != nil {
+		fn := reflect.ValueOf(opt.Heuristic).Pointer()
+		for _, h := range heuristics {
+			if reflect.ValueOf(h.fn).Pointer() == fn {
+				msg.Heuristic = h.value
+			}
+		}
+	}
+	return msg
+}
+
+/**
+ * Decode into core options.
+ */
+func (this *Opt) ToCore() (*core.Opt, error) {
+	var opt = &core.Opt{
+		AllowDiagonal:    this.GetAllowDiagonal(),
+		DontCrossCorners: this.GetDontCrossCorners(),
+		Weight:           this.GetHeuristicWeight(),
+		Neighborhood:     core.Neighborhood3D(this.GetNeighborhood()),
+		AgentSize:        this.GetAgentSize(),
+	}
+	if opt.Weight == 0 {
+		opt.Weight = float64(this.GetWeight())
+	}
+	if _, ok := TieBreaking_name[int32(this.GetTieBreaking())]; !ok {
+		return nil, fmt.Errorf("pb: unknown tie breaking %d", this.GetTieBreaking())
+	}
+	opt.TieBreaking = core.TieBreaking(this.GetTieBreaking())
+	if move := this.GetDiagonalMovement(); move != DiagonalMovement_DIAGONAL_MOVEMENT_UNSPECIFIED {
+		if _, ok := DiagonalMovement_name[int32(move)]; !ok {
+			return nil, fmt.Errorf("pb: unknown diagonal movement %d", move)
+		}
+		opt.DiagonalMovement = core.DiagonalMovement(move)
+	}
+	if heuristic := this.GetHeuristic(); heuristic != Heuristic_HEURISTIC_UNSPECIFIED {
+		for _, h := range heuristics {
+			if h.value == heuristic {
+				opt.Heuristic = h.fn
+			}
+		}
+		if opt.Heuristic == nil {
+			return nil, fmt.Errorf("pb: unknown heuristic %d", heuristic)
+		}
+	}
+	switch opt.Neighborhood {
+	case core.Planar, core.Six, core.Eighteen, core.TwentySix:
+	default:
+		return nil, fmt.Errorf("pb: unknown neighborhood %d", opt.Neighborhood)
+	}
+	return opt, nil
+}
+
+/**
+ * Flatten path coordinates into x0, y0, x1, y1, ...
+ */
+func CoordsFromCore(path core.DoubleInt32) []int32 {
+	var coords = make([]int32, 0, 2*len(path))
+	for _, p := range path {
+		coords = append(coords, p[0], p[1])
+	}
+	return coords
+}
+
+/**
+ * The result path as {x, y} coordinates.
+ */
+func (this *PathResult) Path() core.DoubleInt32 {
+	coords := this.GetCoords()
+	var path = make(core.DoubleInt32, 0, len(coords)/2)
+	for i := 0; i+1 < len(coords); i += 2 {
+		path = append(path, core.ArrayInt32{coords[i], coords[i+1]})
+	}
+	return path
+}
+
+func (this *Point) XY() (int, int) {
+	return int(this.GetX()), int(this.GetY())
+}
@@ -0,0 +1,131 @@
+package pb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go-PathFinding/core"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGridRoundTrip(t *testing.T) {
+	matrix := core.DoubleInt32{
+		{1, 0, 0, 0},
+		{0, 1, 1, 0},
+		{0, 0, 0, 1},
+	}
+	grid := core.Grid(4, 3, matrix)
+	msg := GridFromCore(grid)
+	akLog.FmtPrintln("runs: ", msg.Runs)
+	// starts blocked, so the first walkable run is empty.
+	if want := []uint32{0, 1, 4, 2, 4, 1}; !reflect.DeepEqual(msg.Runs, want) {
+		t.Fatalf("runs %v, expected %v", msg.Runs, want)
+	}
+	if !proto.Equal(msg, GridFromMatrix(4, 3, matrix)) {
+		t.Fatalf("GridFromMatrix differs from GridFromCore")
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Grid
+	if err := proto.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	back, err := decoded.ToCore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			if back.IsWalkableAt(x, y) != grid.IsWalkableAt(x, y) {
+				t.Fatalf("cell (%d, %d) changed in the round trip", x, y)
+			}
+		}
+	}
+
+	empty := GridFromMatrix(3, 2, nil)
+	if len(empty.Runs) != 1 || empty.Runs[0] != 6 {
+		t.Fatalf("open grid runs %v", empty.Runs)
+	}
+	if _, err := (&Grid{Width: 3, Height: 2, Runs: []uint32{5}}).Matrix(); err == nil {
+		t.Fatalf("short runs accepted")
+	}
+
+	// empty grids and huge ones are refused before anything is allocated.
+	for _, bad := range []*Grid{
+		{Width: 5, Height: 0},
+		{Width: 0, Height: 0},
+		{Width: 1, Height: math.MaxUint32, Runs: []uint32{math.MaxUint32}},
+		{Width: math.MaxUint32, Height: math.MaxUint32},
+	} {
+		if _, err := bad.ToCore(); err == nil {
+			t.Fatalf("grid %dx%d accepted", bad.Width, bad.Height)
+		}
+	}
+}
+
+func TestOptRoundTrip(t *testing.T) {
+	opt := &core.Opt{
+		DiagonalMovement: core.OnlyWhenNoObstacles,
+		Heuristic:        core.Octile,
+		Weight:           2,
+		AgentSize:        3,
+		TieBreaking:      core.TieFewerTurns,
+	}
+	msg := OptFromCore(opt)
+	if msg.Heuristic != Heuristic_HEURISTIC_OCTILE || msg.DiagonalMovement != DiagonalMovement_DIAGONAL_MOVEMENT_ONLY_WHEN_NO_OBSTACLES {
+		t.Fatalf("unexpected message %v", msg)
+	}
+	back, err := msg.ToCore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.DiagonalMovement != opt.DiagonalMovement || back.Weight != 2 || back.AgentSize != 3 || back.Heuristic(3, 4) != core.Octile(3, 4) || back.TieBreaking != core.TieFewerTurns {
+		t.Fatalf("unexpected options %+v", back)
+	}
+	if _, err := (&Opt{Heuristic: 42}).ToCore(); err == nil {
+		t.Fatalf("unknown heuristic accepted")
+	}
+	if _, err := (&Opt{TieBreaking: 42}).ToCore(); err == nil {
+		t.Fatalf("unknown tie breaking accepted")
+	}
+
+	// fractional weights only go in heuristic_weight, whole ones in both.
+	if msg.Weight != 2 || msg.HeuristicWeight != 2 {
+		t.Fatalf("unexpected weights %v, %v", msg.Weight, msg.HeuristicWeight)
+	}
+	opt.Weight = 1.5
+	if msg = OptFromCore(opt); msg.Weight != 0 || msg.HeuristicWeight != 1.5 {
+		t.Fatalf("unexpected weights %v, %v", msg.Weight, msg.HeuristicWeight)
+	}
+	if back, _ = msg.ToCore(); back.Weight != 1.5 {
+		t.Fatalf("unexpected weight %v", back.Weight)
+	}
+	if back, _ = (&Opt{Weight: 3}).ToCore(); back.Weight != 3 {
+		t.Fatalf("weight of an older peer read as %v", back.Weight)
+	}
+}
+
+func TestPathResult(t *testing.T) {
+	path := core.DoubleInt32{{0, 0}, {1, 1}, {-1, 2}}
+	res := &PathResult{Coords: CoordsFromCore(path), PathLength: core.PathLength(path)}
+	data, err := proto.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded PathResult
+	if err := proto.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Path(), path) {
+		t.Fatalf("path %v, expected %v", decoded.Path(), path)
+	}
+	if math.Abs(decoded.PathLength-(core.SQRT2+math.Sqrt(5))) > 1e-12 {
+		t.Fatalf("path length %v", decoded.PathLength)
+	}
+}
@@ -0,0 +1,955 @@
+// by stefan 2572915286@qq.com
+//
+// Wire format of grids, options and paths, shared with non-Go clients.
+// Breaking changes go to a new package version (pathfinding.v2); within
+// v1 fields are only ever added.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.23.0
+// 	protoc        (unknown)
+// source: pathfinding.proto
+
+package pb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// Values match core.DiagonalMovement.
+type DiagonalMovement int32
+
+const (
+	DiagonalMovement_DIAGONAL_MOVEMENT_UNSPECIFIED             DiagonalMovement = 0
+	DiagonalMovement_DIAGONAL_MOVEMENT_ALWAYS                  DiagonalMovement = 1
+	DiagonalMovement_DIAGONAL_MOVEMENT_NEVER                   DiagonalMovement = 2
+	DiagonalMovement_DIAGONAL_MOVEMENT_IF_AT_MOST_ONE_OBSTACLE DiagonalMovement = 3
+	DiagonalMovement_DIAGONAL_MOVEMENT_ONLY_WHEN_NO_OBSTACLES  DiagonalMovement = 4
+)
+
+// Enum value maps for DiagonalMovement.
+var (
+	DiagonalMovement_name = map[int32]string{
+		0: "DIAGONAL_MOVEMENT_UNSPECIFIED",
+		1: "DIAGONAL_MOVEMENT_ALWAYS",
+		2: "DIAGONAL_MOVEMENT_NEVER",
+		3: "DIAGONAL_MOVEMENT_IF_AT_MOST_ONE_OBSTACLE",
+		4: "DIAGONAL_MOVEMENT_ONLY_WHEN_NO_OBSTACLES",
+	}
+	DiagonalMovement_value = map[string]int32{
+		"DIAGONAL_MOVEMENT_UNSPECIFIED":             0,
+		"DIAGONAL_MOVEMENT_ALWAYS":                  1,
+		"DIAGONAL_MOVEMENT_NEVER":                   2,
+		"DIAGONAL_MOVEMENT_IF_AT_MOST_ONE_OBSTACLE": 3,
+		"DIAGONAL_MOVEMENT_ONLY_WHEN_NO_OBSTACLES":  4,
+	}
+)
+
+func (x DiagonalMovement) Enum() *DiagonalMovement {
+	p := new(DiagonalMovement)
+	*p = x
+	return p
+}
+
+func (x DiagonalMovement) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiagonalMovement) Descriptor() protoreflect.EnumDescriptor {
+	return file_pathfinding_proto_enumTypes[0].Descriptor()
+}
+
+func (DiagonalMovement) Type() protoreflect.EnumType {
+	return &file_pathfinding_proto_enumTypes[0]
+}
+
+func (x DiagonalMovement) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiagonalMovement.Descriptor instead.
+func (DiagonalMovement) EnumDescriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{0}
+}
+
+type Heuristic int32
+
+const (
+	Heuristic_HEURISTIC_UNSPECIFIED Heuristic = 0
+	Heuristic_HEURISTIC_MANHATTAN   Heuristic = 1
+	Heuristic_HEURISTIC_EUCLIDEAN   Heuristic = 2
+	Heuristic_HEURISTIC_OCTILE      Heuristic = 3
+	Heuristic_HEURISTIC_CHEBYSHEV   Heuristic = 4
+)
+
+// Enum value maps for Heuristic.
+var (
+	Heuristic_name = map[int32]string{
+		0: "HEURISTIC_UNSPECIFIED",
+		1: "HEURISTIC_MANHATTAN",
+		2: "HEURISTIC_EUCLIDEAN",
+		3: "HEURISTIC_OCTILE",
+		4: "HEURISTIC_CHEBYSHEV",
+	}
+	Heuristic_value = map[string]int32{
+		"HEURISTIC_UNSPECIFIED": 0,
+		"HEURISTIC_MANHATTAN":   1,
+		"HEURISTIC_EUCLIDEAN":   2,
+		"HEURISTIC_OCTILE":      3,
+		"HEURISTIC_CHEBYSHEV":   4,
+	}
+)
+
+func (x Heuristic) Enum() *Heuristic {
+	p := new(Heuristic)
+	*p = x
+	return p
+}
+
+func (x Heuristic) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Heuristic) Descriptor() protoreflect.EnumDescriptor {
+	return file_pathfinding_proto_enumTypes[1].Descriptor()
+}
+
+func (Heuristic) Type() protoreflect.EnumType {
+	return &file_pathfinding_proto_enumTypes[1]
+}
+
+func (x Heuristic) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Heuristic.Descriptor instead.
+func (Heuristic) EnumDescriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{1}
+}
+
+// Values match core.TieBreaking; FIFO is the default.
+type TieBreaking int32
+
+const (
+	TieBreaking_TIE_BREAKING_FIFO            TieBreaking = 0
+	TieBreaking_TIE_BREAKING_LIFO            TieBreaking = 1
+	TieBreaking_TIE_BREAKING_PREFER_HIGHER_G TieBreaking = 2
+	TieBreaking_TIE_BREAKING_CROSS_PRODUCT   TieBreaking = 3
+	TieBreaking_TIE_BREAKING_FEWER_TURNS     TieBreaking = 4
+)
+
+// Enum value maps for TieBreaking.
+var (
+	TieBreaking_name = map[int32]string{
+		0: "TIE_BREAKING_FIFO",
+		1: "TIE_BREAKING_LIFO",
+		2: "TIE_BREAKING_PREFER_HIGHER_G",
+		3: "TIE_BREAKING_CROSS_PRODUCT",
+		4: "TIE_BREAKING_FEWER_TURNS",
+	}
+	TieBreaking_value = map[string]int32{
+		"TIE_BREAKING_FIFO":            0,
+		"TIE_BREAKING_LIFO":            1,
+		"TIE_BREAKING_PREFER_HIGHER_G": 2,
+		"TIE_BREAKING_CROSS_PRODUCT":   3,
+		"TIE_BREAKING_FEWER_TURNS":     4,
+	}
+)
+
+func (x TieBreaking) Enum() *TieBreaking {
+	p := new(TieBreaking)
+	*p = x
+	return p
+}
+
+func (x TieBreaking) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TieBreaking) Descriptor() protoreflect.EnumDescriptor {
+	return file_pathfinding_proto_enumTypes[2].Descriptor()
+}
+
+func (TieBreaking) Type() protoreflect.EnumType {
+	return &file_pathfinding_proto_enumTypes[2]
+}
+
+func (x TieBreaking) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TieBreaking.Descriptor instead.
+func (TieBreaking) EnumDescriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{2}
+}
+
+// A walkability grid, run-length encoded.
+// Cells are taken row by row from (0, 0); runs alternate between walkable
+// and blocked cells, starting with walkable (so the first run may be 0).
+// The runs add up to width * height.
+type Grid struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Width  uint32   `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height uint32   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Runs   []uint32 `protobuf:"varint,3,rep,packed,name=runs,proto3" json:"runs,omitempty"`
+}
+
+func (x *Grid) Reset() {
+	*x = Grid{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pathfinding_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Grid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Grid) ProtoMessage() {}
+
+func (x *Grid) ProtoReflect() protoreflect.Message {
+	mi := &file_pathfinding_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Grid.ProtoReflect.Descriptor instead.
+func (*Grid) Descriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Grid) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Grid) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Grid) GetRuns() []uint32 {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+// Search options, see core.Opt. Unset fields take the finder defaults.
+type Opt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AllowDiagonal    bool             `protobuf:"varint,1,opt,name=allow_diagonal,json=allowDiagonal,proto3" json:"allow_diagonal,omitempty"`
+	DontCrossCorners bool             `protobuf:"varint,2,opt,name=dont_cross_corners,json=dontCrossCorners,proto3" json:"dont_cross_corners,omitempty"`
+	DiagonalMovement DiagonalMovement `protobuf:"varint,3,opt,name=diagonal_movement,json=diagonalMovement,proto3,enum=pathfinding.v1.DiagonalMovement" json:"diagonal_movement,omitempty"`
+	Heuristic        Heuristic        `protobuf:"varint,4,opt,name=heuristic,proto3,enum=pathfinding.v1.Heuristic" json:"heuristic,omitempty"`
+	// Whole weights only, use heuristic_weight.
+	//
+	// Deprecated: Do not use.
+	Weight int32 `protobuf:"varint,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	// core.Neighborhood3D of 3D searches: 0, 6, 18 or 26.
+	Neighborhood uint32 `protobuf:"varint,6,opt,name=neighborhood,proto3" json:"neighborhood,omitempty"`
+	AgentSize    int32  `protobuf:"varint,7,opt,name=agent_size,json=agentSize,proto3" json:"agent_size,omitempty"`
+	// Weight of the heuristic, e.g. 1.5; weight is read when unset.
+	HeuristicWeight float64     `protobuf:"fixed64,8,opt,name=heuristic_weight,json=heuristicWeight,proto3" json:"heuristic_weight,omitempty"`
+	TieBreaking     TieBreaking `protobuf:"varint,9,opt,name=tie_breaking,json=tieBreaking,proto3,enum=pathfinding.v1.TieBreaking" json:"tie_breaking,omitempty"`
+}
+
+func (x *Opt) Reset() {
+	*x = Opt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pathfinding_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Opt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Opt) ProtoMessage() {}
+
+func (x *Opt) ProtoReflect() protoreflect.Message {
+	mi := &file_pathfinding_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Opt.ProtoReflect.Descriptor instead.
+func (*Opt) Descriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Opt) GetAllowDiagonal() bool {
+	if x != nil {
+		return x.AllowDiagonal
+	}
+	return false
+}
+
+func (x *Opt) GetDontCrossCorners() bool {
+	if x != nil {
+		return x.DontCrossCorners
+	}
+	return false
+}
+
+func (x *Opt) GetDiagonalMovement() DiagonalMovement {
+	if x != nil {
+		return x.DiagonalMovement
+	}
+	return DiagonalMovement_DIAGONAL_MOVEMENT_UNSPECIFIED
+}
+
+func (x *Opt) GetHeuristic() Heuristic {
+	if x != nil {
+		return x.Heuristic
+	}
+	return Heuristic_HEURISTIC_UNSPECIFIED
+}
+
+// Deprecated: Do not use.
+func (x *Opt) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Opt) GetNeighborhood() uint32 {
+	if x != nil {
+		return x.Neighborhood
+	}
+	return 0
+}
+
+func (x *Opt) GetAgentSize() int32 {
+	if x != nil {
+		return x.AgentSize
+	}
+	return 0
+}
+
+func (x *Opt) GetHeuristicWeight() float64 {
+	if x != nil {
+		return x.HeuristicWeight
+	}
+	return 0
+}
+
+func (x *Opt) GetTieBreaking() TieBreaking {
+	if x != nil {
+		return x.TieBreaking
+	}
+	return TieBreaking_TIE_BREAKING_FIFO
+}
+
+type Point struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X int32 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y int32 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pathfinding_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_pathfinding_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Point) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Point) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type PathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The grid, either sent along or named after an earlier upload.
+	//
+	// Types that are assignable to Map:
+	//	*PathRequest_Grid
+	//	*PathRequest_GridName
+	Map isPathRequest_Map `protobuf_oneof:"map"`
+	// Registered finder name, e.g. "astar"; empty for the default.
+	Finder string `protobuf:"bytes,3,opt,name=finder,proto3" json:"finder,omitempty"`
+	Start  *Point `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End    *Point `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	Opt    *Opt   `protobuf:"bytes,6,opt,name=opt,proto3" json:"opt,omitempty"`
+}
+
+func (x *PathRequest) Reset() {
+	*x = PathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pathfinding_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathRequest) ProtoMessage() {}
+
+func (x *PathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pathfinding_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathRequest.ProtoReflect.Descriptor instead.
+func (*PathRequest) Descriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{3}
+}
+
+func (m *PathRequest) GetMap() isPathRequest_Map {
+	if m != nil {
+		return m.Map
+	}
+	return nil
+}
+
+func (x *PathRequest) GetGrid() *Grid {
+	if x, ok := x.GetMap().(*PathRequest_Grid); ok {
+		return x.Grid
+	}
+	return nil
+}
+
+func (x *PathRequest) GetGridName() string {
+	if x, ok := x.GetMap().(*PathRequest_GridName); ok {
+		return x.GridName
+	}
+	return ""
+}
+
+func (x *PathRequest) GetFinder() string {
+	if x != nil {
+		return x.Finder
+	}
+	return ""
+}
+
+func (x *PathRequest) GetStart() *Point {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *PathRequest) GetEnd() *Point {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *PathRequest) GetOpt() *Opt {
+	if x != nil {
+		return x.Opt
+	}
+	return nil
+}
+
+type isPathRequest_Map interface {
+	isPathRequest_Map()
+}
+
+type PathRequest_Grid struct {
+	Grid *Grid `protobuf:"bytes,1,opt,name=grid,proto3,oneof"`
+}
+
+type PathRequest_GridName struct {
+	GridName string `protobuf:"bytes,2,opt,name=grid_name,json=gridName,proto3,oneof"`
+}
+
+func (*PathRequest_Grid) isPathRequest_Map() {}
+
+func (*PathRequest_GridName) isPathRequest_Map() {}
+
+type SearchStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Opened        uint32 `protobuf:"varint,1,opt,name=opened,proto3" json:"opened,omitempty"`
+	Closed        uint32 `protobuf:"varint,2,opt,name=closed,proto3" json:"closed,omitempty"`
+	ElapsedMicros uint64 `protobuf:"varint,3,opt,name=elapsed_micros,json=elapsedMicros,proto3" json:"elapsed_micros,omitempty"`
+}
+
+func (x *SearchStats) Reset() {
+	*x = SearchStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pathfinding_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchStats) ProtoMessage() {}
+
+func (x *SearchStats) ProtoReflect() protoreflect.Message {
+	mi := &file_pathfinding_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchStats.ProtoReflect.Descriptor instead.
+func (*SearchStats) Descriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SearchStats) GetOpened() uint32 {
+	if x != nil {
+		return x.Opened
+	}
+	return 0
+}
+
+func (x *SearchStats) GetClosed() uint32 {
+	if x != nil {
+		return x.Closed
+	}
+	return 0
+}
+
+func (x *SearchStats) GetElapsedMicros() uint64 {
+	if x != nil {
+		return x.ElapsedMicros
+	}
+	return 0
+}
+
+type PathResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The path as x0, y0, x1, y1, ...; empty if there is none.
+	Coords []int32 `protobuf:"zigzag32,1,rep,packed,name=coords,proto3" json:"coords,omitempty"`
+	// core.PathLength of the path rounded up, use path_length.
+	//
+	// Deprecated: Do not use.
+	Length uint32 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	// Sum of the edge costs along the path.
+	Cost  float64      `protobuf:"fixed64,3,opt,name=cost,proto3" json:"cost,omitempty"`
+	Stats *SearchStats `protobuf:"bytes,4,opt,name=stats,proto3" json:"stats,omitempty"`
+	// core.PathLength of the path.
+	PathLength float64 `protobuf:"fixed64,5,opt,name=path_length,json=pathLength,proto3" json:"path_length,omitempty"`
+}
+
+func (x *PathResult) Reset() {
+	*x = PathResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pathfinding_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PathResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathResult) ProtoMessage() {}
+
+func (x *PathResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pathfinding_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathResult.ProtoReflect.Descriptor instead.
+func (*PathResult) Descriptor() ([]byte, []int) {
+	return file_pathfinding_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PathResult) GetCoords() []int32 {
+	if x != nil {
+		return x.Coords
+	}
+	return nil
+}
+
+// Deprecated: Do not use.
+func (x *PathResult) GetLength() uint32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *PathResult) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *PathResult) GetStats() *SearchStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *PathResult) GetPathLength() float64 {
+	if x != nil {
+		return x.PathLength
+	}
+	return 0
+}
+
+var File_pathfinding_proto protoreflect.FileDescriptor
+
+var file_pathfinding_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x2e, 0x76, 0x31, 0x22, 0x48, 0x0a, 0x04, 0x47, 0x72, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6e,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x22, 0xac, 0x03,
+	0x0a, 0x03, 0x4f, 0x70, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x64,
+	0x69, 0x61, 0x67, 0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x44, 0x69, 0x61, 0x67, 0x6f, 0x6e, 0x61, 0x6c, 0x12, 0x2c, 0x0a, 0x12,
+	0x64, 0x6f, 0x6e, 0x74, 0x5f, 0x63, 0x72, 0x6f, 0x73, 0x73, 0x5f, 0x63, 0x6f, 0x72, 0x6e, 0x65,
+	0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x64, 0x6f, 0x6e, 0x74, 0x43, 0x72,
+	0x6f, 0x73, 0x73, 0x43, 0x6f, 0x72, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x4d, 0x0a, 0x11, 0x64, 0x69,
+	0x61, 0x67, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x6d, 0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6f, 0x6e, 0x61, 0x6c, 0x4d,
+	0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x10, 0x64, 0x69, 0x61, 0x67, 0x6f, 0x6e, 0x61,
+	0x6c, 0x4d, 0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x09, 0x68, 0x65, 0x75,
+	0x72, 0x69, 0x73, 0x74, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x70,
+	0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65,
+	0x75, 0x72, 0x69, 0x73, 0x74, 0x69, 0x63, 0x52, 0x09, 0x68, 0x65, 0x75, 0x72, 0x69, 0x73, 0x74,
+	0x69, 0x63, 0x12, 0x1a, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x22,
+	0x0a, 0x0c, 0x6e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x68, 0x6f, 0x6f, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6e, 0x65, 0x69, 0x67, 0x68, 0x62, 0x6f, 0x72, 0x68, 0x6f,
+	0x6f, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a,
+	0x65, 0x12, 0x29, 0x0a, 0x10, 0x68, 0x65, 0x75, 0x72, 0x69, 0x73, 0x74, 0x69, 0x63, 0x5f, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x68, 0x65, 0x75,
+	0x72, 0x69, 0x73, 0x74, 0x69, 0x63, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x3e, 0x0a, 0x0c,
+	0x74, 0x69, 0x65, 0x5f, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x65, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x52,
+	0x0b, 0x74, 0x69, 0x65, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x22, 0x23, 0x0a, 0x05,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01,
+	0x79, 0x22, 0xf4, 0x01, 0x0a, 0x0b, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x2a, 0x0a, 0x04, 0x67, 0x72, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x72, 0x69, 0x64, 0x48, 0x00, 0x52, 0x04, 0x67, 0x72, 0x69, 0x64, 0x12, 0x1d, 0x0a,
+	0x09, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x08, 0x67, 0x72, 0x69, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x66, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69,
+	0x6e, 0x64, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x12, 0x27, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x25, 0x0a, 0x03, 0x6f, 0x70,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x74, 0x52, 0x03, 0x6f, 0x70,
+	0x74, 0x42, 0x05, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x22, 0x64, 0x0a, 0x0b, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x70, 0x65, 0x6e, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x06, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x6c, 0x61, 0x70, 0x73,
+	0x65, 0x64, 0x5f, 0x6d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0d, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x22, 0xa8,
+	0x01, 0x0a, 0x0a, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x11, 0x52, 0x06, 0x63,
+	0x6f, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1a, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x04, 0x63, 0x6f, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x61, 0x74, 0x68, 0x66, 0x69, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x74, 0x68,
+	0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x70,
+	0x61, 0x74, 0x68, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x2a, 0xcd, 0x01, 0x0a, 0x10, 0x44, 0x69,
+	0x61, 0x67, 0x6f, 0x6e, 0x61, 0x6c, 0x4d, 0x6f, 0x76, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21,
+	0x0a, 0x1d, 0x44, 0x49, 0x41, 0x47, 0x4f, 0x4e, 0x41, 0x4c, 0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x4d,
+	0x45, 0x4e, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x1c, 0x0a, 0x18, 0x44, 0x49, 0x41, 0x47, 0x4f, 0x4e, 0x41, 0x4c, 0x5f, 0x4d, 0x4f,
+	0x56, 0x45, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x41, 0x4c, 0x57, 0x41, 0x59, 0x53, 0x10, 0x01, 0x12,
+	0x1b, 0x0a, 0x17, 0x44, 0x49, 0x41, 0x47, 0x4f, 0x4e, 0x41, 0x4c, 0x5f, 0x4d, 0x4f, 0x56, 0x45,
+	0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4e, 0x45, 0x56, 0x45, 0x52, 0x10, 0x02, 0x12, 0x2d, 0x0a, 0x29,
+	0x44, 0x49, 0x41, 0x47, 0x4f, 0x4e, 0x41, 0x4c, 0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x4d, 0x45, 0x4e,
+	0x54, 0x5f, 0x49, 0x46, 0x5f, 0x41, 0x54, 0x5f, 0x4d, 0x4f, 0x53, 0x54, 0x5f, 0x4f, 0x4e, 0x45,
+	0x5f, 0x4f, 0x42, 0x53, 0x54, 0x41, 0x43, 0x4c, 0x45, 0x10, 0x03, 0x12, 0x2c, 0x0a, 0x28, 0x44,
+	0x49, 0x41, 0x47, 0x4f, 0x4e, 0x41, 0x4c, 0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x4d, 0x45, 0x4e, 0x54,
+	0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x5f, 0x57, 0x48, 0x45, 0x4e, 0x5f, 0x4e, 0x4f, 0x5f, 0x4f, 0x42,
+	0x53, 0x54, 0x41, 0x43, 0x4c, 0x45, 0x53, 0x10, 0x04, 0x2a, 0x87, 0x01, 0x0a, 0x09, 0x48, 0x65,
+	0x75, 0x72, 0x69, 0x73, 0x74, 0x69, 0x63, 0x12, 0x19, 0x0a, 0x15, 0x48, 0x45, 0x55, 0x52, 0x49,
+	0x53, 0x54, 0x49, 0x43, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x48, 0x45, 0x55, 0x52, 0x49, 0x53, 0x54, 0x49, 0x43, 0x5f,
+	0x4d, 0x41, 0x4e, 0x48, 0x41, 0x54, 0x54, 0x41, 0x4e, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x48,
+	0x45, 0x55, 0x52, 0x49, 0x53, 0x54, 0x49, 0x43, 0x5f, 0x45, 0x55, 0x43, 0x4c, 0x49, 0x44, 0x45,
+	0x41, 0x4e, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x48, 0x45, 0x55, 0x52, 0x49, 0x53, 0x54, 0x49,
+	0x43, 0x5f, 0x4f, 0x43, 0x54, 0x49, 0x4c, 0x45, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x48, 0x45,
+	0x55, 0x52, 0x49, 0x53, 0x54, 0x49, 0x43, 0x5f, 0x43, 0x48, 0x45, 0x42, 0x59, 0x53, 0x48, 0x45,
+	0x56, 0x10, 0x04, 0x2a, 0x9b, 0x01, 0x0a, 0x0b, 0x54, 0x69, 0x65, 0x42, 0x72, 0x65, 0x61, 0x6b,
+	0x69, 0x6e, 0x67, 0x12, 0x15, 0x0a, 0x11, 0x54, 0x49, 0x45, 0x5f, 0x42, 0x52, 0x45, 0x41, 0x4b,
+	0x49, 0x4e, 0x47, 0x5f, 0x46, 0x49, 0x46, 0x4f, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x54, 0x49,
+	0x45, 0x5f, 0x42, 0x52, 0x45, 0x41, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x4c, 0x49, 0x46, 0x4f, 0x10,
+	0x01, 0x12, 0x20, 0x0a, 0x1c, 0x54, 0x49, 0x45, 0x5f, 0x42, 0x52, 0x45, 0x41, 0x4b, 0x49, 0x4e,
+	0x47, 0x5f, 0x50, 0x52, 0x45, 0x46, 0x45, 0x52, 0x5f, 0x48, 0x49, 0x47, 0x48, 0x45, 0x52, 0x5f,
+	0x47, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x54, 0x49, 0x45, 0x5f, 0x42, 0x52, 0x45, 0x41, 0x4b,
+	0x49, 0x4e, 0x47, 0x5f, 0x43, 0x52, 0x4f, 0x53, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x44, 0x55, 0x43,
+	0x54, 0x10, 0x03, 0x12, 0x1c, 0x0a, 0x18, 0x54, 0x49, 0x45, 0x5f, 0x42, 0x52, 0x45, 0x41, 0x4b,
+	0x49, 0x4e, 0x47, 0x5f, 0x46, 0x45, 0x57, 0x45, 0x52, 0x5f, 0x54, 0x55, 0x52, 0x4e, 0x53, 0x10,
+	0x04, 0x42, 0x13, 0x5a, 0x11, 0x67, 0x6f, 0x2d, 0x50, 0x61, 0x74, 0x68, 0x46, 0x69, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pathfinding_proto_rawDescOnce sync.Once
+	file_pathfinding_proto_rawDescData = file_pathfinding_proto_rawDesc
+)
+
+func file_pathfinding_proto_rawDescGZIP() []byte {
+	file_pathfinding_proto_rawDescOnce.Do(func() {
+		file_pathfinding_proto_rawDescData = protoimpl.X.CompressGZIP(file_pathfinding_proto_rawDescData)
+	})
+	return file_pathfinding_proto_rawDescData
+}
+
+var file_pathfinding_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_pathfinding_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_pathfinding_proto_goTypes = []interface{}{
+	(DiagonalMovement)(0), // 0: pathfinding.v1.DiagonalMovement
+	(Heuristic)(0),        // 1: pathfinding.v1.Heuristic
+	(TieBreaking)(0),      // 2: pathfinding.v1.TieBreaking
+	(*Grid)(nil),          // 3: pathfinding.v1.Grid
+	(*Opt)(nil),           // 4: pathfinding.v1.Opt
+	(*Point)(nil),         // 5: pathfinding.v1.Point
+	(*PathRequest)(nil),   // 6: pathfinding.v1.PathRequest
+	(*SearchStats)(nil),   // 7: pathfinding.v1.SearchStats
+	(*PathResult)(nil),    // 8: pathfinding.v1.PathResult
+}
+var file_pathfinding_proto_depIdxs = []int32{
+	0, // 0: pathfinding.v1.Opt.diagonal_movement:type_name -> pathfinding.v1.DiagonalMovement
+	1, // 1: pathfinding.v1.Opt.heuristic:type_name -> pathfinding.v1.Heuristic
+	2, // 2: pathfinding.v1.Opt.tie_breaking:type_name -> pathfinding.v1.TieBreaking
+	3, // 3: pathfinding.v1.PathRequest.grid:type_name -> pathfinding.v1.Grid
+	5, // 4: pathfinding.v1.PathRequest.start:type_name -> pathfinding.v1.Point
+	5, // 5: pathfinding.v1.PathRequest.end:type_name -> pathfinding.v1.Point
+	4, // 6: pathfinding.v1.PathRequest.opt:type_name -> pathfinding.v1.Opt
+	7, // 7: pathfinding.v1.PathResult.stats:type_name -> pathfinding.v1.SearchStats
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_pathfinding_proto_init() }
+func file_pathfinding_proto_init() {
+	if File_pathfinding_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pathfinding_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Grid); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pathfinding_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Opt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pathfinding_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Point); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pathfinding_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pathfinding_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pathfinding_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PathResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pathfinding_proto_msgTypes[3].OneofWrappers = []interface{}{
+		(*PathRequest_Grid)(nil),
+		(*PathRequest_GridName)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pathfinding_proto_rawDesc,
+			NumEnums:      3,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pathfinding_proto_goTypes,
+		DependencyIndexes: file_pathfinding_proto_depIdxs,
+		EnumInfos:         file_pathfinding_proto_enumTypes,
+		MessageInfos:      file_pathfinding_proto_msgTypes,
+	}.Build()
+	File_pathfinding_proto = out.File
+	file_pathfinding_proto_rawDesc = nil
+	file_pathfinding_proto_goTypes = nil
+	file_pathfinding_proto_depIdxs = nil
+}
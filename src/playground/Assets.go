@@ -0,0 +1,369 @@
+package playground
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+type asset struct {
+	contentType string
+	body        string
+}
+
+/**
+ * Static files of the playground by path, kept in the binary so that it
+ * runs offline.
+ */
+var assets = map[string]asset{
+	"/":           {"text/html; charset=utf-8", indexHTML},
+	"/index.html": {"text/html; charset=utf-8", indexHTML},
+	"/app.js":     {"application/javascript; charset=utf-8", appJS},
+	"/style.css":  {"text/css; charset=utf-8", styleCSS},
+}
+
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>PathFinding playground</title>
+<link rel="stylesheet" href="/style.css">
+</head>
+<body>
+<header>
+  <label>Size <input id="width" type="number" min="2" value="40"> x <input id="height" type="number" min="2" value="25"></label>
+  <label>Diagonal <select id="diagonal"></select></label>
+  <label>Heuristic <select id="heuristic"><option value="">default</option></select></label>
+  <label>Weight <input id="weight" type="number" min="0" step="0.1" value="0"></label>
+  <label>Ties <select id="tie"><option value="">default</option></select></label>
+  <label>Speed <input id="speed" type="range" min="1" max="200" value="10"></label>
+  <button id="run">Run</button>
+  <button id="clearSearch">Clear search</button>
+  <button id="clearWalls">Clear walls</button>
+  <div id="finders"></div>
+</header>
+<p class="help">Drag the green start and the red goal, click or drag elsewhere to draw and erase walls.</p>
+<main id="panels"></main>
+<script src="/app.js"></script>
+</body>
+</html>
+`
+
+const styleCSS = `body { font-family: sans-serif; margin: 1em; background: #f4f4f4; }
+header { display: flex; flex-wrap: wrap; gap: 0.8em; align-items: center; }
+header input[type=number] { width: 4em; }
+#finders label { margin-right: 0.6em; }
+.help { color: #666; font-size: 0.9em; }
+main { display: flex; flex-wrap: wrap; gap: 1em; }
+.panel { background: #fff; padding: 0.5em; box-shadow: 0 1px 3px #aaa; }
+.panel h2 { font-size: 1em; margin: 0 0 0.4em; }
+.panel canvas { display: block; cursor: crosshair; }
+.panel .stats { font-size: 0.85em; color: #333; min-height: 1.2em; margin-top: 0.4em; }
+.panel .error { color: #c00; }
+`
+
+const appJS = `"use strict";
+(function () {
+  var COLORS = { walkable: "#ffffff", wall: "#404040", opened: "#98fb98", closed: "#afeeee",
+    start: "#20a020", end: "#e02020", path: "#e0a000", line: "#e8e8e8" };
+  var W, H, walls, start, end, cell;
+  var panels = [];
+  var running = null;
+  var $ = function (id) { return document.getElementById(id); };
+
+  function resize() {
+    W = Math.max(2, parseInt($("width").value, 10) || 40);
+    H = Math.max(2, parseInt($("height").value, 10) || 25);
+    walls = new Uint8Array(W * H);
+    start = { x: 1, y: Math.floor(H / 2) };
+    end = { x: W - 2, y: Math.floor(H / 2) };
+    buildPanels();
+  }
+
+  function selectedFinders() {
+    var names = [];
+    var boxes = $("finders").querySelectorAll("input");
+    for (var i = 0; i < boxes.length; i++) {
+      if (boxes[i].checked) names.push(boxes[i].value);
+    }
+    return names;
+  }
+
+  function buildPanels() {
+    stop();
+    var names = selectedFinders();
+    var width = Math.max(300, (window.innerWidth - 60) / Math.min(Math.max(names.length, 1), 3));
+    cell = Math.max(3, Math.floor(Math.min(width / W, 600 / H)));
+    $("panels").innerHTML = "";
+    panels = names.map(function (name) {
+      var div = document.createElement("div");
+      div.className = "panel";
+      var title = document.createElement("h2");
+      title.textContent = name;
+      var canvas = document.createElement("canvas");
+      canvas.width = W * cell;
+      canvas.height = H * cell;
+      var stats = document.createElement("div");
+      stats.className = "stats";
+      div.appendChild(title);
+      div.appendChild(canvas);
+      div.appendChild(stats);
+      $("panels").appendChild(div);
+      var panel = { name: name, canvas: canvas, ctx: canvas.getContext("2d"), stats: stats };
+      clearPanel(panel);
+      listen(canvas);
+      return panel;
+    });
+    drawAll();
+  }
+
+  function clearPanel(panel) {
+    panel.events = [];
+    panel.cursor = 0;
+    panel.state = new Uint8Array(W * H);
+    panel.result = null;
+    panel.shown = false;
+    panel.error = null;
+    panel.stats.textContent = "";
+    panel.stats.className = "stats";
+  }
+
+  function fill(panel, x, y, color) {
+    panel.ctx.fillStyle = color;
+    panel.ctx.fillRect(x * cell, y * cell, cell, cell);
+    if (cell > 4) {
+      panel.ctx.strokeStyle = COLORS.line;
+      panel.ctx.strokeRect(x * cell + 0.5, y * cell + 0.5, cell - 1, cell - 1);
+    }
+  }
+
+  function drawCell(panel, x, y) {
+    var id = y * W + x;
+    var color = COLORS.walkable;
+    if (x === start.x && y === start.y) color = COLORS.start;
+    else if (x === end.x && y === end.y) color = COLORS.end;
+    else if (walls[id]) color = COLORS.wall;
+    else if (panel.state[id] === 2) color = COLORS.closed;
+    else if (panel.state[id] === 1) color = COLORS.opened;
+    fill(panel, x, y, color);
+  }
+
+  function drawPanel(panel) {
+    for (var y = 0; y < H; y++) {
+      for (var x = 0; x < W; x++) drawCell(panel, x, y);
+    }
+    if (panel.result && panel.cursor >= panel.events.length) drawPath(panel);
+  }
+
+  function drawAll() {
+    panels.forEach(drawPanel);
+  }
+
+  function drawPath(panel) {
+    var path = panel.result.path || [];
+    if (path.length < 2) return;
+    var ctx = panel.ctx;
+    ctx.strokeStyle = COLORS.path;
+    ctx.lineWidth = Math.max(2, cell / 3);
+    ctx.lineJoin = "round";
+    ctx.beginPath();
+    path.forEach(function (p, i) {
+      var px = (p[0] + 0.5) * cell, py = (p[1] + 0.5) * cell;
+      if (i === 0) ctx.moveTo(px, py); else ctx.lineTo(px, py);
+    });
+    ctx.stroke();
+    ctx.lineWidth = 1;
+  }
+
+  function showResult(panel) {
+    var r = panel.result;
+    var s = r.stats;
+    panel.stats.textContent = (r.path.length ? r.path.length + " nodes, cost " + r.cost.toFixed(3) : "no path") +
+      ", opened " + s.opened + ", closed " + s.closed + ", " + s.elapsedMicros + " µs";
+  }
+
+  // editing: drag the endpoints, or paint walls with the opposite of the
+  // first cell pressed.
+  var dragging = null;
+  window.addEventListener("mouseup", function () { dragging = null; });
+
+  function listen(canvas) {
+    function at(e) {
+      var rect = canvas.getBoundingClientRect();
+      var x = Math.floor((e.clientX - rect.left) / cell), y = Math.floor((e.clientY - rect.top) / cell);
+      return x >= 0 && x < W && y >= 0 && y < H ? { x: x, y: y } : null;
+    }
+    function apply(p) {
+      if (!p) return;
+      var id = p.y * W + p.x;
+      if (dragging === "start" || dragging === "end") {
+        var other = dragging === "start" ? end : start;
+        var self = dragging === "start" ? start : end;
+        if (walls[id] || (p.x === other.x && p.y === other.y) || (p.x === self.x && p.y === self.y)) return;
+        if (dragging === "start") start = p; else end = p;
+      } else {
+        if ((p.x === start.x && p.y === start.y) || (p.x === end.x && p.y === end.y) || walls[id] === dragging) return;
+        walls[id] = dragging;
+      }
+      clearSearch();
+    }
+    canvas.addEventListener("mousedown", function (e) {
+      var p = at(e);
+      if (!p) return;
+      if (p.x === start.x && p.y === start.y) dragging = "start";
+      else if (p.x === end.x && p.y === end.y) dragging = "end";
+      else dragging = walls[p.y * W + p.x] ? 0 : 1;
+      apply(p);
+    });
+    canvas.addEventListener("mousemove", function (e) {
+      if (dragging !== null) apply(at(e));
+    });
+  }
+
+  function stop() {
+    if (running) running.abort();
+    running = null;
+  }
+
+  function clearSearch() {
+    stop();
+    panels.forEach(clearPanel);
+    drawAll();
+  }
+
+  function request() {
+    var matrix = [];
+    for (var y = 0; y < H; y++) {
+      matrix.push(Array.prototype.slice.call(walls.subarray(y * W, (y + 1) * W)));
+    }
+    var options = { diagonalMovement: $("diagonal").value };
+    if ($("heuristic").value) options.heuristic = $("heuristic").value;
+    var weight = parseFloat($("weight").value);
+    if (weight > 0) options.weight = weight;
+    if ($("tie").value) options.tieBreaking = $("tie").value;
+    return {
+      grid: { width: W, height: H, matrix: matrix },
+      startX: start.x, startY: start.y, endX: end.x, endY: end.y,
+      finders: panels.map(function (p) { return p.name; }),
+      options: options
+    };
+  }
+
+  function dispatch(event, data) {
+    var panel = panels[data.finder];
+    if (!panel) return;
+    if (event === "trace") {
+      Array.prototype.push.apply(panel.events, data.events);
+    } else if (event === "result") {
+      panel.result = data;
+    } else if (event === "failure") {
+      panel.error = data.error;
+      panel.stats.textContent = data.error;
+      panel.stats.className = "stats error";
+    }
+  }
+
+  // the search answers with server-sent events, read from the fetch
+  // stream since EventSource cannot post.
+  function run() {
+    clearSearch();
+    if (!panels.length) return;
+    var controller = window.AbortController ? new AbortController() : null;
+    running = controller || { abort: function () {} };
+    fetch("/search", { method: "POST", body: JSON.stringify(request()), signal: controller && controller.signal })
+      .then(function (res) {
+        if (!res.ok) return res.json().then(function (body) { throw new Error(body.error); });
+        var reader = res.body.getReader();
+        var decoder = new TextDecoder();
+        var buffer = "";
+        function pump() {
+          return reader.read().then(function (chunk) {
+            if (chunk.done) return;
+            buffer += decoder.decode(chunk.value, { stream: true });
+            var messages = buffer.split("\n\n");
+            buffer = messages.pop();
+            messages.forEach(function (message) {
+              var event = "message", data = "";
+              message.split("\n").forEach(function (line) {
+                if (line.indexOf("event: ") === 0) event = line.slice(7);
+                else if (line.indexOf("data: ") === 0) data += line.slice(6);
+              });
+              if (data) dispatch(event, JSON.parse(data));
+            });
+            return pump();
+          });
+        }
+        return pump();
+      })
+      .catch(function (err) {
+        if (err.name === "AbortError") return;
+        panels.forEach(function (panel) {
+          panel.stats.textContent = err.message;
+          panel.stats.className = "stats error";
+        });
+      });
+  }
+
+  // replays every panel side by side, speed events per frame each.
+  function animate() {
+    var speed = parseInt($("speed").value, 10);
+    panels.forEach(function (panel) {
+      if (panel.cursor >= panel.events.length) return;
+      var to = Math.min(panel.events.length, panel.cursor + speed);
+      for (; panel.cursor < to; panel.cursor++) {
+        var e = panel.events[panel.cursor];
+        var id = e[1];
+        if (panel.state[id] !== 2) panel.state[id] = e[0] ? 2 : 1;
+        drawCell(panel, id % W, Math.floor(id / W));
+      }
+    });
+    // the result follows the last trace event of its finder.
+    panels.forEach(function (panel) {
+      if (panel.result && !panel.shown && panel.cursor >= panel.events.length) {
+        drawPath(panel);
+        showResult(panel);
+        panel.shown = true;
+      }
+    });
+    window.requestAnimationFrame(animate);
+  }
+
+  fetch("/config").then(function (res) { return res.json(); }).then(function (config) {
+    config.diagonals.forEach(function (name) {
+      var option = document.createElement("option");
+      option.value = option.textContent = name;
+      $("diagonal").appendChild(option);
+    });
+    config.heuristics.forEach(function (name) {
+      var option = document.createElement("option");
+      option.value = option.textContent = name;
+      $("heuristic").appendChild(option);
+    });
+    config.ties.forEach(function (name) {
+      var option = document.createElement("option");
+      option.value = option.textContent = name;
+      $("tie").appendChild(option);
+    });
+    config.finders.forEach(function (name) {
+      var label = document.createElement("label");
+      var box = document.createElement("input");
+      box.type = "checkbox";
+      box.value = name;
+      box.checked = name === "astar" || name === "biastar";
+      box.addEventListener("change", function () {
+        if (selectedFinders().length > config.maxFinders) box.checked = false;
+        buildPanels();
+      });
+      label.appendChild(box);
+      label.appendChild(document.createTextNode(" " + name));
+      $("finders").appendChild(label);
+    });
+    $("width").addEventListener("change", resize);
+    $("height").addEventListener("change", resize);
+    $("run").addEventListener("click", run);
+    $("clearSearch").addEventListener("click", clearSearch);
+    $("clearWalls").addEventListener("click", function () { walls = new Uint8Array(W * H); clearSearch(); });
+    ["diagonal", "heuristic", "weight", "tie"].forEach(function (id) { $(id).addEventListener("change", clearSearch); });
+    resize();
+    window.requestAnimationFrame(animate);
+  });
+})();
+`
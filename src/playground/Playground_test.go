@@ -0,0 +1,171 @@
+package playground
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-PathFinding/core"
+	"go-PathFinding/service"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+type sseMessage struct {
+	event string
+	data  map[string]interface{}
+}
+
+// readEvents parses a server-sent event stream as the page script does.
+func readEvents(t *testing.T, resp *http.Response) []sseMessage {
+	var messages []sseMessage
+	var current sseMessage
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.event = line[7:]
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(line[6:]), &current.data); err != nil {
+				t.Fatal(err)
+			}
+		case line == "":
+			messages = append(messages, current)
+			current = sseMessage{}
+		}
+	}
+	return messages
+}
+
+func TestPlayground(t *testing.T) {
+	handler := NewPlayground()
+	handler.BatchSize = 4
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for path, want := range map[string]string{"/": "/app.js", "/app.js": "/search", "/style.css": ".panel"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), want) {
+			t.Fatalf("GET %s: %d", path, resp.StatusCode)
+		}
+	}
+	if resp, _ := http.Get(server.URL + "/nope.js"); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown asset served")
+	}
+
+	var config struct {
+		Finders   []string `json:"finders"`
+		Diagonals []string `json:"diagonals"`
+	}
+	resp, err := http.Get(server.URL + "/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.NewDecoder(resp.Body).Decode(&config)
+	resp.Body.Close()
+	if len(config.Finders) < 3 || len(config.Diagonals) != 4 {
+		t.Fatalf("config %+v", config)
+	}
+
+	req := SearchRequest{
+		Grid:    service.GridBody{Width: 4, Height: 3, Matrix: core.DoubleInt32{{0, 0, 0, 0}, {1, 1, 1, 0}, {0, 0, 0, 0}}},
+		StartX:  0,
+		StartY:  0,
+		EndX:    0,
+		EndY:    2,
+		Finders: []string{"astar", "nope", "biastar"},
+		Options: service.Options{"diagonalMovement": "never"},
+	}
+	payload, _ := json.Marshal(req)
+	if resp, err = http.Post(server.URL+"/search", "application/json", bytes.NewReader(payload)); err != nil {
+		t.Fatal(err)
+	}
+	messages := readEvents(t, resp)
+	resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("content type %s", resp.Header.Get("Content-Type"))
+	}
+
+	var events = map[float64]int{}
+	var results = map[float64]map[string]interface{}{}
+	var names []string
+	for _, m := range messages {
+		finder, _ := m.data["finder"].(float64)
+		switch m.event {
+		case "finder":
+			names = append(names, m.data["name"].(string))
+		case "trace":
+			batch := m.data["events"].([]interface{})
+			if len(batch) > handler.BatchSize {
+				t.Fatalf("batch of %d events", len(batch))
+			}
+			events[finder] += len(batch)
+		case "result":
+			results[finder] = m.data
+		case "failure":
+			if finder != 1 {
+				t.Fatalf("finder %v failed: %v", finder, m.data["error"])
+			}
+		}
+	}
+	akLog.FmtPrintln(names, events)
+	if last := messages[len(messages)-1]; last.event != "done" || strings.Join(names, ",") != "astar,nope,biastar" {
+		t.Fatalf("stream ended with %q after %v", last.event, names)
+	}
+	for _, finder := range []float64{0, 2} {
+		res := results[finder]
+		stats := res["stats"].(map[string]interface{})
+		if len(res["path"].([]interface{})) != 9 || stats["opened"].(float64)+stats["closed"].(float64) != float64(events[finder]) {
+			t.Fatalf("finder %v: %v after %d trace events", finder, res, events[finder])
+		}
+	}
+
+	for _, bad := range []SearchRequest{
+		{Grid: req.Grid, Finders: []string{"astar"}, EndX: 4},
+		{Grid: service.GridBody{Width: 300, Height: 300}, Finders: []string{"astar"}},
+		{Grid: req.Grid},
+		{Grid: req.Grid, Finders: []string{"astar"}, Options: service.Options{"heuristic": "nope"}},
+		{Grid: req.Grid, Finders: []string{"astar", "dijkstra"}, Options: service.Options{"weight": 0.5}},
+	} {
+		payload, _ := json.Marshal(bad)
+		if resp, err = http.Post(server.URL+"/search", "application/json", bytes.NewReader(payload)); err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%+v: status %d", bad, resp.StatusCode)
+		}
+	}
+
+	// options the schema of one finder lacks fail that finder only.
+	req.Finders = []string{"astar", "sipp"}
+	req.Options = service.Options{"tieBreaking": "lifo"}
+	payload, _ = json.Marshal(req)
+	if resp, err = http.Post(server.URL+"/search", "application/json", bytes.NewReader(payload)); err != nil {
+		t.Fatal(err)
+	}
+	messages = readEvents(t, resp)
+	resp.Body.Close()
+	var failures []string
+	for _, m := range messages {
+		if m.event == "failure" {
+			failures = append(failures, fmt.Sprint(m.data["finder"], ": ", m.data["error"]))
+		}
+	}
+	if len(failures) != 1 || !strings.HasPrefix(failures[0], "1: ") || !strings.Contains(failures[0], "unknown option tieBreaking") {
+		t.Fatalf("failures %q", failures)
+	}
+}
@@ -0,0 +1,269 @@
+package playground
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	_ "go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/BiAStarFinder"
+	_ "go-PathFinding/finders/DijkstraFinder"
+	_ "go-PathFinding/finders/JumpPointFinder"
+	_ "go-PathFinding/finders/SIPPFinder"
+	"go-PathFinding/service"
+)
+
+/**
+ * Local web playground comparing finders on a grid drawn in the browser.
+ * The page and its script are served from the binary, without any CDN.
+ *
+ *   GET  /                page, script and style
+ *   GET  /config          finders, diagonal movements, heuristics and tie breakings
+ *   POST /search          SearchRequest -> server-sent events
+ *
+ * Every finder of a search is run with a recorded trace, then streamed as
+ * events, in the order of the request:
+ *
+ *   finder   {"finder": i, "name": "astar"}
+ *   trace    {"finder": i, "events": [[close, id, g, h], ...]}, in batches
+ *   result   {"finder": i, "path": [[x, y], ...], "cost": ..., "stats": {...}}
+ *   failure  {"finder": i, "error": "..."}
+ *   done     {}
+ */
+type TPlayground struct {
+	MaxCells   int // largest grid accepted
+	MaxFinders int // finders compared at once
+	BatchSize  int // trace events per message
+
+	mux *http.ServeMux
+}
+
+/**
+ * A grid, its endpoints and the finders to compare on it.
+ */
+type SearchRequest struct {
+	Grid    service.GridBody `json:"grid"`
+	StartX  int              `json:"startX"`
+	StartY  int              `json:"startY"`
+	EndX    int              `json:"endX"`
+	EndY    int              `json:"endY"`
+	Finders []string         `json:"finders"`
+	Options service.Options  `json:"options"`
+}
+
+type finderEvent struct {
+	Finder int    `json:"finder"`
+	Name   string `json:"name"`
+}
+
+type traceEvent struct {
+	Finder int          `json:"finder"`
+	Events [][4]float64 `json:"events"`
+}
+
+type resultEvent struct {
+	Finder int `json:"finder"`
+	service.PathResponse
+}
+
+type failureEvent struct {
+	Finder int    `json:"finder"`
+	Error  string `json:"error"`
+}
+
+/**
+ * @constructor
+ */
+func NewPlayground() (this *TPlayground) {
+	this = &TPlayground{
+		MaxCells:   200 * 200,
+		MaxFinders: 6,
+		BatchSize:  500,
+		mux:        http.NewServeMux(),
+	}
+	this.mux.HandleFunc("/", this.handleAsset)
+	this.mux.HandleFunc("/config", this.handleConfig)
+	this.mux.HandleFunc("/search", this.handleSearch)
+	return
+}
+
+func (this *TPlayground) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	this.mux.ServeHTTP(w, r)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf(format, args...)})
+}
+
+func (this *TPlayground) handleAsset(w http.ResponseWriter, r *http.Request) {
+	asset, ok := assets[r.URL.Path]
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such resource %s", r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	w.Header().Set("Content-Type", asset.contentType)
+	w.Write([]byte(asset.body))
+}
+
+func (this *TPlayground) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	var config = struct {
+		Finders    []string `json:"finders"`
+		Diagonals  []string `json:"diagonals"`
+		Heuristics []string `json:"heuristics"`
+		Ties       []string `json:"ties"`
+		MaxCells   int      `json:"maxCells"`
+		MaxFinders int      `json:"maxFinders"`
+	}{
+		Finders:    finders.Names(),
+		Heuristics: core.HeuristicNames(),
+		Ties:       core.TieBreakingNames(),
+		MaxCells:   this.MaxCells,
+		MaxFinders: this.MaxFinders,
+	}
+	for _, move := range []core.DiagonalMovement{core.Never, core.Always, core.IfAtMostOneObstacle, core.OnlyWhenNoObstacles} {
+		config.Diagonals = append(config.Diagonals, move.String())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+func (this *TPlayground) parseSearch(r *http.Request) (*SearchRequest, *core.TGrid, error) {
+	var req SearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, nil, fmt.Errorf("invalid search: %v", err)
+	}
+	body := req.Grid
+	if body.Width <= 0 || body.Height <= 0 || body.Width*body.Height > this.MaxCells {
+		return nil, nil, fmt.Errorf("grid of %dx%d cells, at most %d accepted", body.Width, body.Height, this.MaxCells)
+	}
+	if len(body.Matrix) != body.Height {
+		return nil, nil, fmt.Errorf("matrix has %d rows, want %d", len(body.Matrix), body.Height)
+	}
+	for y, row := range body.Matrix {
+		if len(row) != body.Width {
+			return nil, nil, fmt.Errorf("matrix row %d has %d cells, want %d", y, len(row), body.Width)
+		}
+	}
+	for _, p := range [][2]int{{req.StartX, req.StartY}, {req.EndX, req.EndY}} {
+		if p[0] < 0 || p[0] >= body.Width || p[1] < 0 || p[1] >= body.Height {
+			return nil, nil, fmt.Errorf("point (%d, %d) outside the grid", p[0], p[1])
+		}
+	}
+	if len(req.Finders) == 0 || len(req.Finders) > this.MaxFinders {
+		return nil, nil, fmt.Errorf("%d finders, from 1 to %d accepted", len(req.Finders), this.MaxFinders)
+	}
+	// a finder whose schema rejects the options fails alone, as an unknown
+	// finder does; options no finder accepts fail the request.
+	var accepted bool
+	var rejected error
+	for _, name := range req.Finders {
+		if _, err := finders.Schema(name); err != nil {
+			continue
+		}
+		if _, err := finders.Build(name, req.Options); err != nil {
+			rejected = err
+		} else {
+			accepted = true
+		}
+	}
+	if !accepted && rejected != nil {
+		return nil, nil, rejected
+	}
+	return &req, core.Grid(body.Width, body.Height, body.Matrix), nil
+}
+
+func (this *TPlayground) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	req, grid, err := this.parseSearch(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event string, data interface{}) bool {
+		payload, _ := json.Marshal(data)
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return r.Context().Err() == nil
+	}
+
+	start, end := grid.NodeID(req.StartX, req.StartY), grid.NodeID(req.EndX, req.EndY)
+	for i, name := range req.Finders {
+		if !send("finder", finderEvent{Finder: i, Name: name}) {
+			return
+		}
+		trace := &core.TSearchTrace{}
+		finder, err := finders.BuildWith(name, &core.Opt{Tracer: trace}, req.Options)
+		if err != nil {
+			if !send("failure", failureEvent{Finder: i, Error: err.Error()}) {
+				return
+			}
+			continue
+		}
+		begin := time.Now()
+		path := finder.FindPath(start, end, grid)
+		elapsed := time.Since(begin)
+
+		batch := this.BatchSize
+		if batch < 1 {
+			batch = 1
+		}
+		var res = resultEvent{Finder: i}
+		for from := 0; from < len(trace.Events); from += batch {
+			to := from + batch
+			if to > len(trace.Events) {
+				to = len(trace.Events)
+			}
+			var msg = traceEvent{Finder: i, Events: make([][4]float64, 0, to-from)}
+			for _, e := range trace.Events[from:to] {
+				var closed float64
+				if e.Close {
+					closed = 1
+					res.Stats.Closed++
+				} else {
+					res.Stats.Opened++
+				}
+				msg.Events = append(msg.Events, [4]float64{closed, float64(e.ID), e.G, e.H})
+			}
+			if !send("trace", msg) {
+				return
+			}
+		}
+		res.Path = grid.PathCoords(path)
+		res.Length = core.PathLength(res.Path)
+		res.Cost = core.PathCost(grid, path)
+		res.Stats.ElapsedMicros = elapsed.Nanoseconds() / 1000
+		if !send("result", res) {
+			return
+		}
+	}
+	send("done", struct{}{})
+}
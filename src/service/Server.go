@@ -0,0 +1,345 @@
+package service
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	_ "go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/BiAStarFinder"
+	_ "go-PathFinding/finders/DijkstraFinder"
+	_ "go-PathFinding/finders/JumpPointFinder"
+	_ "go-PathFinding/finders/SIPPFinder"
+)
+
+/**
+ * JSON path-finding service over named grids.
+ *
+ *   GET    /finders                 names of the registered finders
+ *   PUT    /grids/{name}            upload (or replace) a grid, GridBody
+ *   GET    /grids/{name}            download it, GridBody
+ *   DELETE /grids/{name}            forget it
+ *   PATCH  /grids/{name}/cells      set walkability, []CellPatch
+ *   POST   /grids/{name}/path       search, PathRequest -> PathResponse
+ *
+ * Errors are answered as {"error": "..."} with a 4xx/5xx status.
+ * Searches stop at the request deadline, and at most MaxSearches run at
+ * once; the others wait for a slot until their deadline.
+ */
+type TServer struct {
+	Timeout     time.Duration // per request, 0 for none
+	MaxCells    int           // largest grid accepted, 0 for DefaultMaxCells
+	MaxSearches int           // searches running at once, 0 for the number of CPUs
+
+	mu       sync.RWMutex
+	grids    map[string]*namedGrid
+	mux      *http.ServeMux
+	slots    chan struct{}
+	slotOnce sync.Once
+}
+
+/**
+ * Largest grid of a server whose MaxCells is 0.
+ */
+const DefaultMaxCells = 4 << 20
+
+// bytes of JSON a grid cell may take, "0, " with some room, and of a body
+// that is not a grid.
+const (
+	bytesPerCell = 8
+	maxBodyBytes = 1 << 20
+)
+
+type namedGrid struct {
+	mu   sync.RWMutex
+	grid *core.TGrid
+}
+
+/**
+ * @constructor
+ * @param {Duration} timeout - Deadline of each request, 0 for none.
+ */
+func NewServer(timeout time.Duration) (this *TServer) {
+	this = &TServer{
+		Timeout: timeout,
+		grids:   map[string]*namedGrid{},
+		mux:     http.NewServeMux(),
+	}
+	this.mux.HandleFunc("/finders", this.handleFinders)
+	this.mux.HandleFunc("/grids/", this.handleGrids)
+	return
+}
+
+func (this *TServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if this.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), this.Timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+	this.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, errorBody{Error: fmt.Sprintf(format, args...)})
+}
+
+func (this *TServer) handleFinders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	writeJSON(w, http.StatusOK, finders.Names())
+}
+
+func (this *TServer) handleGrids(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/grids/"), "/")
+	name := parts[0]
+	if name == "" || len(parts) > 2 {
+		writeError(w, http.StatusNotFound, "no such resource %s", r.URL.Path)
+		return
+	}
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodPut:
+		this.putGrid(w, r, name)
+	case action == "" && r.Method == http.MethodGet:
+		this.getGrid(w, r, name)
+	case action == "" && r.Method == http.MethodDelete:
+		this.deleteGrid(w, r, name)
+	case action == "cells" && r.Method == http.MethodPatch:
+		this.patchCells(w, r, name)
+	case action == "path" && r.Method == http.MethodPost:
+		this.findPath(w, r, name)
+	case action == "" || action == "cells" || action == "path":
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	default:
+		writeError(w, http.StatusNotFound, "no such resource %s", r.URL.Path)
+	}
+}
+
+func (this *TServer) maxCells() int {
+	if this.MaxCells > 0 {
+		return this.MaxCells
+	}
+	return DefaultMaxCells
+}
+
+// decodeBody decodes at most limit bytes of the request body into v.
+func decodeBody(w http.ResponseWriter, r *http.Request, limit int64, v interface{}) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, limit)).Decode(v)
+}
+
+// acquire waits for a search slot until the request deadline.
+func (this *TServer) acquire(r *http.Request) bool {
+	this.slotOnce.Do(func() {
+		slots := this.MaxSearches
+		if slots <= 0 {
+			slots = runtime.NumCPU()
+		}
+		this.slots = make(chan struct{}, slots)
+	})
+	select {
+	case this.slots <- struct{}{}:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func (this *TServer) release() {
+	<-this.slots
+}
+
+func (this *TServer) lookup(name string) *namedGrid {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return this.grids[name]
+}
+
+func (this *TServer) putGrid(w http.ResponseWriter, r *http.Request, name string) {
+	var body GridBody
+	maxCells := this.maxCells()
+	if err := decodeBody(w, r, int64(maxCells)*bytesPerCell+maxBodyBytes, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid grid: %v", err)
+		return
+	}
+	if body.Width <= 0 || body.Height <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid grid size %dx%d", body.Width, body.Height)
+		return
+	}
+	// no product, which could overflow.
+	if body.Width > maxCells/body.Height {
+		writeError(w, http.StatusRequestEntityTooLarge, "grid larger than %d cells", maxCells)
+		return
+	}
+	if body.Matrix != nil {
+		if len(body.Matrix) != body.Height {
+			writeError(w, http.StatusBadRequest, "matrix has %d rows, want %d", len(body.Matrix), body.Height)
+			return
+		}
+		for y, row := range body.Matrix {
+			if len(row) != body.Width {
+				writeError(w, http.StatusBadRequest, "matrix row %d has %d cells, want %d", y, len(row), body.Width)
+				return
+			}
+		}
+	}
+
+	grid := core.Grid(body.Width, body.Height, body.Matrix)
+	// built now so that concurrent searches only ever read it.
+	grid.Clearance()
+
+	this.mu.Lock()
+	_, replaced := this.grids[name]
+	this.grids[name] = &namedGrid{grid: grid}
+	this.mu.Unlock()
+
+	if replaced {
+		writeJSON(w, http.StatusOK, body)
+	} else {
+		writeJSON(w, http.StatusCreated, body)
+	}
+}
+
+func (this *TServer) getGrid(w http.ResponseWriter, r *http.Request, name string) {
+	entry := this.lookup(name)
+	if entry == nil {
+		writeError(w, http.StatusNotFound, "no grid %q", name)
+		return
+	}
+	entry.mu.RLock()
+	grid := entry.grid
+	body := GridBody{Width: grid.Width(), Height: grid.Height(), Matrix: make(core.DoubleInt32, grid.Height())}
+	for y := 0; y < grid.Height(); y++ {
+		body.Matrix[y] = make(core.ArrayInt32, grid.Width())
+		for x := 0; x < grid.Width(); x++ {
+			if !grid.IsWalkableAt(x, y) {
+				body.Matrix[y][x] = 1
+			}
+		}
+	}
+	entry.mu.RUnlock()
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (this *TServer) deleteGrid(w http.ResponseWriter, r *http.Request, name string) {
+	this.mu.Lock()
+	_, ok := this.grids[name]
+	delete(this.grids, name)
+	this.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "no grid %q", name)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (this *TServer) patchCells(w http.ResponseWriter, r *http.Request, name string) {
+	entry := this.lookup(name)
+	if entry == nil {
+		writeError(w, http.StatusNotFound, "no grid %q", name)
+		return
+	}
+	var cells []CellPatch
+	if err := decodeBody(w, r, int64(this.maxCells())*bytesPerCell+maxBodyBytes, &cells); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid cells: %v", err)
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	grid := entry.grid
+	// all or nothing: check every cell before touching the grid.
+	for _, cell := range cells {
+		if cell.X < 0 || cell.X >= grid.Width() || cell.Y < 0 || cell.Y >= grid.Height() {
+			writeError(w, http.StatusBadRequest, "cell (%d, %d) outside the grid", cell.X, cell.Y)
+			return
+		}
+	}
+	for _, cell := range cells {
+		grid.SetWalkableAt(cell.X, cell.Y, cell.Walkable)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (this *TServer) findPath(w http.ResponseWriter, r *http.Request, name string) {
+	entry := this.lookup(name)
+	if entry == nil {
+		writeError(w, http.StatusNotFound, "no grid %q", name)
+		return
+	}
+	var req PathRequest
+	if err := decodeBody(w, r, maxBodyBytes, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid path request: %v", err)
+		return
+	}
+	if req.Finder == "" {
+		req.Finder = "astar"
+	}
+	// the size of a grid never changes, only its cells do.
+	width, height := entry.grid.Width(), entry.grid.Height()
+	for _, p := range [][2]int{{req.StartX, req.StartY}, {req.EndX, req.EndY}} {
+		if p[0] < 0 || p[0] >= width || p[1] < 0 || p[1] >= height {
+			writeError(w, http.StatusBadRequest, "point (%d, %d) outside the grid", p[0], p[1])
+			return
+		}
+	}
+	stats := &core.TSearchStats{}
+	// the search gives up at the request deadline, releasing the grid.
+	opt := &core.Opt{Tracer: stats, Done: r.Context().Done()}
+	finder, err := finders.BuildWith(req.Finder, opt, req.Options)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	if !this.acquire(r) {
+		writeError(w, http.StatusServiceUnavailable, "search aborted: %v", r.Context().Err())
+		return
+	}
+	defer this.release()
+	if err := r.Context().Err(); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "search aborted: %v", err)
+		return
+	}
+
+	entry.mu.RLock()
+	grid := entry.grid
+	var res PathResponse
+	begin := time.Now()
+	path := finder.FindPath(grid.NodeID(req.StartX, req.StartY), grid.NodeID(req.EndX, req.EndY), grid)
+	res.Stats.ElapsedMicros = time.Since(begin).Nanoseconds() / 1000
+	res.Path = grid.PathCoords(path)
+	res.Length = core.PathLength(res.Path)
+	res.Cost = core.PathCost(grid, path)
+	entry.mu.RUnlock()
+	res.Stats.Opened = stats.Opened
+	res.Stats.Closed = stats.Closed
+
+	if err := r.Context().Err(); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "search aborted: %v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
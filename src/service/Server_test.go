@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+func call(t *testing.T, server *httptest.Server, method, path string, body interface{}, out interface{}) int {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestServer(t *testing.T) {
+	server := httptest.NewServer(NewServer(time.Minute))
+	defer server.Close()
+
+	var names []string
+	if status := call(t, server, "GET", "/finders", nil, &names); status != http.StatusOK || len(names) == 0 {
+		t.Fatalf("GET /finders: %d %v", status, names)
+	}
+	akLog.FmtPrintln("finders: ", names)
+
+	grid := GridBody{Width: 5, Height: 3, Matrix: [][]int32{
+		{0, 0, 0, 0, 0},
+		{1, 1, 1, 1, 0},
+		{0, 0, 0, 0, 0},
+	}}
+	if status := call(t, server, "PUT", "/grids/maze", grid, nil); status != http.StatusCreated {
+		t.Fatalf("PUT grid: %d", status)
+	}
+
+	request := PathRequest{StartX: 0, StartY: 0, EndX: 0, EndY: 2}
+	for _, name := range names {
+		var res PathResponse
+		request.Finder = name
+		if status := call(t, server, "POST", "/grids/maze/path", request, &res); status != http.StatusOK {
+			t.Fatalf("POST path with %s: %d", name, status)
+		}
+		akLog.FmtPrintln(name, ": ", res)
+		if len(res.Path) != 11 || res.Cost != 10 || res.Stats.Closed == 0 {
+			t.Fatalf("%s: unexpected result %+v", name, res)
+		}
+	}
+
+	// open a shortcut; diagonal moves through it are not allowed around corners.
+	patch := []CellPatch{{X: 0, Y: 1, Walkable: true}}
+	if status := call(t, server, "PATCH", "/grids/maze/cells", patch, nil); status != http.StatusNoContent {
+		t.Fatalf("PATCH cells: %d", status)
+	}
+	var res PathResponse
+	request.Finder = ""
+	request.Options = Options{"diagonalMovement": "onlyWhenNoObstacles", "heuristic": "octile"}
+	if status := call(t, server, "POST", "/grids/maze/path", request, &res); status != http.StatusOK || len(res.Path) != 3 {
+		t.Fatalf("path after patch: %d %+v", status, res)
+	}
+
+	request.Finder = "sipp"
+	request.Options = Options{"horizon": 500}
+	if status := call(t, server, "POST", "/grids/maze/path", request, &res); status != http.StatusOK || len(res.Path) == 0 {
+		t.Fatalf("sipp path with a horizon: %d %+v", status, res)
+	}
+
+	var body GridBody
+	if status := call(t, server, "GET", "/grids/maze", nil, &body); status != http.StatusOK || body.Matrix[1][0] != 0 || body.Matrix[1][1] != 1 {
+		t.Fatalf("GET grid: %d %+v", status, body)
+	}
+
+	var failure errorBody
+	bad := []struct {
+		method, path string
+		body         interface{}
+		status       int
+	}{
+		{"POST", "/grids/none/path", request, http.StatusNotFound},
+		{"POST", "/grids/maze/path", PathRequest{Finder: "nope"}, http.StatusBadRequest},
+		{"POST", "/grids/maze/path", PathRequest{EndX: 9}, http.StatusBadRequest},
+		{"POST", "/grids/maze/path", PathRequest{Options: Options{"heuristic": "nope"}}, http.StatusBadRequest},
+		{"POST", "/grids/maze/path", PathRequest{Options: Options{"tieBreaking": "nope"}}, http.StatusBadRequest},
+		// checked by the schema of the finder.
+		{"POST", "/grids/maze/path", PathRequest{Options: Options{"weight": -1}}, http.StatusBadRequest},
+		{"POST", "/grids/maze/path", PathRequest{Options: Options{"agentSize": 1e10}}, http.StatusBadRequest},
+		{"POST", "/grids/maze/path", PathRequest{Options: Options{"horizon": 500}}, http.StatusBadRequest},
+		{"POST", "/grids/maze/path", PathRequest{Finder: "dijkstra", Options: Options{"heuristic": "octile"}}, http.StatusBadRequest},
+		{"PATCH", "/grids/maze/cells", []CellPatch{{X: -1}}, http.StatusBadRequest},
+		{"PUT", "/grids/bad", GridBody{Width: 2, Height: 1, Matrix: [][]int32{{0}}}, http.StatusBadRequest},
+		{"GET", "/grids/maze/path", nil, http.StatusMethodNotAllowed},
+	}
+	for _, c := range bad {
+		failure.Error = ""
+		if status := call(t, server, c.method, c.path, c.body, &failure); status != c.status || failure.Error == "" {
+			t.Fatalf("%s %s: %d %q, expected %d", c.method, c.path, status, failure.Error, c.status)
+		}
+	}
+
+	if status := call(t, server, "DELETE", "/grids/maze", nil, nil); status != http.StatusNoContent {
+		t.Fatalf("DELETE grid: %d", status)
+	}
+	if status := call(t, server, "GET", "/grids/maze", nil, &failure); status != http.StatusNotFound {
+		t.Fatalf("GET deleted grid: %d", status)
+	}
+}
+
+func TestServerTimeout(t *testing.T) {
+	handler := NewServer(time.Nanosecond)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	if status := call(t, server, "PUT", "/grids/open", GridBody{Width: 300, Height: 300}, nil); status != http.StatusCreated {
+		t.Fatalf("PUT grid: %d", status)
+	}
+	var failure errorBody
+	request := PathRequest{EndX: 299, EndY: 299}
+	if status := call(t, server, "POST", "/grids/open/path", request, &failure); status != http.StatusServiceUnavailable {
+		t.Fatalf("POST path: %d, expected a timeout", status)
+	}
+	akLog.FmtPrintln("timeout: ", failure.Error)
+}
+
+func TestServerLimits(t *testing.T) {
+	server := httptest.NewServer(NewServer(time.Minute))
+	defer server.Close()
+
+	var failure errorBody
+	for _, body := range []json.RawMessage{
+		// the product of the sides wraps to 0 on 64 bits.
+		json.RawMessage(`{"width": 4294967296, "height": 4294967296}`),
+		// without a matrix nothing but the sides bound the grid.
+		json.RawMessage(`{"width": 100000, "height": 100000}`),
+	} {
+		failure.Error = ""
+		if status := call(t, server, "PUT", "/grids/huge", body, &failure); status < 400 || status >= 500 || failure.Error == "" {
+			t.Fatalf("PUT %s: %d %q", body, status, failure.Error)
+		}
+	}
+
+	// bodies are cut at the size of the largest grid.
+	huge := append([]byte(`{"width": 1, "height": 1, "matrix": [[0]], "pad": "`), bytes.Repeat([]byte("x"), DefaultMaxCells*bytesPerCell+maxBodyBytes)...)
+	huge = append(huge, `"}`...)
+	if status := call(t, server, "PUT", "/grids/huge", json.RawMessage(huge), &failure); status != http.StatusBadRequest {
+		t.Fatalf("PUT of %d bytes: %d", len(huge), status)
+	}
+}
+
+func TestServerCancel(t *testing.T) {
+	handler := NewServer(50 * time.Millisecond)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// the goal is walled in: the search would close all 4M cells.
+	if status := call(t, server, "PUT", "/grids/open", GridBody{Width: 2048, Height: 2048}, nil); status != http.StatusCreated {
+		t.Fatalf("PUT grid: %d", status)
+	}
+	walls := []CellPatch{{X: 2046, Y: 2047}, {X: 2047, Y: 2046}}
+	if status := call(t, server, "PATCH", "/grids/open/cells", walls, nil); status != http.StatusNoContent {
+		t.Fatalf("PATCH cells: %d", status)
+	}
+
+	begin := time.Now()
+	var failure errorBody
+	request := PathRequest{EndX: 2047, EndY: 2047}
+	if status := call(t, server, "POST", "/grids/open/path", request, &failure); status != http.StatusServiceUnavailable {
+		t.Fatalf("POST path: %d, expected a timeout", status)
+	}
+	// the aborted search no longer holds the grid.
+	if status := call(t, server, "PATCH", "/grids/open/cells", walls[:1], nil); status != http.StatusNoContent {
+		t.Fatalf("PATCH cells: %d", status)
+	}
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Fatalf("search stopped after %v", elapsed)
+	}
+	akLog.FmtPrintln("cancelled: ", failure.Error, time.Since(begin))
+}
@@ -0,0 +1,72 @@
+package service
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"go-PathFinding/core"
+)
+
+/**
+ * A grid as uploaded and returned by /grids/{name}.
+ * Matrix is indexed [y][x]; 0 is walkable, anything else blocked.
+ */
+type GridBody struct {
+	Width  int              `json:"width"`
+	Height int              `json:"height"`
+	Matrix core.DoubleInt32 `json:"matrix"`
+}
+
+/**
+ * One cell of a PATCH /grids/{name}/cells request.
+ */
+type CellPatch struct {
+	X        int  `json:"x"`
+	Y        int  `json:"y"`
+	Walkable bool `json:"walkable"`
+}
+
+/**
+ * Search options by name, as accepted by the schema of the finder, e.g.
+ * {"diagonalMovement": "onlyWhenNoObstacles", "heuristic": "octile",
+ * "weight": 1.5} or {"horizon": 500} for "sipp". Options left out
+ * default as in the finder constructors; unknown options and values out
+ * of range are errors of finders.Build.
+ */
+type Options map[string]interface{}
+
+/**
+ * Body of POST /grids/{name}/path. Finder defaults to "astar".
+ */
+type PathRequest struct {
+	Finder  string  `json:"finder,omitempty"`
+	StartX  int     `json:"startX"`
+	StartY  int     `json:"startY"`
+	EndX    int     `json:"endX"`
+	EndY    int     `json:"endY"`
+	Options Options `json:"options,omitempty"`
+}
+
+/**
+ * Counters of one search.
+ */
+type Stats struct {
+	Opened        int   `json:"opened"`
+	Closed        int   `json:"closed"`
+	ElapsedMicros int64 `json:"elapsedMicros"`
+}
+
+/**
+ * Answer to POST /grids/{name}/path. Path is empty when there is none.
+ */
+type PathResponse struct {
+	Path   core.DoubleInt32 `json:"path"`
+	Length float64          `json:"length"`
+	Cost   float64          `json:"cost"`
+	Stats  Stats            `json:"stats"`
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,122 @@
+package validate
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"go-PathFinding/core"
+	"go-PathFinding/finders"
+	_ "go-PathFinding/finders/AStarFinder"
+	_ "go-PathFinding/finders/BiAStarFinder"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+// . . . .
+// . # . .
+// . . # .
+var validateMatrix = core.DoubleInt32{
+	{0, 0, 0, 0},
+	{0, 1, 0, 0},
+	{0, 0, 1, 0},
+}
+
+var validateMoves = []core.DiagonalMovement{core.Always, core.Never, core.IfAtMostOneObstacle, core.OnlyWhenNoObstacles}
+
+func TestValidatePath(t *testing.T) {
+	grid := core.Grid(4, 3, validateMatrix)
+	// the paths of the finders are valid.
+	for _, name := range []string{"astar", "biastar"} {
+		for _, move := range validateMoves {
+			finder, err := finders.Create(name, &core.Opt{DiagonalMovement: move})
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := grid.PathCoords(finder.FindPath(grid.NodeID(0, 2), grid.NodeID(3, 2), grid))
+			if err := NewValidator(grid, &core.Opt{DiagonalMovement: move}).Path(path, 0, 2, 3, 2); err != nil {
+				t.Fatalf("%s %s: %v on %v", name, move, err, path)
+			}
+		}
+	}
+
+	type check struct {
+		path   core.DoubleInt32
+		opt    core.Opt
+		reason string // "" when valid
+	}
+	for _, c := range []check{
+		{core.DoubleInt32{{0, 2}, {1, 2}, {2, 1}, {3, 2}}, core.Opt{DiagonalMovement: core.Always}, ""},
+		// the deprecated flags still apply.
+		{core.DoubleInt32{{0, 2}, {1, 2}, {2, 1}, {3, 2}}, core.Opt{AllowDiagonal: true}, "step 2: move from (1, 2) to (2, 1) not allowed by ifAtMostOneObstacle"},
+		{core.DoubleInt32{{0, 2}, {1, 2}, {2, 1}, {3, 2}}, core.Opt{AllowDiagonal: true, DontCrossCorners: true}, "step 2: move from (1, 2) to (2, 1) not allowed by onlyWhenNoObstacles"},
+		{core.DoubleInt32{{0, 0}, {1, 0}, {2, 1}, {3, 2}}, core.Opt{DiagonalMovement: core.IfAtMostOneObstacle}, "step 0: starts at (0, 0), not (0, 2)"},
+		{core.DoubleInt32{{0, 2}, {0, 1}, {0, 0}, {1, 0}, {2, 1}, {3, 2}}, core.Opt{DiagonalMovement: core.IfAtMostOneObstacle}, ""},
+		{core.DoubleInt32{{0, 2}, {0, 1}, {0, 0}, {1, 0}, {2, 1}, {3, 2}}, core.Opt{DiagonalMovement: core.OnlyWhenNoObstacles}, "step 4: move from (1, 0) to (2, 1) not allowed by onlyWhenNoObstacles"},
+		{core.DoubleInt32{{0, 2}, {1, 2}, {2, 1}, {3, 2}}, core.Opt{}, "step 2: move from (1, 2) to (2, 1) not allowed by never"},
+		{core.DoubleInt32{{0, 2}, {1, 2}, {2, 2}, {3, 2}}, core.Opt{}, "step 2: (2, 2) blocked"},
+		{core.DoubleInt32{{0, 2}, {0, 0}, {3, 2}}, core.Opt{}, "step 1: jumps from (0, 2) to (0, 0)"},
+		{core.DoubleInt32{{0, 1}, {3, 2}}, core.Opt{}, "step 0: starts at (0, 1), not (0, 2)"},
+		{core.DoubleInt32{{0, 2}, {0, 1}}, core.Opt{}, "step 1: ends at (0, 1), not (3, 2)"},
+		{core.DoubleInt32{{0, 2}, {-1, 2}, {3, 2}}, core.Opt{}, "step 1: (-1, 2) outside the 4x3 grid"},
+		{core.DoubleInt32{{0, 2}, {1, 2}, {1, 2}, {2, 1}, {3, 2}}, core.Opt{DiagonalMovement: core.Always}, "step 2: stays at (1, 2)"},
+		{core.DoubleInt32{}, core.Opt{}, "step 0: empty path"},
+	} {
+		err := NewValidator(grid, &c.opt).Path(c.path, 0, 2, 3, 2)
+		if (err == nil) != (c.reason == "") || (err != nil && !strings.HasSuffix(err.Error(), c.reason)) {
+			t.Fatalf("%v: %v, expected %q", c.path, err, c.reason)
+		}
+	}
+
+	validator := NewValidator(grid, &core.Opt{DiagonalMovement: core.Always})
+	validator.AllowWaits = true
+	if err := validator.Path(core.DoubleInt32{{0, 2}, {1, 2}, {1, 2}, {2, 1}, {3, 2}}, 0, 2, 3, 2); err != nil {
+		t.Fatalf("wait rejected: %v", err)
+	}
+	// an agent of 2x2 cells cannot stand at (1, 0).
+	if err := NewValidator(grid, &core.Opt{AgentSize: 2}).Path(core.DoubleInt32{{1, 0}}, 1, 0, 1, 0); err == nil {
+		t.Fatalf("agent size ignored")
+	}
+}
+
+func TestValidateTrace(t *testing.T) {
+	grid := core.Grid(4, 3, validateMatrix)
+	validator := NewValidator(grid, &core.Opt{DiagonalMovement: core.OnlyWhenNoObstacles})
+	validator.MaxSpeed = 2
+
+	walk := []TSample{{0.5, 2.5, 0}, {0.5, 0.5, 1}, {3.5, 0.5, 2.5}, {3.5, 2.5, 3.5}}
+	if err := validator.Trace(walk); err != nil {
+		t.Fatal(err)
+	}
+	type check struct {
+		trace  []TSample
+		reason string
+	}
+	for _, c := range []check{
+		{[]TSample{{0.5, 2.5, 0}, {0.5, 0.5, 0.5}}, "step 1: moves 2.000 cells in 0.500s, faster than 2 cells/s"},
+		{[]TSample{{0.5, 2.5, 1}, {0.5, 1.5, 0}}, "step 1: time goes back from 1 to 0"},
+		{[]TSample{{0.5, 2.5, 0}, {2.5, 2.5, 5}}, "step 1: (2.5, 2.5) on a blocked cell"},
+		// through the wall at (1, 1).
+		{[]TSample{{0.5, 1.5, 0}, {2.5, 1.5, 5}}, "step 1: crosses blocked cell (1, 1)"},
+		{[]TSample{{0.5, 0.5, 0}, {3.5, 1.7, 5}}, "step 1: crosses blocked cell (1, 1)"},
+		{[]TSample{{0.5, 0.5, 0}, {3.5, 1.5, 5}}, "step 1: cuts the corner from (1, 0) to (2, 1), not allowed by onlyWhenNoObstacles"},
+		// through the corner between (1, 1) and (2, 2).
+		{[]TSample{{1.5, 2.5, 0}, {2.5, 1.5, 5}}, "step 1: cuts the corner from (1, 2) to (2, 1), not allowed by onlyWhenNoObstacles"},
+		{[]TSample{{0.5, 0.5, 0}, {4.5, 0.5, 5}}, "step 1: (4.5, 0.5) outside the 4x3 grid"},
+		// an infinite timestamp would make any later move slow enough.
+		{[]TSample{{0.5, 0.5, 0}, {1.5, 0.5, math.Inf(1)}, {3.5, 0.5, math.Inf(1)}}, "step 1: (1.5, 0.5) at +Inf is not finite"},
+		{[]TSample{{0.5, 0.5, 0}, {math.NaN(), 0.5, 1}}, "step 1: (NaN, 0.5) at 1 is not finite"},
+	} {
+		err := validator.Trace(c.trace)
+		akLog.FmtPrintln(err)
+		if err == nil || !strings.HasSuffix(err.Error(), c.reason) {
+			t.Fatalf("%v: %v, expected %q", c.trace, err, c.reason)
+		}
+	}
+
+	// the same corner under Always, with a jitter within tolerance.
+	validator.Opt = &core.Opt{DiagonalMovement: core.Always}
+	if err := validator.Trace([]TSample{{1.5, 2.5, 0}, {2.5, 1.5, 0.7}}); err != nil {
+		t.Fatal(err)
+	}
+}
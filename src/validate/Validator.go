@@ -0,0 +1,246 @@
+package validate
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import (
+	"fmt"
+	"math"
+
+	"go-PathFinding/core"
+)
+
+/**
+ * The first rule a path or a trace breaks: Index is its step (or sample)
+ * and Reason says what is wrong with it.
+ */
+type TViolation struct {
+	Index  int
+	Reason string
+}
+
+func (this *TViolation) Error() string {
+	return fmt.Sprintf("validate: step %d: %s", this.Index, this.Reason)
+}
+
+func violation(index int, format string, args ...interface{}) error {
+	return &TViolation{Index: index, Reason: fmt.Sprintf(format, args...)}
+}
+
+/**
+ * Checks paths found by finders, or reported by clients, against a grid
+ * and the options they were searched with: Opt.DiagonalMovement (or the
+ * deprecated AllowDiagonal and DontCrossCorners) and Opt.AgentSize.
+ * AllowWaits accepts repeated cells, as in timed paths.
+ * MaxSpeed (cells per second, 0 for no limit) bounds the movement traces,
+ * with a relative Tolerance for the clock and network jitter.
+ */
+type TValidator struct {
+	Grid       *core.TGrid
+	Opt        *core.Opt
+	AllowWaits bool
+	MaxSpeed   float64
+	Tolerance  float64
+}
+
+/**
+ * @constructor
+ * @param {Grid} grid
+ * @param {Object} opt - The options of the finder, not modified.
+ */
+func NewValidator(grid *core.TGrid, opt *core.Opt) *TValidator {
+	return &TValidator{
+		Grid:      grid,
+		Opt:       opt,
+		Tolerance: 0.05,
+	}
+}
+
+// resolved returns a copy of the options with the diagonal movement set.
+func (this *TValidator) resolved() *core.Opt {
+	var opt core.Opt
+	if this.Opt != nil {
+		opt = *this.Opt
+	}
+	opt.DiagonalMovement = opt.Movement()
+	return &opt
+}
+
+func (this *TValidator) inside(x, y int) bool {
+	return x >= 0 && x < this.Grid.Width() && y >= 0 && y < this.Grid.Height()
+}
+
+// canStep tells whether (x1, y1) is a neighbour of (x0, y0) for the options.
+func (this *TValidator) canStep(opt *core.Opt, x0, y0, x1, y1 int) bool {
+	to := this.Grid.NodeID(x1, y1)
+	for _, id := range this.Grid.Neighbors(this.Grid.NodeID(x0, y0), opt) {
+		if id == to {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Check that the path goes from (sx, sy) to (ex, ey) through walkable
+ * cells only, every step being to a neighbour allowed by the diagonal
+ * movement. Returns nil or a *TViolation.
+ */
+func (this *TValidator) Path(path core.DoubleInt32, sx, sy, ex, ey int) error {
+	if len(path) == 0 {
+		return violation(0, "empty path")
+	}
+	if x, y := int(path[0][0]), int(path[0][1]); x != sx || y != sy {
+		return violation(0, "starts at (%d, %d), not (%d, %d)", x, y, sx, sy)
+	}
+	if x, y := int(path[len(path)-1][0]), int(path[len(path)-1][1]); x != ex || y != ey {
+		return violation(len(path)-1, "ends at (%d, %d), not (%d, %d)", x, y, ex, ey)
+	}
+
+	opt := this.resolved()
+	for i, p := range path {
+		x, y := int(p[0]), int(p[1])
+		if !this.inside(x, y) {
+			return violation(i, "(%d, %d) outside the %dx%d grid", x, y, this.Grid.Width(), this.Grid.Height())
+		}
+		if !this.Grid.IsWalkable(this.Grid.NodeID(x, y), opt) {
+			return violation(i, "(%d, %d) blocked", x, y)
+		}
+		if i == 0 {
+			continue
+		}
+		px, py := int(path[i-1][0]), int(path[i-1][1])
+		dx, dy := x-px, y-py
+		switch {
+		case dx == 0 && dy == 0:
+			if !this.AllowWaits {
+				return violation(i, "stays at (%d, %d)", x, y)
+			}
+		case dx < -1 || dx > 1 || dy < -1 || dy > 1:
+			return violation(i, "jumps from (%d, %d) to (%d, %d)", px, py, x, y)
+		case !this.canStep(opt, px, py, x, y):
+			return violation(i, "move from (%d, %d) to (%d, %d) not allowed by %s", px, py, x, y, opt.DiagonalMovement)
+		}
+	}
+	return nil
+}
+
+/**
+ * A position reported by a client at time T, in seconds. Positions are in
+ * cells: (2.5, 0.5) is the centre of cell (2, 0).
+ */
+type TSample struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	T float64 `json:"t"`
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+/**
+ * Check a movement trace: positions and timestamps are finite,
+ * timestamps never go back, every position is on a walkable cell, the
+ * straight moves between samples cross walkable cells only, and are no
+ * faster than MaxSpeed.
+ * A move through the very corner of four cells is a diagonal step, which
+ * must be allowed by the diagonal movement.
+ */
+func (this *TValidator) Trace(trace []TSample) error {
+	opt := this.resolved()
+	for i, s := range trace {
+		if !finite(s.X) || !finite(s.Y) || !finite(s.T) {
+			return violation(i, "(%g, %g) at %g is not finite", s.X, s.Y, s.T)
+		}
+		x, y := int(math.Floor(s.X)), int(math.Floor(s.Y))
+		if !this.inside(x, y) {
+			return violation(i, "(%g, %g) outside the %dx%d grid", s.X, s.Y, this.Grid.Width(), this.Grid.Height())
+		}
+		if !this.Grid.IsWalkable(this.Grid.NodeID(x, y), opt) {
+			return violation(i, "(%g, %g) on a blocked cell", s.X, s.Y)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := trace[i-1]
+		dt := s.T - prev.T
+		if dt < 0 {
+			return violation(i, "time goes back from %g to %g", prev.T, s.T)
+		}
+		distance := math.Hypot(s.X-prev.X, s.Y-prev.Y)
+		if this.MaxSpeed > 0 && distance > this.MaxSpeed*(1+this.Tolerance)*dt+1e-9 {
+			return violation(i, "moves %.3f cells in %.3fs, faster than %g cells/s", distance, dt, this.MaxSpeed)
+		}
+		if reason := this.crossing(opt, prev, s); reason != "" {
+			return violation(i, "%s", reason)
+		}
+	}
+	return nil
+}
+
+// crossing walks the cells under the segment from a to b, after Amanatides
+// and Woo, and describes the first one that may not be entered.
+func (this *TValidator) crossing(opt *core.Opt, a, b TSample) string {
+	cx, cy := int(math.Floor(a.X)), int(math.Floor(a.Y))
+	tx, ty := int(math.Floor(b.X)), int(math.Floor(b.Y))
+	dx, dy := b.X-a.X, b.Y-a.Y
+
+	stepX, stepY := 1, 1
+	if dx < 0 {
+		stepX = -1
+	}
+	if dy < 0 {
+		stepY = -1
+	}
+	// parameter of the segment at the next vertical and horizontal lines.
+	nextX, nextY := math.Inf(1), math.Inf(1)
+	deltaX, deltaY := math.Inf(1), math.Inf(1)
+	if dx != 0 {
+		edge := float64(cx)
+		if stepX > 0 {
+			edge++
+		}
+		nextX, deltaX = (edge-a.X)/dx, math.Abs(1/dx)
+	}
+	if dy != 0 {
+		edge := float64(cy)
+		if stepY > 0 {
+			edge++
+		}
+		nextY, deltaY = (edge-a.Y)/dy, math.Abs(1/dy)
+	}
+
+	const epsilon = 1e-12
+	for steps := abs(tx-cx) + abs(ty-cy); steps > 0 && (cx != tx || cy != ty); steps-- {
+		x, y := cx, cy
+		switch {
+		case nextX < nextY-epsilon:
+			cx += stepX
+			nextX += deltaX
+		case nextY < nextX-epsilon:
+			cy += stepY
+			nextY += deltaY
+		default:
+			cx += stepX
+			cy += stepY
+			nextX += deltaX
+			nextY += deltaY
+		}
+		if !this.inside(cx, cy) || !this.Grid.IsWalkable(this.Grid.NodeID(cx, cy), opt) {
+			return fmt.Sprintf("crosses blocked cell (%d, %d)", cx, cy)
+		}
+		if cx != x && cy != y && !this.canStep(opt, x, y, cx, cy) {
+			return fmt.Sprintf("cuts the corner from (%d, %d) to (%d, %d), not allowed by %s", x, y, cx, cy, opt.DiagonalMovement)
+		}
+	}
+	return ""
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,131 @@
+package visgraph
+
+/*
+	by stefan 2572915286@qq.com
+*/
+
+import "math"
+
+const epsilon = 1e-9
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+func dist(a, b Point) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+func cross(o, a, b Point) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+func samePoint(a, b Point) bool {
+	return math.Abs(a.X-b.X) < epsilon && math.Abs(a.Y-b.Y) < epsilon
+}
+
+/**
+ * Twice the signed area of the polygon, positive when counter-clockwise
+ * in a y-up frame.
+ */
+func signedArea2(verts []Point) float64 {
+	var sum float64
+	for i := range verts {
+		a := verts[i]
+		b := verts[(i+1)%len(verts)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return sum
+}
+
+/**
+ * Whether segments p1p2 and q1q2 cross at a single point lying strictly
+ * inside both of them.
+ */
+func properIntersect(p1, p2, q1, q2 Point) bool {
+	d1 := cross(q1, q2, p1)
+	d2 := cross(q1, q2, p2)
+	d3 := cross(p1, p2, q1)
+	d4 := cross(p1, p2, q2)
+	return ((d1 > epsilon && d2 < -epsilon) || (d1 < -epsilon && d2 > epsilon)) &&
+		((d3 > epsilon && d4 < -epsilon) || (d3 < -epsilon && d4 > epsilon))
+}
+
+/**
+ * Whether p lies on segment ab, excluding both ends.
+ */
+func onSegment(p, a, b Point) bool {
+	if samePoint(p, a) || samePoint(p, b) {
+		return false
+	}
+	if math.Abs(cross(a, b, p)) > epsilon*math.Max(1, dist(a, b)) {
+		return false
+	}
+	return p.X >= math.Min(a.X, b.X)-epsilon && p.X <= math.Max(a.X, b.X)+epsilon &&
+		p.Y >= math.Min(a.Y, b.Y)-epsilon && p.Y <= math.Max(a.Y, b.Y)+epsilon
+}
+
+/**
+ * Whether p lies strictly inside the polygon (even-odd rule). Points on
+ * the boundary are reported outside.
+ */
+func insidePolygon(p Point, verts []Point) bool {
+	var inside bool
+	for i, j := 0, len(verts)-1; i < len(verts); j, i = i, i+1 {
+		a := verts[i]
+		b := verts[j]
+		if onSegment(p, a, b) || samePoint(p, a) {
+			return false
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) &&
+			p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+/**
+ * Offset a simple polygon outwards by radius, using mitred corners.
+ * Reflex corners are moved inwards by the same rule, which keeps the
+ * edges parallel to the original ones.
+ */
+func inflate(verts []Point, radius float64) []Point {
+	if radius <= 0 {
+		return append([]Point{}, verts...)
+	}
+	var orientation = 1.0
+	if signedArea2(verts) < 0 {
+		orientation = -1
+	}
+	n := len(verts)
+	out := make([]Point, n)
+	for i := range verts {
+		prev := verts[(i+n-1)%n]
+		cur := verts[i]
+		next := verts[(i+1)%n]
+		n1 := outwardNormal(prev, cur, orientation)
+		n2 := outwardNormal(cur, next, orientation)
+		bx := n1.X + n2.X
+		by := n1.Y + n2.Y
+		// n1 . bisector = 1 + cos(angle between normals)
+		scale := radius / (1 + n1.X*n2.X + n1.Y*n2.Y)
+		if math.IsInf(scale, 0) || math.IsNaN(scale) {
+			bx, by, scale = n1.X, n1.Y, radius
+		}
+		out[i] = Point{X: cur.X + bx*scale, Y: cur.Y + by*scale}
+	}
+	return out
+}
+
+func outwardNormal(a, b Point, orientation float64) Point {
+	dx := b.X - a.X
+	dy := b.Y - a.Y
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return Point{}
+	}
+	// for a counter-clockwise polygon the outside is on the right.
+	return Point{X: orientation * dy / l, Y: -orientation * dx / l}
+}
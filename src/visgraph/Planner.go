@@ -0,0 +1,382 @@
+package visgraph
+
+/*
+	by stefan 2572915286@qq.com
+	Visibility-graph planner for polygonal obstacle maps.
+*/
+
+import (
+	"go-PathFinding/core"
+	"go-PathFinding/finders/AStarFinder"
+	"math"
+)
+
+type obstacle struct {
+	id       int
+	verts    []Point // inflated outline
+	vertices []int   // convex vertex ids
+}
+
+type vertex struct {
+	id       int
+	pos      Point
+	obstacle int
+}
+
+type TVisibilityPlanner struct {
+	FinderOpt *core.Opt
+	Radius    float64
+	finder    *AStarFinder.TAStarFinder
+
+	obstacles  map[int]*obstacle
+	vertices   map[int]*vertex
+	edges      map[int]map[int]float64
+	nextObsId  int
+	nextVertId int
+}
+
+/**
+ * Visibility-graph planner.
+ * @constructor
+ * @param {Object} opt
+ * @param {function} opt.heuristic Heuristic function to estimate the
+ *     distance (defaults to euclidean, the only admissible one for
+ *     any-angle paths).
+ * @param {number} opt.weight Weight to apply to the heuristic.
+ * @param {number} radius Agent radius every obstacle is inflated by.
+ */
+func CreateVisibilityPlanner(opt *core.Opt, radius float64) (this *TVisibilityPlanner) {
+	this = &TVisibilityPlanner{
+		FinderOpt: opt,
+		Radius:    radius,
+		obstacles: map[int]*obstacle{},
+		vertices:  map[int]*vertex{},
+		edges:     map[int]map[int]float64{},
+	}
+	if opt.Heuristic == nil {
+		this.FinderOpt.Heuristic = core.Euclidean
+	}
+	this.finder = AStarFinder.CreateAStarFinder(this.FinderOpt)
+	return
+}
+
+/**
+ * Add a polygon obstacle and update the graph around it.
+ * Only the edges crossing the new obstacle are dropped and only the new
+ * convex vertices are linked, the rest of the graph is kept.
+ * @param {[]Point} verts - The outline, in either orientation.
+ * @return {number} The obstacle id, for RemoveObstacle.
+ */
+func (this *TVisibilityPlanner) AddObstacle(verts []Point) int {
+	obs := &obstacle{
+		id:    this.nextObsId,
+		verts: inflate(verts, this.Radius),
+	}
+	this.nextObsId++
+
+	// drop the edges the new obstacle hides.
+	for a, links := range this.edges {
+		for b := range links {
+			if a < b && this.blockedBy(this.vertices[a].pos, this.vertices[b].pos, obs.verts) {
+				this.unlink(a, b)
+			}
+		}
+	}
+
+	// corners of other obstacles swallowed by the new one.
+	for _, other := range this.obstacles {
+		kept := other.vertices[:0]
+		for _, vid := range other.vertices {
+			if insidePolygon(this.vertices[vid].pos, obs.verts) {
+				this.removeVertex(vid)
+				continue
+			}
+			kept = append(kept, vid)
+		}
+		other.vertices = kept
+	}
+
+	this.obstacles[obs.id] = obs
+	var orientation = 1.0
+	if signedArea2(obs.verts) < 0 {
+		orientation = -1
+	}
+	n := len(obs.verts)
+	for i, pos := range obs.verts {
+		if orientation*cross(obs.verts[(i+n-1)%n], pos, obs.verts[(i+1)%n]) <= epsilon {
+			// reflex or flat vertices are never on a shortest path.
+			continue
+		}
+		if this.insideAny(pos) {
+			continue
+		}
+		v := &vertex{id: this.nextVertId, pos: pos, obstacle: obs.id}
+		this.nextVertId++
+		this.vertices[v.id] = v
+		obs.vertices = append(obs.vertices, v.id)
+	}
+
+	for _, id := range obs.vertices {
+		v := this.vertices[id]
+		for _, other := range this.vertices {
+			if other.id != id && this.Visible(v.pos, other.pos) {
+				this.link(id, other.id)
+			}
+		}
+	}
+	return obs.id
+}
+
+/**
+ * Remove an obstacle and update the graph around it.
+ * Only the vertex pairs whose segment crossed the removed obstacle are
+ * checked again.
+ */
+func (this *TVisibilityPlanner) RemoveObstacle(id int) {
+	obs := this.obstacles[id]
+	if obs == nil {
+		return
+	}
+	delete(this.obstacles, id)
+	for _, vid := range obs.vertices {
+		this.removeVertex(vid)
+	}
+
+	// vertices covered by the removed obstacle were never created, so a
+	// remaining obstacle may now expose some of its corners: rebuild those.
+	for _, other := range this.obstacles {
+		this.restoreCorners(other)
+	}
+
+	for a, va := range this.vertices {
+		for b, vb := range this.vertices {
+			if a >= b || this.edges[a][b] != 0 {
+				continue
+			}
+			if this.blockedBy(va.pos, vb.pos, obs.verts) && this.Visible(va.pos, vb.pos) {
+				this.link(a, b)
+			}
+		}
+	}
+}
+
+/**
+ * Create the convex vertices of obs that were hidden inside another
+ * obstacle and are free again.
+ */
+func (this *TVisibilityPlanner) restoreCorners(obs *obstacle) {
+	var orientation = 1.0
+	if signedArea2(obs.verts) < 0 {
+		orientation = -1
+	}
+	n := len(obs.verts)
+	for i, pos := range obs.verts {
+		if orientation*cross(obs.verts[(i+n-1)%n], pos, obs.verts[(i+1)%n]) <= epsilon {
+			continue
+		}
+		known := false
+		for _, vid := range obs.vertices {
+			if samePoint(this.vertices[vid].pos, pos) {
+				known = true
+				break
+			}
+		}
+		if known || this.insideAny(pos) {
+			continue
+		}
+		v := &vertex{id: this.nextVertId, pos: pos, obstacle: obs.id}
+		this.nextVertId++
+		this.vertices[v.id] = v
+		obs.vertices = append(obs.vertices, v.id)
+		for _, other := range this.vertices {
+			if other.id != v.id && this.Visible(v.pos, other.pos) {
+				this.link(v.id, other.id)
+			}
+		}
+	}
+}
+
+func (this *TVisibilityPlanner) removeVertex(id int) {
+	for other := range this.edges[id] {
+		this.unlink(id, other)
+	}
+	delete(this.vertices, id)
+	delete(this.edges, id)
+}
+
+func (this *TVisibilityPlanner) link(a, b int) {
+	d := dist(this.vertices[a].pos, this.vertices[b].pos)
+	if this.edges[a] == nil {
+		this.edges[a] = map[int]float64{}
+	}
+	if this.edges[b] == nil {
+		this.edges[b] = map[int]float64{}
+	}
+	// a zero length would read as "no edge".
+	d = math.Max(d, epsilon)
+	this.edges[a][b] = d
+	this.edges[b][a] = d
+}
+
+func (this *TVisibilityPlanner) unlink(a, b int) {
+	delete(this.edges[a], b)
+	delete(this.edges[b], a)
+}
+
+func (this *TVisibilityPlanner) insideAny(p Point) bool {
+	for _, obs := range this.obstacles {
+		if insidePolygon(p, obs.verts) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Whether segment pq goes through the interior of the polygon.
+ */
+func (this *TVisibilityPlanner) blockedBy(p, q Point, verts []Point) bool {
+	n := len(verts)
+	for i := 0; i < n; i++ {
+		a := verts[i]
+		b := verts[(i+1)%n]
+		if properIntersect(p, q, a, b) {
+			return true
+		}
+	}
+	// the segment may enter through a vertex or run along a diagonal.
+	var cuts = []Point{p, q}
+	for _, v := range verts {
+		if onSegment(v, p, q) {
+			cuts = append(cuts, v)
+		}
+	}
+	for i := 0; i < len(cuts); i++ {
+		for j := i + 1; j < len(cuts); j++ {
+			mid := Point{X: (cuts[i].X + cuts[j].X) / 2, Y: (cuts[i].Y + cuts[j].Y) / 2}
+			if insidePolygon(mid, verts) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/**
+ * Whether the straight segment pq avoids every obstacle.
+ */
+func (this *TVisibilityPlanner) Visible(p, q Point) bool {
+	for _, obs := range this.obstacles {
+		if this.blockedBy(p, q, obs.verts) {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * The number of vertices and undirected edges of the graph.
+ */
+func (this *TVisibilityPlanner) Size() (vertices int, edges int) {
+	for _, links := range this.edges {
+		edges += len(links)
+	}
+	return len(this.vertices), edges / 2
+}
+
+/**
+ * Estimate the distance between two points with the finder heuristic.
+ */
+func (this *TVisibilityPlanner) estimate(a, b Point) float64 {
+	return this.FinderOpt.Heuristic(math.Abs(a.X-b.X), math.Abs(a.Y-b.Y))
+}
+
+const (
+	startNodeId = core.NodeID(-1)
+	goalNodeId  = core.NodeID(-2)
+)
+
+/**
+ * The visibility graph of the planner, extended for one query with the
+ * start and goal points, linked to every vertex they can see.
+ */
+type queryGraph struct {
+	planner    *TVisibilityPlanner
+	start      Point
+	goal       Point
+	startLinks core.ArrayNodeID
+	goalLinks  map[core.NodeID]bool
+}
+
+func (this *queryGraph) pos(id core.NodeID) Point {
+	switch id {
+	case startNodeId:
+		return this.start
+	case goalNodeId:
+		return this.goal
+	}
+	return this.planner.vertices[int(id)].pos
+}
+
+func (this *queryGraph) IsWalkable(id core.NodeID, opt *core.Opt) bool {
+	return id == startNodeId || id == goalNodeId || this.planner.vertices[int(id)] != nil
+}
+
+func (this *queryGraph) Neighbors(id core.NodeID, opt *core.Opt) core.ArrayNodeID {
+	if id == startNodeId {
+		return this.startLinks
+	}
+	var ids = core.ArrayNodeID{}
+	for other := range this.planner.edges[int(id)] {
+		ids = append(ids, core.NodeID(other))
+	}
+	if this.goalLinks[id] {
+		ids = append(ids, goalNodeId)
+	}
+	return ids
+}
+
+func (this *queryGraph) Cost(from, to core.NodeID) float64 {
+	return dist(this.pos(from), this.pos(to))
+}
+
+func (this *queryGraph) Heuristic(from, to core.NodeID, opt *core.Opt) float64 {
+	return this.planner.estimate(this.pos(from), this.pos(to))
+}
+
+/**
+ * Find and return the shortest path between two points.
+ * Start and goal are linked to the graph for this search only.
+ * @return {[]Point} The waypoints, including both start and goal;
+ *     empty if there is no path.
+ */
+func (this *TVisibilityPlanner) FindPath(start, goal Point) []Point {
+	if this.insideAny(start) || this.insideAny(goal) {
+		return []Point{}
+	}
+	if this.Visible(start, goal) {
+		return []Point{start, goal}
+	}
+
+	graph := &queryGraph{
+		planner:    this,
+		start:      start,
+		goal:       goal,
+		startLinks: core.ArrayNodeID{},
+		goalLinks:  map[core.NodeID]bool{},
+	}
+	for id, v := range this.vertices {
+		if this.Visible(start, v.pos) {
+			graph.startLinks = append(graph.startLinks, core.NodeID(id))
+		}
+		if this.Visible(v.pos, goal) {
+			graph.goalLinks[core.NodeID(id)] = true
+		}
+	}
+
+	var path = []Point{}
+	for _, id := range this.finder.FindPath(startNodeId, goalNodeId, graph) {
+		path = append(path, graph.pos(id))
+	}
+	return path
+}
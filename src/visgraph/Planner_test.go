@@ -0,0 +1,85 @@
+package visgraph
+
+import (
+	"go-PathFinding/core"
+	"math"
+	"testing"
+
+	"github.com/Peakchen/xgameCommon/akLog"
+)
+
+func square(x, y, size float64) []Point {
+	return []Point{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}}
+}
+
+func pathLength(path []Point) float64 {
+	var length float64
+	for i := 1; i < len(path); i++ {
+		length += dist(path[i-1], path[i])
+	}
+	return length
+}
+
+func TestVisibilityPlanner(t *testing.T) {
+	planner := CreateVisibilityPlanner(&core.Opt{}, 0)
+	id := planner.AddObstacle(square(2, 2, 2))
+
+	path := planner.FindPath(Point{0, 3}, Point{6, 3})
+	akLog.FmtPrintln("result: ", path)
+	expected := 2*math.Hypot(2, 1) + 2
+	if len(path) != 4 || math.Abs(pathLength(path)-expected) > 1e-9 {
+		t.Fatalf("unexpected path: %v", path)
+	}
+
+	planner.RemoveObstacle(id)
+	if path = planner.FindPath(Point{0, 3}, Point{6, 3}); len(path) != 2 {
+		t.Fatalf("unexpected path after removal: %v", path)
+	}
+	if vertices, edges := planner.Size(); vertices != 0 || edges != 0 {
+		t.Fatalf("graph not emptied: %v vertices, %v edges", vertices, edges)
+	}
+}
+
+func TestVisibilityPlannerIncremental(t *testing.T) {
+	obstacles := [][]Point{
+		square(2, 0, 1),
+		square(2, 2, 1),
+		{{5, 1}, {7, 1}, {6, 3}},
+		square(8, -1, 2),
+	}
+	incremental := CreateVisibilityPlanner(&core.Opt{}, 0.25)
+	ids := []int{}
+	for _, obs := range obstacles {
+		ids = append(ids, incremental.AddObstacle(obs))
+	}
+	extra := incremental.AddObstacle(square(3.5, 0.5, 1))
+	incremental.RemoveObstacle(extra)
+	incremental.RemoveObstacle(ids[1])
+
+	rebuilt := CreateVisibilityPlanner(&core.Opt{}, 0.25)
+	for i, obs := range obstacles {
+		if i != 1 {
+			rebuilt.AddObstacle(obs)
+		}
+	}
+
+	v1, e1 := incremental.Size()
+	v2, e2 := rebuilt.Size()
+	if v1 != v2 || e1 != e2 {
+		t.Fatalf("incremental graph %v/%v differs from rebuilt %v/%v", v1, e1, v2, e2)
+	}
+	start, goal := Point{0, 1.5}, Point{11, 0}
+	l1 := pathLength(incremental.FindPath(start, goal))
+	l2 := pathLength(rebuilt.FindPath(start, goal))
+	if l1 == 0 || math.Abs(l1-l2) > 1e-9 {
+		t.Fatalf("path lengths differ: %v, %v", l1, l2)
+	}
+}
+
+func TestVisibilityPlannerBlocked(t *testing.T) {
+	planner := CreateVisibilityPlanner(&core.Opt{}, 0)
+	planner.AddObstacle(square(0, 0, 4))
+	if path := planner.FindPath(Point{1, 1}, Point{6, 6}); len(path) != 0 {
+		t.Fatalf("path found from inside an obstacle: %v", path)
+	}
+}